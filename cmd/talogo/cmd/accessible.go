@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// accessibleStatusEvery is how often the timer prints a status line in
+// accessible mode
+const accessibleStatusEvery = 5 * time.Minute
+
+// Update handles msg and, in accessible mode, where the view is not
+// drawn, prints the notices below the timer when they change and a status
+// line every accessibleStatusEvery
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.update(msg)
+	next, ok := updated.(model)
+	if !ok || !next.accessible || next.quitting || next.takenOver {
+		return updated, cmd
+	}
+
+	var lines string
+	if _, tick := msg.(tickMsg); tick {
+		// Ticks only change the time away of the prompt, not worth
+		// repeating it, or end the countdown, a pomodoro, a break or the
+		// session
+		if next.expired != m.expired || next.onBreak != m.onBreak || next.asking != m.asking {
+			next.spoken = next.notice()
+			lines = next.spoken
+			if lines == "" {
+				lines = next.statusLine() + "\n"
+			}
+		} else if !next.paused && next.elapsed >= next.nextStatus {
+			next.nextStatus += accessibleStatusEvery
+			lines = next.statusLine() + "\n"
+		}
+	} else if notice := next.notice(); notice != next.spoken {
+		lines = notice
+		if notice == "" {
+			// Back to tracking after a pause or a prompt
+			lines = next.statusLine() + "\n"
+		}
+		next.spoken = notice
+	}
+	if lines == "" {
+		return next, cmd
+	}
+	return next, tea.Batch(cmd, printCmd(lines))
+}
+
+// statusLine returns the task and the time tracked as a sentence, e.g.
+// "Tracking work / mail, 1 hour 5 minutes"
+func (m model) statusLine() string {
+	return fmt.Sprintf(tr("Tracking %s, %s"), strings.Join(m.titles, " / "), spokenDuration(m.elapsed))
+}
+
+// spokenDuration returns d in hours and minutes written out, which screen
+// readers read better than "1h5m0s"
+func spokenDuration(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	if hours == 0 && minutes == 0 {
+		return tr("less than a minute")
+	}
+
+	var parts []string
+	switch {
+	case hours == 1:
+		parts = append(parts, tr("1 hour"))
+	case hours > 1:
+		parts = append(parts, fmt.Sprintf(tr("%d hours"), hours))
+	}
+	switch {
+	case minutes == 1:
+		parts = append(parts, tr("1 minute"))
+	case minutes > 1:
+		parts = append(parts, fmt.Sprintf(tr("%d minutes"), minutes))
+	}
+	return strings.Join(parts, " ")
+}
+
+// printCmd writes s to the terminal, for programs run without a renderer
+func printCmd(s string) tea.Cmd {
+	return func() tea.Msg {
+		fmt.Print(s)
+		return nil
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// anonymizer replaces the titles and tags of records with pseudonyms,
+// the same for every record with the same ones: top level tasks are
+// projects named by letter (Project A), their subtasks numbered under
+// their parent (Task 3) and tags numbered too (tag2). The tags talogo
+// writes itself, such as parallel or pomodoro:3, are kept.
+type anonymizer struct {
+	titles   map[string]string // Task path -> pseudonym of its last title
+	subtasks map[string]int    // Task path -> subtasks named so far
+	tags     map[string]string
+}
+
+// newAnonymizer returns an anonymizer naming the tasks and tags of records
+// in order of appearance, so the pseudonyms of a log are the same
+// whatever part of it is exported
+func newAnonymizer(records []Record) *anonymizer {
+	a := &anonymizer{
+		titles:   make(map[string]string),
+		subtasks: make(map[string]int),
+		tags:     make(map[string]string),
+	}
+	for _, record := range records {
+		a.record(record)
+	}
+	return a
+}
+
+// record returns record with pseudonyms for its titles and tags, and
+// without its notes, host and user
+func (a *anonymizer) record(record Record) Record {
+	titles := sanitizedTitles(record.Titles)
+	anonymous := make([]string, len(titles))
+	for i := range titles {
+		path := strings.Join(titles[:i+1], "\x00")
+		name, ok := a.titles[path]
+		if !ok {
+			parent := strings.Join(titles[:i], "\x00")
+			a.subtasks[parent]++
+			if i == 0 {
+				name = "Project " + letterName(a.subtasks[parent])
+			} else {
+				name = fmt.Sprintf("Task %d", a.subtasks[parent])
+			}
+			a.titles[path] = name
+		}
+		anonymous[i] = name
+	}
+
+	var tags []string
+	for _, tag := range record.Tags {
+		if tag == parallelTag || tag == autoStoppedTag || tag == billedTag || tag == syncedTag ||
+			tag == pomodoroTag || pomodoroNumber([]string{tag}) > 0 {
+			tags = append(tags, tag)
+			continue
+		}
+		name, ok := a.tags[tag]
+		if !ok {
+			name = fmt.Sprintf("tag%d", len(a.tags)+1)
+			a.tags[tag] = name
+		}
+		tags = append(tags, name)
+	}
+
+	record.Titles = anonymous
+	record.Tags = tags
+	record.Notes = ""
+	record.Host = ""
+	record.User = ""
+	return record
+}
+
+// letterName returns the letters naming the nth project or spreadsheet
+// column, counted from 1: A to Z, then AA, AB and so on
+func letterName(n int) string {
+	name := ""
+	for ; n > 0; n = (n - 1) / 26 {
+		name = string(rune('A'+(n-1)%26)) + name
+	}
+	return name
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	backupCmdLogFile string
+	backupCmdDir     string
+	backupCmdKeep    int
+)
+
+// defaultBackupKeep is the number of backups kept when not configured
+const defaultBackupKeep = 10
+
+// backupTimeLayout is the layout of the timestamps naming backups, which
+// sort lexicographically in time order
+const backupTimeLayout = "20060102T150405.000000000"
+
+// backupCmd defines the backup subcommand
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Copy the log into the backups directory, keeping the last N copies",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := loadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+
+		settings := config.Backup
+		if backupCmdDir != "" {
+			settings.Dir = backupCmdDir
+		}
+		if backupCmdKeep > 0 {
+			settings.Keep = backupCmdKeep
+		}
+
+		path, err := backupLog(logPath(backupCmdLogFile), settings)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error backing up log: %v\n"), err)
+			os.Exit(1)
+		}
+		printInfo("Backed up %s to %s\n", backupCmdLogFile, path)
+	},
+}
+
+func init() {
+	backupCmd.Flags().StringVarP(&backupCmdLogFile, "file", "f", defaultLogFile(), "Log file to back up")
+	backupCmd.Flags().StringVar(&backupCmdDir, "dir", "", "Backups directory (default from config, or backups in the data directory)")
+	backupCmd.Flags().IntVar(&backupCmdKeep, "keep", 0, fmt.Sprintf("Number of backups to keep (default from config, or %d)", defaultBackupKeep))
+	rootCmd.AddCommand(backupCmd)
+}
+
+// backupLog copies logFile to a timestamped file in the backups directory
+// and removes the oldest backups of the same log beyond the retention
+// limit. Each log has its own subdirectory, named after the log and a
+// hash of its absolute path, so logs with the same name in different
+// directories don't share their backups. It returns the path of the new
+// backup.
+func backupLog(logFile string, settings BackupConfig) (string, error) {
+	dir := settings.Dir
+	if dir == "" {
+		dir = filepath.Join(dataDir(), "backups")
+	}
+	keep := settings.Keep
+	if keep <= 0 {
+		keep = defaultBackupKeep
+	}
+
+	absLogFile, err := filepath.Abs(logFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve log path: %v", err)
+	}
+	ext := filepath.Ext(logFile)
+	prefix := strings.TrimSuffix(filepath.Base(logFile), ext) + "-"
+	dir = filepath.Join(dir, prefix+sha256Hex([]byte(absLogFile))[:8])
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create backups directory: %v", err)
+	}
+
+	path := filepath.Join(dir, prefix+time.Now().Format(backupTimeLayout)+ext)
+	if err := copyFile(logFile, path); err != nil {
+		return "", err
+	}
+
+	// Timestamps sort lexicographically, oldest first. Only the names
+	// made of the prefix, a timestamp and the extension are backups.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to list backups: %v", err)
+	}
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		timestamp, ok := strings.CutPrefix(name, prefix)
+		if ok {
+			timestamp, ok = strings.CutSuffix(timestamp, ext)
+		}
+		if _, err := time.Parse(backupTimeLayout, timestamp); ok && err == nil && entry.Type().IsRegular() {
+			backups = append(backups, name)
+		}
+	}
+	sort.Strings(backups)
+	for len(backups) > keep {
+		if err := os.Remove(filepath.Join(dir, backups[0])); err != nil {
+			return "", fmt.Errorf("failed to remove old backup: %v", err)
+		}
+		backups = backups[1:]
+	}
+
+	return path, nil
+}
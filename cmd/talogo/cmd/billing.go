@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// Tags marking sessions already accounted for outside talogo
+const (
+	// billedTag marks the records of sessions invoiced to a client
+	billedTag = "billed"
+	// syncedTag marks the records of sessions pushed to an external time
+	// tracker, such as with 'talogo harvest'
+	syncedTag = "synced"
+)
+
+var (
+	markCmdLogFile string
+	markCmdFrom    string
+	markCmdTo      string
+	markCmdTasks   []string
+	markCmdUndo    bool
+)
+
+// markCmd defines the mark subcommand
+var markCmd = &cobra.Command{
+	Use:   "mark billed|synced [ID...]",
+	Short: "Mark sessions as billed or synced, so they are not invoiced or pushed twice",
+	Long: `Mark sessions as billed or synced, by id (unique id prefixes are
+accepted) or with the sessions of the days between --from and --to,
+optionally of some tasks only. The mark is a tag of the sessions, and
+--undo removes it.
+
+'talogo report --mark-billed' marks the sessions of a report as billed,
+and harvest, clockify and jira mark the sessions they push as synced.
+Reports, summaries and exports leave billed sessions out with --unbilled:
+
+  talogo report --from 2026-09-01 --to 2026-09-30 --unbilled --mark-billed`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		tag := args[0]
+		if tag != billedTag && tag != syncedTag {
+			fmt.Fprintf(os.Stderr, tr("Error marking sessions: unknown mark %q (expected billed or synced)\n"), tag)
+			os.Exit(1)
+		}
+		if len(args) == 1 && markCmdFrom == "" && markCmdTo == "" && len(markCmdTasks) == 0 {
+			fmt.Fprintln(os.Stderr, tr("Error marking sessions: give the ids of the sessions or select them with --from, --to or --task"))
+			os.Exit(1)
+		}
+
+		selected, err := selectMarkedRecords(markCmdLogFile, args[1:], markCmdFrom, markCmdTo, markCmdTasks)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error marking sessions: %v\n"), err)
+			os.Exit(1)
+		}
+		count, err := markRecords(markCmdLogFile, tag, selected, markCmdUndo)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error marking sessions: %v\n"), err)
+			os.Exit(1)
+		}
+		if markCmdUndo {
+			printInfo("Unmarked %d sessions as %s\n", count, tag)
+		} else {
+			printInfo("Marked %d sessions as %s\n", count, tag)
+		}
+	},
+}
+
+func init() {
+	markCmd.Flags().StringVarP(&markCmdLogFile, "file", "f", defaultLogFile(), "Log file to rewrite")
+	markCmd.Flags().StringVar(&markCmdFrom, "from", "", "Mark the sessions of the days from this date (YYYY-MM-DD)")
+	markCmd.Flags().StringVar(&markCmdTo, "to", "", "Mark the sessions of the days up to this date (YYYY-MM-DD)")
+	markCmd.Flags().StringArrayVar(&markCmdTasks, "task", nil, "Only mark a task and its subtasks (e.g. \"work\" or \"work/project-x\")")
+	markCmd.Flags().BoolVar(&markCmdUndo, "undo", false, "Remove the mark instead of adding it")
+	rootCmd.AddCommand(markCmd)
+}
+
+// isBilled reports whether record was marked as billed
+func isBilled(record Record) bool {
+	for _, tag := range record.Tags {
+		if tag == billedTag {
+			return true
+		}
+	}
+	return false
+}
+
+// selectMarkedRecords returns the keys of the records of logFile with the
+// given ids or, without ids, of those of the days between from and to
+// (open ends if empty) logged under any of tasks (all if none)
+func selectMarkedRecords(logFile string, ids []string, from, to string, tasks []string) (map[string]bool, error) {
+	records, err := readRecordsStrict(logFile)
+	if err != nil {
+		return nil, err
+	}
+	selected := make(map[string]bool)
+	if len(ids) > 0 {
+		for _, id := range ids {
+			i, err := findRecord(records, id)
+			if err != nil {
+				return nil, err
+			}
+			selected[recordKey(records[i])] = true
+		}
+		return selected, nil
+	}
+
+	if records, err = recordsBetween(records, from, to); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if len(tasks) == 0 || record.MatchesTask(tasks) {
+			selected[recordKey(record)] = true
+		}
+	}
+	return selected, nil
+}
+
+// markRecords adds tag to the records of logFile whose keys are selected,
+// or removes it if undo, and returns how many records changed
+func markRecords(logFile, tag string, selected map[string]bool, undo bool) (int, error) {
+	if len(selected) == 0 {
+		return 0, nil
+	}
+	records, err := readRecordsStrict(logFile)
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for i, record := range records {
+		if !selected[recordKey(record)] {
+			continue
+		}
+		tags := withTag(record.Tags, tag)
+		if undo {
+			tags = withoutTag(record.Tags, tag)
+		}
+		if len(tags) != len(record.Tags) {
+			records[i].Tags = tags
+			changed++
+		}
+	}
+	if changed == 0 {
+		return 0, nil
+	}
+	return changed, rewriteRecords(logFile, records)
+}
+
+// unbilledRecords returns the records not marked as billed
+func unbilledRecords(records []Record) []Record {
+	var unbilled []Record
+	for _, record := range records {
+		if !isBilled(record) {
+			unbilled = append(unbilled, record)
+		}
+	}
+	return unbilled
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// breakTasks are the titles of the tasks the breaks of the timer are
+// logged to, nil for breaks not logged
+type breakTasks struct {
+	pause []string // Pauses taken with p
+	short []string // Short pomodoro breaks
+	long  []string // Long pomodoro breaks
+}
+
+// configuredBreakTasks returns the break tasks of the breaks table of
+// config, with aliases expanded. Pomodoro breaks without a task of their
+// own take the one of pauses.
+func configuredBreakTasks(config *Config) (breakTasks, error) {
+	var tasks breakTasks
+	settings := []struct {
+		name   string
+		value  string
+		titles *[]string
+	}{
+		{"task", config.Breaks.Task, &tasks.pause},
+		{"short_task", config.Breaks.ShortTask, &tasks.short},
+		{"long_task", config.Breaks.LongTask, &tasks.long},
+	}
+	for _, s := range settings {
+		if s.value == "" {
+			continue
+		}
+		titles, err := expandTitles(strings.Split(strings.Trim(s.value, "/"), "/"))
+		if err == nil {
+			titles, err = checkTitles(titles)
+		}
+		if err != nil {
+			return tasks, fmt.Errorf("invalid breaks %s %q in config: %v", s.name, s.value, err)
+		}
+		*s.titles = titles
+	}
+	if tasks.short == nil {
+		tasks.short = tasks.pause
+	}
+	if tasks.long == nil {
+		tasks.long = tasks.pause
+	}
+	return tasks, nil
+}
+
+// logBreak writes the pause of the timer until now as a session of the
+// task titles, if any. Failures are shown below the timer.
+func (m model) logBreak(titles []string, now time.Time) model {
+	if len(titles) == 0 {
+		return m
+	}
+	records, err := m.logAway(titles, now)
+	if err != nil {
+		m.err = err
+		return m
+	}
+	m.logged = append(m.logged, records...)
+	return m
+}
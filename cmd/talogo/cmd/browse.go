@@ -0,0 +1,363 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+var (
+	browseCmdLogFile string
+	browseCmdFrom    string
+	browseCmdTo      string
+)
+
+// browseCmd defines the browse subcommand
+var browseCmd = &cobra.Command{
+	Use:   "browse",
+	Short: "Browse the summary of a period as an interactive tree",
+	Long: `Browse the summary of the time tracked between --from and --to, by default
+the current week, as a tree of days, tasks and subtasks.
+
+Keys:
+  up/down, k/j     move
+  right, l         expand, or go to the first child
+  left, h          collapse, or go to the parent
+  enter, space     expand or collapse
+  /                filter by task, e.g. /work/mail (Enter to apply, Esc to clear)
+  q, ctrl+c        quit
+
+Clicking a row selects it and expands or collapses it, and the mouse wheel
+moves up and down. Expanding a task also lists its sessions.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		from, to, err := reportDateRange(browseCmdFrom, browseCmdTo)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		dayStart, err := configuredDayStart()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		records, err := queryDays(browseCmdLogFile, from, to, dayStart)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error reading log: %v\n"), err)
+			os.Exit(1)
+		}
+
+		var period []Record
+		for _, record := range records {
+			if day := talogo.DayOf(record.Start, dayStart); day >= from && day <= to {
+				period = append(period, record)
+			}
+		}
+		if len(period) == 0 {
+			fmt.Printf(tr("No records between %s and %s\n"), from, to)
+			return
+		}
+
+		m := newBrowseModel(period, dayStart)
+		if _, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion()).Run(); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	browseCmd.Flags().StringVarP(&browseCmdLogFile, "file", "f", defaultLogFile(), "Log file to read")
+	browseCmd.Flags().StringVar(&browseCmdFrom, "from", "", "First day to browse (YYYY-MM-DD, default first day of this week)")
+	browseCmd.Flags().StringVar(&browseCmdTo, "to", "", "Last day to browse (YYYY-MM-DD, default today)")
+	rootCmd.AddCommand(browseCmd)
+}
+
+// browseRow is a line of the browse tree: a day, a task or a session
+type browseRow struct {
+	key    string // Date followed by the task path, e.g. 2026-03-02/work/mail
+	depth  int    // 0 for days
+	label  string // Day, task name or session times
+	total  time.Duration
+	parent int       // Index of the parent row, -1 for days
+	node   *TaskNode // nil for sessions
+}
+
+// expandable reports whether the row has rows under it
+func (r browseRow) expandable() bool {
+	return r.node != nil && (len(r.node.Children) > 0 || r.node.Duration > 0 || r.depth == 0)
+}
+
+// browseModel is the bubbletea model of the browse subcommand
+type browseModel struct {
+	records  []Record
+	dayStart time.Duration
+
+	days     map[string]*TaskNode // Summary of the records matching the filter
+	sessions map[string][]Record  // Records matching the filter by day and task path
+	expanded map[string]bool      // Keys of the expanded rows
+	rows     []browseRow          // Visible rows
+	cursor   int
+
+	filter    string
+	filtering bool   // Typing a filter
+	input     string // Filter being typed
+	height    int    // Terminal height, 0 until known
+}
+
+// newBrowseModel returns the browse tree of records, with days collapsed
+func newBrowseModel(records []Record, dayStart time.Duration) browseModel {
+	m := browseModel{records: records, dayStart: dayStart, expanded: make(map[string]bool)}
+	m.build()
+	return m
+}
+
+// build aggregates the records matching the filter and lists the visible
+// rows, keeping the cursor on the same row when it is still visible
+func (m *browseModel) build() {
+	current := ""
+	if m.cursor < len(m.rows) {
+		current = m.rows[m.cursor].key
+	}
+
+	m.days = make(map[string]*TaskNode)
+	m.sessions = make(map[string][]Record)
+	dates, byDay := recordsByDay(m.records, m.dayStart)
+	for _, date := range dates {
+		for _, record := range byDay[date] {
+			path := strings.Join(sanitizedTitles(record.Titles), "/")
+			if m.filter != "" && !strings.Contains(strings.ToLower(path), strings.ToLower(m.filter)) {
+				continue
+			}
+			talogo.AddToSummary(m.days, record, talogo.SummaryOptions{DayStart: m.dayStart})
+			m.sessions[date+"/"+path] = append(m.sessions[date+"/"+path], record)
+		}
+	}
+
+	m.rows = nil
+	for _, date := range talogo.SortedDates(m.days) {
+		day := m.days[date]
+		m.rows = append(m.rows, browseRow{key: date, label: date, total: day.TotalTime, parent: -1, node: day})
+		if m.expanded[date] {
+			m.addTasks(date, day.Children, 1, len(m.rows)-1)
+		}
+	}
+
+	m.cursor = 0
+	for i, row := range m.rows {
+		if row.key == current {
+			m.cursor = i
+		}
+	}
+}
+
+// addTasks adds the rows of tasks, children of the row at parent, and of
+// the subtasks and sessions of those expanded
+func (m *browseModel) addTasks(key string, tasks map[string]*TaskNode, depth, parent int) {
+	for _, name := range talogo.SortedTaskNames(tasks) {
+		task := tasks[name]
+		taskKey := key + "/" + name
+		m.rows = append(m.rows, browseRow{key: taskKey, depth: depth, label: name, total: task.TotalTime, parent: parent, node: task})
+		if !m.expanded[taskKey] {
+			continue
+		}
+		row := len(m.rows) - 1
+		m.addTasks(taskKey, task.Children, depth+1, row)
+		for _, record := range m.sessions[taskKey] {
+			label := fmt.Sprintf("%s - %s", record.Start.Format(clockLayout), record.End.Format(clockLayout))
+			if record.Notes != "" {
+				label += "  " + talogo.SanitizeTitle(record.Notes)
+			}
+			m.rows = append(m.rows, browseRow{key: taskKey + "#" + record.Start.Format(time.RFC3339), depth: depth + 1, label: label, total: record.Duration(), parent: row})
+		}
+	}
+}
+
+func (m browseModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m browseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+	case tea.MouseMsg:
+		return m.click(msg), nil
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		if m.filtering {
+			return m.typeFilter(msg), nil
+		}
+		key := msg.String()
+		if len(m.rows) == 0 && key != "q" && key != "/" && key != "esc" {
+			return m, nil
+		}
+		switch key {
+		case "q":
+			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.rows)-1 {
+				m.cursor++
+			}
+		case "home", "g":
+			m.cursor = 0
+		case "end", "G":
+			m.cursor = len(m.rows) - 1
+		case "right", "l":
+			row := m.rows[m.cursor]
+			if !row.expandable() {
+				break
+			}
+			if m.expanded[row.key] {
+				if m.cursor < len(m.rows)-1 && m.rows[m.cursor+1].parent == m.cursor {
+					m.cursor++
+				}
+				break
+			}
+			m.expanded[row.key] = true
+			m.build()
+		case "left", "h":
+			row := m.rows[m.cursor]
+			if m.expanded[row.key] {
+				delete(m.expanded, row.key)
+				m.build()
+			} else if row.parent >= 0 {
+				m.cursor = row.parent
+			}
+		case "enter", " ":
+			row := m.rows[m.cursor]
+			if !row.expandable() {
+				break
+			}
+			m.expanded[row.key] = !m.expanded[row.key]
+			m.build()
+		case "/":
+			m.filtering = true
+			m.input = m.filter
+		case "esc":
+			if m.filter != "" {
+				m.filter = ""
+				m.build()
+			}
+		}
+	}
+	return m, nil
+}
+
+// click handles the mouse: the wheel moves the cursor and clicking a row
+// selects it and expands or collapses it
+func (m browseModel) click(msg tea.MouseMsg) browseModel {
+	if m.filtering || len(m.rows) == 0 || msg.Action != tea.MouseActionPress {
+		return m
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.cursor = max(m.cursor-1, 0)
+	case tea.MouseButtonWheelDown:
+		m.cursor = min(m.cursor+1, len(m.rows)-1)
+	case tea.MouseButtonLeft:
+		first, count := m.visibleRows()
+		if msg.Y < 0 || msg.Y >= count {
+			break
+		}
+		m.cursor = first + msg.Y
+		if row := m.rows[m.cursor]; row.expandable() {
+			m.expanded[row.key] = !m.expanded[row.key]
+			m.build()
+		}
+	}
+	return m
+}
+
+// visibleRows returns the first row shown and how many fit on the screen,
+// keeping the cursor in sight and leaving room for the footer
+func (m browseModel) visibleRows() (int, int) {
+	visible := m.height - 2
+	if m.height <= 0 || len(m.rows) <= visible || visible <= 0 {
+		return 0, len(m.rows)
+	}
+	return min(max(m.cursor-visible/2, 0), len(m.rows)-visible), visible
+}
+
+// typeFilter handles the keys typed while entering the filter
+func (m browseModel) typeFilter(msg tea.KeyMsg) browseModel {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.filtering = false
+		m.filter = strings.TrimSpace(m.input)
+		m.build()
+		// Show the days of the matching tasks
+		if m.filter != "" {
+			for date := range m.days {
+				m.expanded[date] = true
+			}
+			m.build()
+		}
+	case tea.KeyEsc:
+		m.filtering = false
+		m.input = ""
+	case tea.KeyBackspace:
+		if len(m.input) > 0 {
+			runes := []rune(m.input)
+			m.input = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.input += string(msg.Runes)
+	}
+	return m
+}
+
+func (m browseModel) View() string {
+	var lines []string
+	for i, row := range m.rows {
+		marker := " "
+		if row.expandable() {
+			marker = "+"
+			if m.expanded[row.key] {
+				marker = "-"
+			}
+		}
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		label := fmt.Sprintf("%s%s%s %s", cursor, strings.Repeat("  ", row.depth), marker, row.label)
+		lines = append(lines, fmt.Sprintf("%-50s %8.2f hs", label, row.total.Hours()))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "  "+tr("No tasks match the filter"))
+	} else {
+		first, count := m.visibleRows()
+		lines = lines[first : first+count]
+	}
+
+	footer := tr("↑/↓ move  ←/→ collapse/expand  / filter  q quit")
+	switch {
+	case m.filtering:
+		footer = tr("Filter tasks (Enter to apply, Esc to cancel): ") + m.input
+	case m.filter != "":
+		footer = fmt.Sprintf(tr("Filter: %s (Esc to clear)  %s"), m.filter, footer)
+	}
+	return strings.Join(lines, "\n") + "\n" + footer + "\n"
+}
+
+// sanitizedTitles returns titles as counted in the summary, without
+// control characters
+func sanitizedTitles(titles []string) []string {
+	sanitized := make([]string, len(titles))
+	for i, title := range titles {
+		sanitized[i] = talogo.SanitizeTitle(title)
+	}
+	return sanitized
+}
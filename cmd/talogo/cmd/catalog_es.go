@@ -0,0 +1,1192 @@
+package cmd
+
+// catalogES holds the Spanish translations of user-facing messages, by
+// English message
+var catalogES = map[string]string{
+	// Help
+	"Usage:":                  "Uso:",
+	"Aliases:":                "Alias:",
+	"Examples:":               "Ejemplos:",
+	"Available Commands:":     "Comandos disponibles:",
+	"Flags:":                  "Opciones:",
+	"Global Flags:":           "Opciones globales:",
+	"Additional help topics:": "Temas de ayuda adicionales:",
+	`Use "{{.CommandPath}} [command] --help" for more information about a command.`: `Use "{{.CommandPath}} [comando] --help" para más información sobre un comando.`,
+	"[command]": "[comando]",
+	`talogo is a simple tasks time tracker utility and logger.
+
+Exit codes:
+  0  success
+  1  error, such as an invalid flag or an unreadable log
+  2  talogo status: no session is running
+  3  talogo doctor, verify: some check found problems
+  4  talogo diff: the logs hold different sessions`: `talogo es una herramienta simple para medir y registrar el tiempo de las tareas.
+
+Códigos de salida:
+  0  éxito
+  1  error, como una opción inválida o un registro ilegible
+  2  talogo status: no hay una sesión en curso
+  3  talogo doctor, verify: alguna comprobación encontró problemas
+  4  talogo diff: los registros tienen sesiones distintas`,
+
+	// Commands
+	"talogo is a simple tasks time tracker utility and logger":                              "talogo es una herramienta simple para medir y registrar el tiempo de las tareas",
+	"Copy the log into the backups directory, keeping the last N copies":                    "Copiar el registro al directorio de copias de seguridad, conservando las últimas N copias",
+	"Browse the summary of a period as an interactive tree":                                 "Explorar el resumen de un período como un árbol interactivo",
+	"Render the time of a period as an SVG or PNG chart":                                    "Dibujar el tiempo de un período como un gráfico SVG o PNG",
+	"Create Clockify time entries for sessions of mapped tasks":                             "Crear entradas de tiempo en Clockify para las sesiones de tareas asociadas",
+	"Merge back-to-back sessions of the same task in the log, e.g. 'coalesce 5m'":           "Unir sesiones consecutivas de la misma tarea en el registro, p. ej. 'coalesce 5m'",
+	"Read and write settings in the config file":                                            "Leer y escribir opciones en el archivo de configuración",
+	"Print a config value, or the whole config if no key is given":                          "Mostrar un valor de la configuración, o toda la configuración si no se indica una clave",
+	"Set a config value, e.g. 'config set flags.file ~/talogo.csv'":                         "Cambiar un valor de la configuración, p. ej. 'config set flags.file ~/talogo.csv'",
+	"Delete sessions by id (unique id prefixes are accepted)":                               "Borrar sesiones por id (se aceptan prefijos únicos de id)",
+	"Check the log file for common problems":                                                "Buscar problemas comunes en el archivo de registro",
+	"Export logged sessions to CSV, JSON, org-mode, timeclock, DOT or Excel":                "Exportar las sesiones registradas a CSV, JSON, org-mode, timeclock, DOT o Excel",
+	"Create Harvest time entries for sessions of mapped tasks":                              "Crear entradas de tiempo en Harvest para las sesiones de tareas asociadas",
+	"Import sessions from calendars and other tools":                                        "Importar sesiones de calendarios y otras herramientas",
+	"Import calendar events from an ICS file or URL as sessions":                            "Importar eventos de un calendario ICS, archivo o URL, como sesiones",
+	"Create Jira worklogs for sessions whose titles mention an issue key":                   "Crear registros de trabajo en Jira para las sesiones cuyos títulos mencionan una incidencia",
+	"List logged sessions with their ids":                                                   "Listar las sesiones registradas con sus ids",
+	"Start tracking a task and log to file when finished":                                   "Empezar a medir una tarea y registrarla en el archivo al terminar",
+	"Merge the records of another log file into the log":                                    "Incorporar al registro las entradas de otro archivo de registro",
+	"Upgrade the log file to the current schema version":                                    "Actualizar el archivo de registro a la versión actual del formato",
+	"List records whose time ranges overlap":                                                "Listar las entradas cuyos intervalos se superponen",
+	"List sessions that could not be written to their log":                                  "Listar las sesiones que no se pudieron escribir en su registro",
+	"Write the pending sessions to their log":                                               "Escribir las sesiones pendientes en su registro",
+	"Manage named projects, each with its own log file":                                     "Administrar proyectos con nombre, cada uno con su propio archivo de registro",
+	"Register a project and its log file":                                                   "Agregar un proyecto y su archivo de registro",
+	"Unregister a project, leaving its log file in place":                                   "Quitar un proyecto, conservando su archivo de registro",
+	"List the registered projects, marking the active one":                                  "Listar los proyectos, marcando el activo",
+	"Make a project the active one, or print the active project":                            "Activar un proyecto, o mostrar el proyecto activo",
+	"Print a compact running session segment for tmux or shell prompts":                     "Mostrar la sesión en curso de forma compacta para tmux o el prompt de la shell",
+	"Rename a task and its subtasks in every record, e.g. 'rename work/emials work/emails'": "Renombrar una tarea y sus subtareas en todas las entradas, p. ej. 'rename trabajo/corros trabajo/correos'",
+	"Print or email a summary of a period, by default the current week":                     "Mostrar o enviar por correo el resumen de un período, por defecto la semana actual",
+	"Serve an HTTP API to start and stop sessions and query the log":                        "Servir una API HTTP para iniciar y detener sesiones y consultar el registro",
+	"Show statistics about the logged sessions":                                             "Mostrar estadísticas de las sesiones registradas",
+	"Measure time with the timer, without logging it":                                       "Medir tiempo con el temporizador, sin registrarlo",
+	"Push the end of the last session forward, e.g. 'extend --by 15m'":                      "Extender el final de la última sesión, p. ej. 'extend --by 15m'",
+	"Keep running, notifying when nothing is tracked during working hours":                  "Seguir ejecutándose, avisando cuando no se registra nada en horario laboral",
+	"Search the titles and notes of the logged sessions, e.g. 'grep -i deploy'":             "Buscar en los títulos y notas de las sesiones registradas, p. ej. 'grep -i deploy'",
+	"Compare two log files, listing added, removed and modified sessions":                   "Comparar dos archivos de registro, listando las sesiones añadidas, eliminadas y modificadas",
+	"List the sessions removed from the log or edited away, to restore them":                "Listar las sesiones eliminadas del registro o reemplazadas al editarlas, para restaurarlas",
+	"Put sessions of the trash back into the log (unique id prefixes are accepted)":         "Devolver sesiones de la papelera al registro (se aceptan prefijos únicos de id)",
+	"Drop the sessions of the trash for good":                                               "Eliminar definitivamente las sesiones de la papelera",
+	"Check the log against its checksums, to detect edits made outside talogo":              "Comprobar el registro con sus sumas de verificación, para detectar ediciones hechas fuera de talogo",
+	"Manage the estimated time of tasks and compare it with the logged time":                "Gestionar el tiempo estimado de las tareas y compararlo con el registrado",
+	"Set the estimated time of a task (e.g. work/project-x 40h)":                            "Definir el tiempo estimado de una tarea (p. ej. work/project-x 40h)",
+	"Remove the estimate of a task":                                                         "Eliminar la estimaci\u00f3n de una tarea",
+	"Compare the estimated and logged time of the estimated tasks":                          "Comparar el tiempo estimado y el registrado de las tareas estimadas",
+	"Mark sessions as billed or synced, so they are not invoiced or pushed twice":           "Marcar sesiones como facturadas o sincronizadas, para no facturarlas ni enviarlas dos veces",
+	"Show the running session, also formatted for status bars":                              "Mostrar la sesión en curso, también con formato para barras de estado",
+	"Generate a report of total hours spent per task and subtasks per day":                  "Generar un informe de las horas de cada tarea y subtarea por día",
+	"Synchronize the log with remote storage and other services":                            "Sincronizar el registro con almacenamiento remoto y otros servicios",
+	"Synchronize the log with the S3 bucket or WebDAV server in the config file":            "Sincronizar el registro con el bucket S3 o el servidor WebDAV de la configuración",
+	"Manage task templates, logged with 'talogo log @NAME'":                                 "Administrar plantillas de tareas, registradas con 'talogo log @NOMBRE'",
+	"Save the titles of a task as a template":                                               "Guardar los títulos de una tarea como plantilla",
+	"Remove a template":                                   "Quitar una plantilla",
+	"List the templates and their titles":                 "Listar las plantillas y sus títulos",
+	"Draw the sessions of a day as bars along a 24h axis": "Dibujar las sesiones de un día como barras sobre un eje de 24 horas",
+
+	// Descriptions
+	`Browse the summary of the time tracked between --from and --to, by default
+the current week, as a tree of days, tasks and subtasks.
+
+Keys:
+  up/down, k/j     move
+  right, l         expand, or go to the first child
+  left, h          collapse, or go to the parent
+  enter, space     expand or collapse
+  /                filter by task, e.g. /work/mail (Enter to apply, Esc to clear)
+  q, ctrl+c        quit
+
+Clicking a row selects it and expands or collapses it, and the mouse wheel
+moves up and down. Expanding a task also lists its sessions.`: `Explorar el resumen del tiempo medido entre --from y --to, por defecto
+la semana actual, como un árbol de días, tareas y subtareas.
+
+Teclas:
+  up/down, k/j     moverse
+  right, l         expandir, o ir al primer hijo
+  left, h          contraer, o ir al padre
+  enter, space     expandir o contraer
+  /                filtrar por tarea, p. ej. /work/mail (Enter aplica, Esc borra)
+  q, ctrl+c        salir
+
+Hacer clic en una fila la selecciona y la expande o contrae, y la rueda del
+mouse sube y baja. Expandir una tarea también lista sus sesiones.`,
+	`Render the time tracked between --from and --to, by default the current
+week, as a standalone chart for slides and reports:
+
+  pie  share of each top-level task
+  bar  total of each day
+
+The image format follows the extension of --out, .svg or .png.`: `Dibujar el tiempo medido entre --from y --to, por defecto la semana
+actual, como un gráfico independiente para presentaciones e informes:
+
+  pie  proporción de cada tarea principal
+  bar  total de cada día
+
+El formato de la imagen sigue la extensión de --out, .svg o .png.`,
+	`Merge the sessions of the same task that start less than GAP after the
+previous one ended, on the same day, into a single record whose duration
+leaves out the gaps. The log is rewritten in order of start time, keeping
+the previous version in a .bak file.
+
+Reports can merge sessions without changing the log with --coalesce.`: `Unir las sesiones de la misma tarea que empiezan menos de GAP después de
+que terminó la anterior, el mismo día, en una sola entrada cuya duración
+excluye los huecos. El registro se reescribe ordenado por hora de inicio,
+conservando la versión anterior en un archivo .bak.
+
+Los informes pueden unir sesiones sin cambiar el registro con --coalesce.`,
+	`Set a config value. Nested keys are separated by dots, e.g.
+'config set rates.work 50'. Values are stored as booleans or numbers when
+they parse as such, and as strings otherwise. Comments in the config file
+are not preserved.`: `Cambiar un valor de la configuración. Las claves anidadas se separan con
+puntos, p. ej. 'config set rates.work 50'. Los valores se guardan como
+booleanos o números cuando se pueden leer como tales, y como texto en otro
+caso. Los comentarios del archivo de configuración no se conservan.`,
+	`Delete sessions by id (unique id prefixes are accepted). Deleted
+sessions are moved to the trash of the log, to be restored with 'talogo
+trash restore' if deleted by mistake.`: `Borrar sesiones por id (se aceptan prefijos únicos de id). Las sesiones
+borradas van a la papelera del registro, para restaurarlas con 'talogo
+trash restore' si se borraron por error.`,
+	`Compare two log files, e.g. the copies of two machines before a merge,
+or a log and the .bak file a rewrite left, listing the sessions only in
+NEW (+), only in OLD (-) and those modified (~) with the fields that
+changed. NEW defaults to the log.
+
+Sessions are matched by id, or by start time and titles when logged
+before ids existed. The exit status is 4 when the logs differ.`: `Comparar dos archivos de registro, p. ej. las copias de dos equipos antes
+de incorporarlas, o un registro y el archivo .bak que dejó una reescritura,
+listando las sesiones que solo están en NEW (+), las que solo están en OLD
+(-) y las modificadas (~) con los campos que cambiaron. NEW es por defecto
+el registro.
+
+Las sesiones se emparejan por id, o por hora de inicio y títulos si se
+registraron antes de que existieran los ids. El código de salida es 4
+cuando los registros difieren.`,
+	`Check the log file for common problems, exiting with code 3 if some
+check finds any. With --quiet only the problems are printed.`: `Buscar problemas comunes en el archivo de registro, saliendo con código 3
+si alguna comprobación encuentra alguno. Con --quiet solo se muestran los
+problemas.`,
+	`Manage the estimated time of tasks, kept in the [estimates] table of the
+config, and compare it with the time logged to them:
+
+  talogo estimate set work/project-x 40h
+  talogo estimate report --from 2026-09-01
+
+The logged time of a task includes its subtasks.`: `Gestionar el tiempo estimado de las tareas, guardado en la tabla
+[estimates] de la configuración, y compararlo con el tiempo registrado:
+
+  talogo estimate set work/project-x 40h
+  talogo estimate report --from 2026-09-01
+
+El tiempo registrado de una tarea incluye el de sus subtareas.`,
+	`Compare the estimated time of each task with the time logged to it and
+its subtasks, optionally between --from and --to. The variance is the
+logged time over or under the estimate, as a percentage of it.`: `Comparar el tiempo estimado de cada tarea con el registrado en ella y sus
+subtareas, opcionalmente entre --from y --to. La desviación es el tiempo
+registrado por encima o por debajo de la estimación, como porcentaje de
+ella.`,
+	`Export logged sessions to CSV, JSON, org-mode, timeclock, DOT or Excel.
+
+The dot format is a Graphviz graph of the task hierarchy, with the time of
+each task and labels sized by their share, e.g. for a map of a quarter:
+
+  talogo export --format dot --from 2026-01-01 --to 2026-03-31 | dot -Tsvg > q1.svg
+
+The xlsx format is an Excel workbook with a Sessions sheet, one session
+per row, and a By day sheet with the time of each task per day, both with
+totals. Times are spreadsheet durations, formatted as hours and minutes:
+
+  talogo export --format xlsx --from 2026-09-01 --to 2026-09-30 -o september.xlsx
+
+With --anonymize, titles are replaced by pseudonyms keeping the task
+hierarchy and the durations, to share the data without client names:
+top level tasks become Project A, Project B..., their subtasks Task 1,
+Task 2... and tags tag1, tag2... Names are numbered in order of first
+appearance in the log, so they keep their pseudonyms in the exports of a
+growing log. Notes, hosts and users are left out.`: `Exportar las sesiones registradas a CSV, JSON, org-mode, timeclock, DOT o
+Excel.
+
+El formato dot es un grafo de Graphviz de la jerarquía de tareas, con el
+tiempo de cada tarea y etiquetas del tamaño de su proporción, p. ej. para
+un mapa de un trimestre:
+
+  talogo export --format dot --from 2026-01-01 --to 2026-03-31 | dot -Tsvg > q1.svg
+
+El formato xlsx es un libro de Excel con una hoja Sessions, una sesión por
+fila, y una hoja By day con el tiempo de cada tarea por día, ambas con
+totales. Los tiempos son duraciones de la hoja de cálculo, con formato de
+horas y minutos:
+
+  talogo export --format xlsx --from 2026-09-01 --to 2026-09-30 -o september.xlsx
+
+Con --anonymize, los títulos se reemplazan por seudónimos que conservan la
+jerarquía de tareas y las duraciones, para compartir los datos sin nombres
+de clientes: las tareas principales pasan a ser Project A, Project B...,
+sus subtareas Task 1, Task 2... y las etiquetas tag1, tag2... Los nombres
+se numeran en orden de aparición en el registro, así conservan sus
+seudónimos en las exportaciones de un registro que crece. Las notas, los
+equipos y los usuarios se omiten.`,
+	`Push the end of the most recent session of the log forward, for when the
+timer was stopped but the work went on for a bit. --by adds a duration and
+--until sets the new end, e.g. 18:00 or "yesterday 19:30". The new end
+can't be in the future. A session extended into the next day is split in
+one session per day, like those tracked across midnight.
+
+The log is rewritten keeping the previous version in a .bak file.`: `Extender el final de la sesión más reciente del registro, para cuando el
+temporizador se detuvo pero el trabajo siguió un poco más. --by agrega una
+duración y --until fija el nuevo final, p. ej. 18:00 o "yesterday 19:30".
+El nuevo final no puede estar en el futuro. Una sesión extendida hasta el
+día siguiente se divide en una sesión por día, como las medidas pasando la
+medianoche.
+
+El registro se reescribe conservando la versión anterior en un archivo
+.bak.`,
+	`Search the titles and notes of the logged sessions with a regular
+expression (Go syntax, e.g. "review|PR-[0-9]+"). Each matching session is
+listed with its id and duration, followed by the lines of its notes that
+match, and the time of all of them is added up at the end.
+
+--titles or --notes restrict the search to one of them, and --from and
+--to to the sessions of those days.`: `Buscar en los títulos y notas de las sesiones registradas con una
+expresión regular (sintaxis de Go, p. ej. "review|PR-[0-9]+"). Cada sesión
+que coincide se lista con su id y duración, seguida de las líneas de sus
+notas que coinciden, y al final se suma el tiempo de todas.
+
+--titles o --notes limitan la búsqueda a uno de ellos, y --from y --to a
+las sesiones de esos días.`,
+	`Import the events of an ICS calendar, read from FILE or downloaded from
+--calendar-url (e.g. the secret iCal address of a Google Calendar), as
+sessions titled with the event summary.
+
+Only events that already ended are imported, and all-day and cancelled
+events are skipped. Events already in the log are not imported again.`: `Importar los eventos de un calendario ICS, leído de FILE o descargado de
+--calendar-url (p. ej. la dirección secreta iCal de un Google Calendar),
+como sesiones tituladas con el resumen del evento.
+
+Solo se importan los eventos que ya terminaron, y se omiten los eventos de
+todo el día y los cancelados. Los eventos que ya están en el registro no
+se vuelven a importar.`,
+	`Start tracking a task and log to file when finished.
+
+Each argument is a title, the first the top level task. Aliases in the
+config file stand for their task path, and @NAME for the titles of the
+template NAME (see 'talogo template').
+
+Keys:
+  p        pause or resume
+  w        switch task: log the session so far and track another task,
+           typed with its titles separated by /
+  ctrl+c   stop and log the session
+
+The keybindings table of the config replaces these keys, e.g. pause =
+"space", though ctrl+c always stops.
+
+With --mouse, or mouse in the config, the timer takes the whole terminal
+and shows its actions as buttons to click.
+
+The last sessions logged today are listed under the timer, 5 by default;
+--recent sets how many, 0 hides them. With daily or weekly in the goals
+table of the config, e.g. daily = "8h", the timer shows the time logged
+today or this week next to them.
+
+With --review, or review in the config, stopping with ctrl+c shows the
+titles and notes of the session to correct them before it is written:
+tab moves between them, enter saves and esc goes back to the timer.
+Sessions stopped by a signal or by another command are written as they
+are.
+
+With --for, e.g. --for 25m, the timer counts down the time tracked. When
+it is over, a notification is sent and the on_expire hook of the config
+runs, while the session goes on until stopped.
+
+With --pomodoro the timer alternates pomodoros, 25m of tracked time by
+default, with breaks it is paused for: 5m long, or 15m every 4
+pomodoros. The pomodoro table of the config sets other lengths, which
+--work, --short-break, --long-break and --long-break-every override.
+Pressing p on a break skips the rest of it. Pomodoros are numbered from
+the first of the day, and their records are tagged pomodoro, plus
+pomodoro:N once pomodoro N is completed (see 'talogo stats --pomodoros').
+
+The breaks table of the config logs the pauses taken with p, and the
+pomodoro breaks, as sessions of a task of their own, e.g. break, when
+the timer resumes.
+
+With max_session in the config, e.g. "10h", a forgotten timer stops by
+itself once it tracked that long, saving the session up to then with its
+last record tagged auto-stopped. With on_max_session = "prompt" it pauses
+instead and asks what to do with the time since.
+
+With a threshold in the gaps table of the config, e.g. "30m", starting a
+session that long after the last one of the day ended asks whether to
+log the time in between, to one of the tasks of the table, e.g. lunch,
+or to another one, or to leave it untracked.
+
+With --allow-overlap the session is tracked alongside others on purpose,
+e.g. on-call underneath regular work: it runs next to the running
+sessions without asking, and its records are tagged parallel. Each
+session keeps its own state, so status shows them all and stopping one
+leaves the others running. It can't be combined with --running stop or
+take-over. Overlaps with parallel records are not warned about nor
+refused by --strict, and summary and report count the time they share
+with other records once in day totals, unless --count-overlaps is given.`: `Empezar a medir una tarea y registrarla en el archivo al terminar.
+
+Cada argumento es un título, el primero la tarea principal. Los alias de
+la configuración representan su ruta de tarea, y @NAME los títulos de la
+plantilla NAME (ver 'talogo template').
+
+Teclas:
+  p        pausar o reanudar
+  w        cambiar de tarea: registrar la sesión hasta ahora y medir otra
+           tarea, escrita con sus títulos separados por /
+  ctrl+c   detener y registrar la sesión
+
+La tabla keybindings de la configuración reemplaza estas teclas, p. ej.
+pause = "space", aunque ctrl+c siempre detiene.
+
+Con --mouse, o mouse en la configuración, el temporizador ocupa toda la
+terminal y muestra sus acciones como botones.
+
+Las últimas sesiones registradas hoy se listan bajo el temporizador, 5 por
+defecto; --recent fija cuántas, 0 las oculta. Con daily o weekly en la
+tabla goals de la configuración, p. ej. daily = "8h", el temporizador
+muestra junto a ellas el tiempo registrado hoy o esta semana.
+
+Con --review, o review en la configuración, detener con ctrl+c muestra los
+títulos y notas de la sesión para corregirlos antes de escribirla: tab
+pasa de uno a otro, enter guarda y esc vuelve al temporizador. Las
+sesiones detenidas por una señal o por otro comando se escriben tal como
+están.
+
+Con --for, p. ej. --for 25m, el temporizador hace una cuenta regresiva del
+tiempo medido. Al terminar se envía una notificación y se ejecuta el hook
+on_expire de la configuración, mientras la sesión sigue hasta detenerla.
+
+Con --pomodoro el temporizador alterna pomodoros, 25m de tiempo medido por
+defecto, con pausas durante las que queda detenido: de 5m, o de 15m cada 4
+pomodoros. La tabla pomodoro de la configuración fija otras duraciones,
+que --work, --short-break, --long-break y --long-break-every reemplazan.
+Presionar p en una pausa saltea el resto. Los pomodoros se numeran desde
+el primero del día, y sus entradas se etiquetan pomodoro, más pomodoro:N
+una vez completado el pomodoro N (ver 'talogo stats --pomodoros').
+
+La tabla breaks de la configuración registra las pausas tomadas con p, y
+las de los pomodoros, como sesiones de una tarea propia, p. ej. break,
+cuando el temporizador se reanuda.
+
+Con max_session en la configuración, p. ej. "10h", un temporizador
+olvidado se detiene solo una vez que midió ese tiempo, guardando la sesión
+hasta entonces con su última entrada etiquetada auto-stopped. Con
+on_max_session = "prompt" en cambio se pausa y pregunta qué hacer con el
+tiempo desde entonces.
+
+Con un umbral en la tabla gaps de la configuración, p. ej. "30m", empezar
+una sesión ese tiempo después de que terminó la última del día pregunta si
+registrar el tiempo intermedio, en una de las tareas de la tabla, p. ej.
+lunch, o en otra, o dejarlo sin medir.
+
+Con --allow-overlap la sesión se mide junto a otras a propósito, p. ej.
+guardias por debajo del trabajo habitual: corre junto a las sesiones en
+curso sin preguntar, y sus entradas se etiquetan parallel. Cada sesión
+guarda su propio estado, así status las muestra todas y detener una deja
+las demás en curso. No se puede combinar con --running stop ni take-over.
+Las superposiciones con entradas paralelas no se avisan ni las rechaza
+--strict, y summary y report cuentan una sola vez en los totales del día
+el tiempo que comparten con otras entradas, salvo que se indique
+--count-overlaps.`,
+	`Mark sessions as billed or synced, by id (unique id prefixes are
+accepted) or with the sessions of the days between --from and --to,
+optionally of some tasks only. The mark is a tag of the sessions, and
+--undo removes it.
+
+'talogo report --mark-billed' marks the sessions of a report as billed,
+and harvest, clockify and jira mark the sessions they push as synced.
+Reports, summaries and exports leave billed sessions out with --unbilled:
+
+  talogo report --from 2026-09-01 --to 2026-09-30 --unbilled --mark-billed`: `Marcar sesiones como facturadas o sincronizadas, por id (se aceptan
+prefijos únicos de id) o con las sesiones de los días entre --from y --to,
+opcionalmente solo de algunas tareas. La marca es una etiqueta de las
+sesiones, y --undo la quita.
+
+'talogo report --mark-billed' marca como facturadas las sesiones de un
+informe, y harvest, clockify y jira marcan como sincronizadas las sesiones
+que envían. Los informes, resúmenes y exportaciones excluyen las sesiones
+facturadas con --unbilled:
+
+  talogo report --from 2026-09-01 --to 2026-09-30 --unbilled --mark-billed`,
+	`Merge the records of another log file (e.g. copied from another machine)
+into the log. Records are matched by id; records with the same id but
+different times, titles, tags or notes are conflicts, resolved interactively
+unless --prefer is given. Records deleted from the log, found in its
+trash, are not merged back.`: `Incorporar al registro las entradas de otro archivo de registro (p. ej.
+copiado de otro equipo). Las entradas se emparejan por id; las que tienen
+el mismo id pero distintos horarios, títulos, etiquetas o notas son
+conflictos, resueltos de forma interactiva salvo que se indique --prefer.
+Las entradas borradas del registro, que están en su papelera, no se
+vuelven a incorporar.`,
+	`List the sessions that could not be written to their log, because the
+disk was full or the file was not writable. They are kept in the state
+directory, or the temporary directory if that fails too, until written to
+the log with 'talogo pending replay'.`: `Listar las sesiones que no se pudieron escribir en su registro, porque el
+disco estaba lleno o el archivo no se podía escribir. Se guardan en el
+directorio de estado, o en el directorio temporal si eso también falla,
+hasta escribirlas en el registro con 'talogo pending replay'.`,
+	`Print a compact segment such as "▶ project-x 1:23" for each running
+session, or nothing when idle. It only reads the session state files, so it
+is fast enough for tmux status-right or a starship custom module.`: `Mostrar un segmento compacto como "▶ project-x 1:23" para cada sesión en
+curso, o nada si no hay ninguna. Solo lee los archivos de estado de las
+sesiones, así que es lo bastante rápido para status-right de tmux o un
+módulo personalizado de starship.`,
+	`Keep running in the background, sending a desktop notification every
+--every while no session is running during the working hours, so gaps
+are noticed while they are still easy to log. Paused sessions count as
+tracked, and weekends and the vacations of the config are left alone.
+
+Start it with the desktop session, e.g. from a systemd user unit or with:
+
+  talogo remind --workhours 09:00-18:00 --every 20m &`: `Seguir ejecutándose en segundo plano, enviando una notificación de
+escritorio cada --every mientras no hay una sesión en curso en el horario
+laboral, para notar los huecos cuando todavía es fácil registrarlos. Las
+sesiones pausadas cuentan como medidas, y los fines de semana y las
+vacaciones de la configuración no se avisan.
+
+Se inicia con la sesión de escritorio, p. ej. desde una unidad de usuario
+de systemd o con:
+
+  talogo remind --workhours 09:00-18:00 --every 20m &`,
+	`Print a summary of the time tracked between --from and --to, as Markdown
+or HTML. By default the period is the current week, from its first day
+(week_start in the config, Monday by default) to today.
+
+With --email the report is sent through the SMTP server of the config file
+to the smtp.to addresses, or those given with --mail-to, with the sessions
+of the period attached as CSV. For example, to email the weekly report every
+Friday evening from cron:
+
+  0 18 * * 5 talogo report --email
+
+With --template the report is rendered with a Go text/template file
+instead, for layouts such as a corporate timesheet. The template gets the
+report with .From, .To, .Days (date -> day), .Total and .Records; days and
+tasks have .Name, .TotalTime, .Duration and .Children. Besides the
+built-in functions it can use dates, tasks, hours, join and json:
+
+  {{range dates .Days}}{{.}};{{printf "%.2f" (hours (index $.Days .).TotalTime)}}
+  {{end}}
+
+With --matrix month the report is a table of the month of --from, by
+default the current one, with tasks as rows and its weeks as columns, the
+layout of many timesheet forms. Weeks begin on the configured week_start
+and are labeled with their ISO week number. --matrix-depth sets how many
+levels of titles the rows keep, e.g. 2 for work/mail.
+
+With --locale, or locale in the config, day headers are long dates in
+that language and hours use its decimal separator, e.g. "Lunes 6 de mayo:
+7,50 hs" for es. Templates can use the same formatting with
+{{$.Locale.Date .}} and {{$.Locale.Hours .TotalTime}}.
+
+Sessions logged with 'log --allow-overlap' count for their tasks, but
+the time they share with other sessions counts once in the day totals
+and the total of the period. --count-overlaps counts it twice instead.
+
+To invoice the same hours once, --unbilled leaves out the sessions marked
+as billed and --mark-billed marks those of the period as billed once the
+report is printed or sent, see 'talogo mark'.`: `Mostrar un resumen del tiempo medido entre --from y --to, en Markdown o
+HTML. Por defecto el período es la semana actual, desde su primer día
+(week_start de la configuración, lunes por defecto) hasta hoy.
+
+Con --email el informe se envía por el servidor SMTP de la configuración a
+las direcciones de smtp.to, o a las indicadas con --mail-to, con las
+sesiones del período adjuntas como CSV. Por ejemplo, para enviar el
+informe semanal cada viernes a la tarde desde cron:
+
+  0 18 * * 5 talogo report --email
+
+Con --template el informe se genera con un archivo de Go text/template, para
+formatos como una planilla de horas corporativa. La plantilla recibe el
+informe con .From, .To, .Days (fecha -> día), .Total y .Records; los días y
+las tareas tienen .Name, .TotalTime, .Duration y .Children. Además de las
+funciones predefinidas puede usar dates, tasks, hours, join y json:
+
+  {{range dates .Days}}{{.}};{{printf "%.2f" (hours (index $.Days .).TotalTime)}}
+  {{end}}
+
+Con --matrix month el informe es una tabla del mes de --from, por defecto
+el actual, con las tareas como filas y sus semanas como columnas, el
+formato de muchas planillas de horas. Las semanas empiezan en el
+week_start configurado y se rotulan con su número de semana ISO.
+--matrix-depth fija cuántos niveles de títulos conservan las filas, p. ej.
+2 para work/mail.
+
+Con --locale, o locale en la configuración, los encabezados de los días
+son fechas largas en ese idioma y las horas usan su separador decimal,
+p. ej. "Lunes 6 de mayo: 7,50 hs" para es. Las plantillas pueden usar el
+mismo formato con {{$.Locale.Date .}} y {{$.Locale.Hours .TotalTime}}.
+
+Las sesiones registradas con 'log --allow-overlap' cuentan para sus
+tareas, pero el tiempo que comparten con otras sesiones cuenta una sola
+vez en los totales del día y en el total del período. --count-overlaps lo
+cuenta dos veces.
+
+Para facturar las mismas horas una sola vez, --unbilled excluye las
+sesiones marcadas como facturadas y --mark-billed marca como facturadas
+las del período una vez que el informe se muestra o se envía, ver 'talogo
+mark'.`,
+	`Serve an HTTP API to start and stop sessions and query the log.
+
+Every request must carry the token in an 'Authorization: Bearer TOKEN'
+header. If no token is given with --token or TALOGO_TOKEN, a random one is
+generated and printed on startup.
+
+Endpoints:
+  POST /sessions/start   start a session, body {"titles": [...], "tags": [...], "notes": "..."}
+  POST /sessions/stop    stop the running session and log it, returning the records
+                         written (none if shorter than min_session)
+  POST /sessions/cancel  discard the running session
+  GET  /status           the running session, if any
+  GET  /entries          logged sessions (?date=YYYY-MM-DD&host=H&last=N)
+  GET  /summary          daily task totals (?by=task|tag&tz=ZONE&source=S&host=H&earnings=true)
+  GET  /metrics          Prometheus metrics of the running session and the log`: `Servir una API HTTP para iniciar y detener sesiones y consultar el registro.
+
+Cada solicitud debe llevar el token en un encabezado 'Authorization: Bearer
+TOKEN'. Si no se indica un token con --token o TALOGO_TOKEN, se genera uno
+aleatorio y se muestra al iniciar.
+
+Endpoints:
+  POST /sessions/start   iniciar una sesión, cuerpo {"titles": [...], "tags": [...], "notes": "..."}
+  POST /sessions/stop    detener la sesión en curso y registrarla, devolviendo las
+                         entradas escritas (ninguna si dura menos que min_session)
+  POST /sessions/cancel  descartar la sesión en curso
+  GET  /status           la sesión en curso, si la hay
+  GET  /entries          sesiones registradas (?date=YYYY-MM-DD&host=H&last=N)
+  GET  /summary          totales diarios por tarea (?by=task|tag&tz=ZONE&source=S&host=H&earnings=true)
+  GET  /metrics          métricas de Prometheus de la sesión en curso y el registro`,
+	`Show statistics about the logged sessions: overall totals, or with
+--switches the task switches of each day.
+
+With --hours or --weekdays, show how the tracked time distributes across
+the hours of the day or the days of the week, for example to see when
+deep work happens compared to meetings:
+
+  talogo stats --hours --task work/code --from 2026-01-01
+
+With --pomodoros, show the pomodoros completed with 'talogo log
+--pomodoro' each day, by task.`: `Mostrar estadísticas de las sesiones registradas: totales generales, o con
+--switches los cambios de tarea de cada día.
+
+Con --hours o --weekdays, mostrar cómo se reparte el tiempo medido entre
+las horas del día o los días de la semana, por ejemplo para ver cuándo se
+hace el trabajo profundo en comparación con las reuniones:
+
+  talogo stats --hours --task work/code --from 2026-01-01
+
+Con --pomodoros, mostrar los pomodoros completados con 'talogo log
+--pomodoro' cada día, por tarea.`,
+	`Show the running sessions, read from the state kept by each 'talogo log'
+and 'talogo serve' process. Sessions running side by side, e.g. with
+'log --running both' or 'log --allow-overlap', are all shown, oldest first.
+
+Formats:
+  text    human readable description (default)
+  plain   a single line for polybar, i3blocks or tmux
+  waybar  the JSON expected by a waybar custom module with return-type json
+
+With --quiet, text prints only the running tasks, e.g. work/mail, one per
+line. The text and JSON outputs exit with code 2 when no session is
+running, so scripts can check with: if talogo status -q; then ...`: `Mostrar las sesiones en curso, leídas del estado que guarda cada proceso
+de 'talogo log' y 'talogo serve'. Las sesiones que corren a la vez, p. ej.
+con 'log --running both' o 'log --allow-overlap', se muestran todas, de la
+más antigua a la más reciente.
+
+Formatos:
+  text    descripción legible (por defecto)
+  plain   una sola línea para polybar, i3blocks o tmux
+  waybar  el JSON que espera un módulo personalizado de waybar con return-type json
+
+Con --quiet, text muestra solo las tareas en curso, p. ej. work/mail, una
+por línea. Las salidas text y JSON terminan con código 2 cuando no hay una
+sesión en curso, así los scripts pueden comprobarlo con: if talogo status -q; then ...`,
+	`Measure time with the timer of 'talogo log', for quick measurements that
+should not appear in reports: nothing is written to the log, and the
+stopwatch is not a running session for status, hooks or webhooks. The
+labels, if any, are shown above the timer.
+
+Keys:
+  p, space     pause or resume
+  l, enter     take a lap
+  q, ctrl+c    stop`: `Medir tiempo con el temporizador de 'talogo log', para mediciones rápidas
+que no deben aparecer en los informes: no se escribe nada en el registro, y
+el cronómetro no es una sesión en curso para status, hooks ni webhooks. Las
+etiquetas, si las hay, se muestran sobre el temporizador.
+
+Teclas:
+  p, space     pausar o reanudar
+  l, enter     marcar una vuelta
+  q, ctrl+c    detener`,
+	`Create Clockify time entries for the sessions in the date range whose
+task is mapped to a project in the [clockify.projects] section of the
+config file, e.g.
+
+  [clockify]
+  workspace_id = "..."
+  token = "..."
+
+  [clockify.projects."client-x/coding"]
+  project_id = "..."
+  task_id = "..."     # optional
+  billable = true
+
+Synced sessions are remembered in a .clockify.json file next to the log,
+so they are never posted twice.`: `Crear entradas de tiempo en Clockify para las sesiones del rango de fechas
+cuya tarea está asociada a un proyecto en la sección [clockify.projects] de
+la configuración, p. ej.
+
+  [clockify]
+  workspace_id = "..."
+  token = "..."
+
+  [clockify.projects."client-x/coding"]
+  project_id = "..."
+  task_id = "..."     # opcional
+  billable = true
+
+Las sesiones sincronizadas se recuerdan en un archivo .clockify.json junto
+al registro, así nunca se envían dos veces.`,
+	`Create Harvest time entries for the sessions in the date range whose
+task is mapped to a project in the [harvest.projects] section of the
+config file, e.g.
+
+  [harvest]
+  account_id = "123456"
+  token = "..."
+
+  [harvest.projects."client-x"]
+  project_id = 111
+  task_id = 222
+
+Synced sessions are remembered in a .harvest.json file next to the log, so
+they are never posted twice.`: `Crear entradas de tiempo en Harvest para las sesiones del rango de fechas
+cuya tarea está asociada a un proyecto en la sección [harvest.projects] de
+la configuración, p. ej.
+
+  [harvest]
+  account_id = "123456"
+  token = "..."
+
+  [harvest.projects."client-x"]
+  project_id = 111
+  task_id = 222
+
+Las sesiones sincronizadas se recuerdan en un archivo .harvest.json junto
+al registro, así nunca se envían dos veces.`,
+	`Create Jira worklogs for the sessions in the date range whose titles
+mention an issue key (e.g. PROJ-123), using the server in the [jira]
+section of the config file. Synced sessions are remembered in a
+.jira.json file next to the log, so they are never posted twice.
+
+Jira only accepts worklogs of at least one minute, so durations are
+rounded to the nearest minute and shorter sessions are skipped.`: `Crear registros de trabajo en Jira para las sesiones del rango de fechas
+cuyos títulos mencionan una incidencia (p. ej. PROJ-123), usando el
+servidor de la sección [jira] de la configuración. Las sesiones
+sincronizadas se recuerdan en un archivo .jira.json junto al registro, así
+nunca se envían dos veces.
+
+Jira solo acepta registros de trabajo de al menos un minuto, así que las
+duraciones se redondean al minuto más cercano y las sesiones más cortas se
+omiten.`,
+	`Synchronize the log with the S3 bucket or WebDAV server configured in the
+[remote] section of the config file.
+
+Strategies:
+  merge  records missing on either side are appended to the other one
+  lww    last write wins: the most recently modified copy replaces the other`: `Sincronizar el registro con el bucket S3 o el servidor WebDAV de la sección
+[remote] de la configuración.
+
+Estrategias:
+  merge  las entradas que faltan en un lado se agregan al otro
+  lww    gana la última escritura: la copia modificada más recientemente
+         reemplaza a la otra`,
+	`Save the titles of a task as a template, so 'talogo log @NAME' logs them.
+Further arguments to log are added as subtasks.
+
+Arguments are split at slashes into titles. A single argument without
+slashes is split at spaces instead, so these are the same:
+
+  talogo template add standup "work meetings standup"
+  talogo template add standup work/meetings/standup
+  talogo template add standup work meetings standup`: `Guardar los títulos de una tarea como plantilla, para que 'talogo log
+@NAME' los registre. Los demás argumentos de log se agregan como
+subtareas.
+
+Los argumentos se dividen en títulos en las barras. Un único argumento sin
+barras se divide en cambio en los espacios, así que estos son iguales:
+
+  talogo template add standup "work meetings standup"
+  talogo template add standup work/meetings/standup
+  talogo template add standup work meetings standup`,
+	`Draw the sessions of each day as horizontal bars along a 24 hour axis, one
+lane per top-level task, so gaps and overlaps stand out. The first lane
+shows the whole day: · untracked, █ tracked, ▓ tracked more than once.
+
+Sessions of the same task that overlap are drawn on lanes of their own.
+Colors follow the theme of the config, and are left out when the output
+is not a terminal or NO_COLOR is set.`: `Dibujar las sesiones de cada día como barras horizontales sobre un eje de
+24 horas, un carril por tarea principal, para que resalten los huecos y
+las superposiciones. El primer carril muestra el día entero: · sin medir,
+█ medido, ▓ medido más de una vez.
+
+Las sesiones de la misma tarea que se superponen se dibujan en carriles
+propios. Los colores siguen el tema de la configuración, y se omiten
+cuando la salida no es una terminal o NO_COLOR está definida.`,
+	`List the sessions commands rewriting the log removed from it, such as
+delete, coalesce or doctor --prune-short, and the previous versions of
+the sessions they edited, such as with rename or extend. They are kept in
+a .trash.jsonl file next to the log until restored with 'talogo trash
+restore' or dropped with 'talogo trash empty'.`: `Listar las sesiones que los comandos que reescriben el registro quitaron
+de él, como delete, coalesce o doctor --prune-short, y las versiones
+anteriores de las sesiones que editaron, como con rename o extend. Se
+guardan en un archivo .trash.jsonl junto al registro hasta restaurarlas
+con 'talogo trash restore' o eliminarlas con 'talogo trash empty'.`,
+	`Put sessions of the trash back into the log, by the id of their trash
+entry. A restored session replaces the version of the log with the same
+id, which goes to the trash in turn, and is appended to the log
+otherwise.`: `Devolver sesiones de la papelera al registro, por el id de su entrada en
+la papelera. Una sesión restaurada reemplaza a la versión del registro con
+el mismo id, que a su vez va a la papelera, y si no la hay se agrega al
+registro.`,
+	`Check the log against its checksums. With checksums = true in the config,
+talogo keeps a hash chain of the records of the log in a .checksums file
+next to it, updated by every command writing the log. verify computes it
+again from the log and reports the first record that does not match, as
+left by an edit made outside talogo or a corrupted disk. Commands
+rewriting the log refuse to while it does not match.
+
+--reset accepts the current content of the log, writing its checksums
+anew, e.g. after enabling checksums or reviewing a manual edit.`: `Comprobar el registro con sus sumas de verificación. Con checksums = true
+en la configuración, talogo mantiene una cadena de hashes de las entradas
+del registro en un archivo .checksums junto a él, actualizada por cada
+comando que escribe el registro. verify la vuelve a calcular a partir del
+registro e informa la primera entrada que no coincide, como la que deja
+una edición hecha fuera de talogo o un disco dañado. Los comandos que
+reescriben el registro se niegan a hacerlo mientras no coincida.
+
+--reset acepta el contenido actual del registro, escribiendo de nuevo sus
+sumas de verificación, p. ej. después de activar checksums o de revisar
+una edición manual.`,
+
+	// Flags
+	"Log file to read":           "Archivo de registro a leer",
+	"Log file to write":          "Archivo de registro a escribir",
+	"Log file to read and write": "Archivo de registro a leer y escribir",
+	"Print plain status lines instead of redrawing the timer, for screen readers (keys are typed followed by Enter)": "Mostrar líneas de estado simples en lugar de redibujar el temporizador, para lectores de pantalla (las teclas se escriben seguidas de Enter)",
+	"Show the timer actions as buttons to click, taking the whole terminal":                                          "Mostrar las acciones del temporizador como botones, ocupando toda la terminal",
+	"Number of sessions of the day shown under the timer, 0 to hide them":                                            "Cantidad de sesiones del día mostradas bajo el temporizador, 0 para ocultarlas",
+	"Review the titles and notes of the session when stopping, before it is written":                                 "Revisar los títulos y notas de la sesión al detenerla, antes de escribirla",
+	"Count down from this time tracked (e.g. 25m), alerting and running the on_expire hook when it is over":          "Cuenta regresiva desde este tiempo registrado (p. ej. 25m), avisando y ejecutando el hook on_expire al terminar",
+	"Alternate pomodoros of tracked time with breaks, as set in the pomodoro table of the config":                    "Alternar pomodoros de tiempo registrado con pausas, según la tabla pomodoro de la configuración",
+	"Length of the pomodoros, instead of the configured one (default 25m)":                                           "Duración de los pomodoros, en lugar de la configurada (por defecto 25m)",
+	"Length of the short pomodoro breaks, instead of the configured one (default 5m)":                                "Duración de las pausas cortas, en lugar de la configurada (por defecto 5m)",
+	"Length of the long pomodoro breaks, instead of the configured one (default 15m)":                                "Duración de las pausas largas, en lugar de la configurada (por defecto 15m)",
+	"Pomodoros before each long break, instead of the configured number (default 4)":                                 "Pomodoros antes de cada pausa larga, en lugar de la cantidad configurada (por defecto 4)",
+	"Report the pomodoros completed per day and task":                                                                "Informar los pomodoros completados por día y tarea",
+	"Time to add to the end of the session, e.g. 15m":                                                                "Tiempo a agregar al final de la sesión, p. ej. 15m",
+	"New end of the session, e.g. 18:00 or \"yesterday 19:30\"":                                                      "Nuevo final de la sesión, p. ej. 18:00 o \"yesterday 19:30\"",
+	"Working hours to remind in, e.g. 08:30-17:00":                                                                   "Horario laboral en el que avisar, p. ej. 08:30-17:00",
+	"Time between reminders while nothing is tracked":                                                                "Tiempo entre avisos mientras no se registra nada",
+	"Remind on Saturdays and Sundays too":                                                                            "Avisar también sábados y domingos",
+	"Track the session alongside others, tagged parallel, e.g. on-call underneath regular work":                      "Registrar la sesión junto a otras, etiquetada parallel, p. ej. guardias por debajo del trabajo habitual",
+	"Count the time parallel sessions share with others twice in day totals":                                         "Contar dos veces en los totales del día el tiempo que las sesiones paralelas comparten con otras",
+	"Only search days from this date (YYYY-MM-DD)":                                                                   "Buscar solo en los días desde esta fecha (AAAA-MM-DD)",
+	"Only search days up to this date (YYYY-MM-DD)":                                                                  "Buscar solo en los días hasta esta fecha (AAAA-MM-DD)",
+	"Ignore case when matching":                                                                                      "Ignorar mayúsculas y minúsculas al buscar",
+	"Only search the titles":                                                                                         "Buscar solo en los títulos",
+	"Only search the notes":                                                                                          "Buscar solo en las notas",
+	"Log file compared when NEW is not given":                                                                        "Archivo de registro comparado cuando no se indica NEW",
+	"Log file whose trash to use":                                                                                    "Archivo de registro cuya papelera usar",
+	"Only drop the sessions trashed longer ago than this, e.g. 720h":                                                 "Eliminar solo las sesiones enviadas a la papelera hace más de esto, p. ej. 720h",
+	"Log file to verify": "Archivo de registro a verificar",
+	"Write the checksums of the current content of the log instead of checking it":   "Escribir las sumas de verificación del contenido actual del registro en lugar de comprobarlo",
+	"Replace titles and tags with pseudonyms and leave out notes, hosts and users":   "Reemplazar títulos y etiquetas por seudónimos y omitir notas, equipos y usuarios",
+	"Mark the sessions of the days from this date (YYYY-MM-DD)":                      "Marcar las sesiones de los d\u00edas desde esta fecha (AAAA-MM-DD)",
+	"Mark the sessions of the days up to this date (YYYY-MM-DD)":                     "Marcar las sesiones de los d\u00edas hasta esta fecha (AAAA-MM-DD)",
+	"Only mark a task and its subtasks (e.g. \"work\" or \"work/project-x\")":        "Marcar solo una tarea y sus subtareas (p. ej. \"work\" o \"work/project-x\")",
+	"Remove the mark instead of adding it":                                           "Quitar la marca en lugar de a\u00f1adirla",
+	"Leave out the sessions marked as billed":                                        "Excluir las sesiones marcadas como facturadas",
+	"Mark the sessions of the period as billed after printing or sending the report": "Marcar las sesiones del per\u00edodo como facturadas tras imprimir o enviar el informe",
+	"Draw borders around the tables of list, stats and estimate report":              "Dibujar bordes alrededor de las tablas de list, stats y estimate report",
+	"Log file to rewrite": "Archivo de registro a reescribir",
+	"Output format of status, list, grep, diff, stats, estimate report and doctor (text, json)": "Formato de salida de status, list, grep, diff, stats, estimate report y doctor (text, json)",
+	"Print only what scripts need, without decoration or confirmations":                         "Mostrar solo lo que necesitan los scripts, sin decoración ni confirmaciones",
+	"Only include days from this date (YYYY-MM-DD)":                                             "Incluir solo los días desde esta fecha (AAAA-MM-DD)",
+	"Only include days up to this date (YYYY-MM-DD)":                                            "Incluir solo los días hasta esta fecha (AAAA-MM-DD)",
+	"Time zone used to group records by day (e.g. Europe/Madrid)":                               "Zona horaria con la que agrupar las entradas por día (p. ej. Europe/Madrid)",
+	"Language of dates and decimal separator (e.g. es, pt_BR), instead of locale in the config": "Idioma de las fechas y separador decimal (p. ej. es, pt_BR), en lugar de locale de la configuración",
+	"Backups directory (default from config, or backups in the data directory)":                 "Directorio de las copias de seguridad (por defecto el de la configuración, o backups en el directorio de datos)",
+	"Log file to back up": "Archivo de registro a copiar",
+	"Number of backups to keep (default from config, or 10)":                                                                          "Cantidad de copias a conservar (por defecto la de la configuración, o 10)",
+	"First day to browse (YYYY-MM-DD, default first day of this week)":                                                                "Primer día a explorar (AAAA-MM-DD, por defecto el primer día de esta semana)",
+	"Last day to browse (YYYY-MM-DD, default today)":                                                                                  "Último día a explorar (AAAA-MM-DD, por defecto hoy)",
+	"First day of the chart (YYYY-MM-DD, default first day of this week)":                                                             "Primer día del gráfico (AAAA-MM-DD, por defecto el primer día de esta semana)",
+	"Height of the image in pixels":                                                                                                   "Alto de la imagen en píxeles",
+	"Destination file, .svg or .png":                                                                                                  "Archivo de destino, .svg o .png",
+	"Last day of the chart (YYYY-MM-DD, default today)":                                                                               "Último día del gráfico (AAAA-MM-DD, por defecto hoy)",
+	"Chart type (pie, bar)":                                                                                                           "Tipo de gráfico (pie, bar)",
+	"Width of the image in pixels":                                                                                                    "Ancho de la imagen en píxeles",
+	"Print how many records would be merged without rewriting the log":                                                                "Mostrar cuántas entradas se unirían sin reescribir el registro",
+	"Log file to check":                                                                                                               "Archivo de registro a revisar",
+	"Remove the records shorter than min_session in the config before checking":                                                       "Eliminar las entradas más cortas que min_session de la configuración antes de revisar",
+	"Field delimiter of CSV output, a single character or \"tab\" (e.g. ';' for spreadsheets in some locales)":                        "Delimitador de campos de la salida CSV, un solo carácter o \"tab\" (p. ej. ';' para hojas de cálculo de algunas regiones)",
+	"Output format (csv, json, jsonl, org, timeclock, dot, xlsx)":                                                                     "Formato de salida (csv, json, jsonl, org, timeclock, dot, xlsx)",
+	"Only export days from this date (YYYY-MM-DD)":                                                                                    "Exportar solo los días desde esta fecha (AAAA-MM-DD)",
+	"Destination file ('-' for stdout)":                                                                                               "Archivo de destino ('-' para la salida estándar)",
+	"Only export entries added since the previous export to the same destination":                                                     "Exportar solo las entradas agregadas desde la exportación anterior al mismo destino",
+	"Only export days up to this date (YYYY-MM-DD)":                                                                                   "Exportar solo los días hasta esta fecha (AAAA-MM-DD)",
+	"Store the attendees of each event in the session notes":                                                                          "Guardar los asistentes de cada evento en las notas de la sesión",
+	"Download the calendar from this URL instead of reading a file":                                                                   "Descargar el calendario de esta URL en lugar de leer un archivo",
+	"Only import events starting on or after this date (YYYY-MM-DD)":                                                                  "Importar solo los eventos que empiezan en esta fecha o después (AAAA-MM-DD)",
+	"Task path the event summaries are nested under (e.g. \"meetings\")":                                                              "Tarea bajo la que se anidan los títulos de los eventos (p. ej. \"meetings\")",
+	"Only import events starting on or before this date (YYYY-MM-DD, default today)":                                                  "Importar solo los eventos que empiezan en esta fecha o antes (AAAA-MM-DD, por defecto hoy)",
+	"Merge back-to-back sessions of the same task separated by less than this (e.g. 5m)":                                              "Unir sesiones consecutivas de la misma tarea separadas por menos de esto (p. ej. 5m)",
+	"Only list sessions started on this date (YYYY-MM-DD)":                                                                            "Listar solo las sesiones iniciadas en esta fecha (AAAA-MM-DD)",
+	"Only list sessions tracked on these hosts":                                                                                       "Listar solo las sesiones medidas en estos equipos",
+	"Only list the last N sessions":                                                                                                   "Listar solo las últimas N sesiones",
+	"Start the session in the past, e.g. 9:30, yesterday 14:00 or 20m ago":                                                            "Empezar la sesión en el pasado, p. ej. 9:30, yesterday 14:00 o 20m ago",
+	"Ask whether to use an existing task instead of titles that look like a typo or variant of it":                                    "Preguntar si usar una tarea existente en lugar de títulos que parecen un error de tipeo o una variante de ella",
+	"Start tracking even if today is marked as vacation":                                                                              "Empezar a medir aunque hoy esté marcado como vacaciones",
+	"Use the repository name and current branch of the working directory as the first titles":                                         "Usar el nombre del repositorio y la rama actual del directorio de trabajo como primeros títulos",
+	"Note to attach to the session":                                                                                                   "Nota a adjuntar a la sesión",
+	"Track without the interactive view, stopping on SIGINT or SIGTERM and printing a single line":                                    "Medir sin la vista interactiva, deteniéndose con SIGINT o SIGTERM y mostrando una sola línea",
+	"What to do if other sessions are running: ask, stop them, take-over (continue the latest here, ignoring the titles) or run both": "Qué hacer si hay otras sesiones en curso: ask (preguntar), stop (detenerlas), take-over (continuar aquí la última, ignorando los títulos) o both (medir todas)",
+	"Refuse to write sessions overlapping records of the log, saving them as pending instead of warning":                              "Rechazar las sesiones superpuestas con entradas del registro, guardándolas como pendientes en lugar de avisar",
+	"Tag to attach to the session (can be repeated)":                                                                                  "Etiqueta a adjuntar a la sesión (se puede repetir)",
+	"Log file to merge into":                                                                                                          "Archivo de registro en el que incorporar",
+	"Resolve conflicting edits without asking, keeping the local or other version":                                                    "Resolver las ediciones en conflicto sin preguntar, conservando la versión local o la otra",
+	"Log file to migrate": "Archivo de registro a actualizar",
+	"Directory in which the project becomes active automatically":                                             "Directorio en el que el proyecto se activa automáticamente",
+	"Print the whole task path instead of the first title":                                                    "Mostrar la ruta completa de la tarea en lugar del primer título",
+	"Symbol printed before the task":                                                                          "Símbolo mostrado antes de la tarea",
+	"Email the report instead of printing it":                                                                 "Enviar el informe por correo en lugar de mostrarlo",
+	"Output format (markdown, html)":                                                                          "Formato de salida (markdown, html)",
+	"First day of the report (YYYY-MM-DD, default first day of this week)":                                    "Primer día del informe (AAAA-MM-DD, por defecto el primer día de esta semana)",
+	"Recipients of the email, instead of smtp.to":                                                             "Destinatarios del correo, en lugar de smtp.to",
+	"Print a table of tasks by week for a period (month)":                                                     "Mostrar una tabla de tareas por semana de un período (month)",
+	"Levels of titles of the rows of --matrix":                                                                "Niveles de títulos de las filas de --matrix",
+	"Render the report with this Go text/template file instead of --format":                                   "Generar el informe con este archivo de Go text/template en lugar de --format",
+	"Last day of the report (YYYY-MM-DD, default today)":                                                      "Último día del informe (AAAA-MM-DD, por defecto hoy)",
+	"Address to listen on":                                                                                    "Dirección en la que escuchar",
+	"Token clients must send as a bearer token (random if empty)":                                             "Token que los clientes deben enviar como bearer token (aleatorio si está vacío)",
+	"Report the tracked time by hour of the day":                                                              "Informar el tiempo medido por hora del día",
+	"Only include records created by these sources (interactive, add, import, auto, recovered, api, unknown)": "Incluir solo las entradas creadas por estos orígenes (interactive, add, import, auto, recovered, api, unknown)",
+	"Report task switches and average block length per day":                                                   "Informar los cambios de tarea y la duración media de los bloques por día",
+	"Only include a task and its subtasks (e.g. \"work\" or \"work/meetings\")":                               "Incluir solo una tarea y sus subtareas (p. ej. \"work\" o \"work/meetings\")",
+	"Report the tracked time by day of the week":                                                              "Informar el tiempo medido por día de la semana",
+	"Output format (text, plain, waybar)":                                                                     "Formato de salida (text, plain, waybar)",
+	"Aggregate time by task or by tag":                                                                        "Agrupar el tiempo por tarea o por etiqueta",
+	"Show money earned per task using the rates in the config file":                                           "Mostrar lo ganado por tarea con las tarifas de la configuración",
+	"Leave a task and its subtasks out of the report (e.g. \"breaks\" or \"work/lunch\")":                     "Excluir del informe una tarea y sus subtareas (p. ej. \"breaks\" o \"work/lunch\")",
+	"Only include records tracked on these hosts":                                                             "Incluir solo las entradas medidas en estos equipos",
+	"Ignore the aggregate index and parse the whole log":                                                      "Ignorar el índice de totales y leer todo el registro",
+	"Output format (csv, json, markdown, text)":                                                               "Formato de salida (csv, json, markdown, text)",
+	"Print the time entries that would be created without posting them":                                       "Mostrar las entradas de tiempo que se crearían sin enviarlas",
+	"First day to sync (YYYY-MM-DD, default today)":                                                           "Primer día a sincronizar (AAAA-MM-DD, por defecto hoy)",
+	"Last day to sync (YYYY-MM-DD, default today)":                                                            "Último día a sincronizar (AAAA-MM-DD, por defecto hoy)",
+	"Print the worklogs that would be created without posting them":                                           "Mostrar los registros de trabajo que se crearían sin enviarlos",
+	"Log file to synchronize":                                                                                 "Archivo de registro a sincronizar",
+	"Resolve conflicting edits without asking, keeping the local or other (remote) version":                   "Resolver las ediciones en conflicto sin preguntar, conservando la versión local o la otra (remota)",
+	"Synchronization strategy (merge, lww)":                                                                   "Estrategia de sincronización (merge, lww)",
+	"Day to draw, or the last of --days (YYYY-MM-DD, default today)":                                          "Día a dibujar, o el último de --days (AAAA-MM-DD, por defecto hoy)",
+	"Number of days to draw, ending on --date":                                                                "Cantidad de días a dibujar, terminando en --date",
+	"Draw without colors":                                                                                     "Dibujar sin colores",
+	"Width of the 24h axis in columns":                                                                        "Ancho del eje de 24 horas en columnas",
+	"Go back to the default log file":                                                                         "Volver al archivo de registro por defecto",
+
+	// Timer
+	"%s is marked as vacation. Start tracking anyway? [y/N] ":                                      "%s está marcado como vacaciones. ¿Empezar a medir de todos modos? [y/N] ",
+	"A session is already running: %s since %s (process %d)\n":                                     "Ya hay una sesión en curso: %s desde las %s (proceso %d)\n",
+	"[s]top it, [t]ake it over, run [b]oth or [c]ancel? ":                                          "¿[s] detenerla, [t] continuarla aquí, [b] medir ambas o [c] cancelar? ",
+	"Error: --allow-overlap runs next to the other sessions, it can't be used with --running %s\n": "Error: --allow-overlap mide junto a las otras sesiones, no se puede usar con --running %s\n",
+	"%d sessions are already running:\n":                                                           "Ya hay %d sesiones en curso:\n",
+	"  %s since %s (process %d)\n":                                                                 "  %s desde las %s (proceso %d)\n",
+	"[s]top them, [t]ake the latest over, run [b]oth or [c]ancel? ":                                "¿[s] detenerlas, [t] continuar aquí la última, [b] medir todas o [c] cancelar? ",
+	"Stopped %s\n": "Detenida %s\n",
+	"You were idle for %s. Keep, discard or reassign that time in talogo.": "Estuviste inactivo %s. Conserva, descarta o reasigna ese tiempo en talogo.",
+	"Still tracking %s after %s?":                                          "¿Sigues con %s después de %s?",
+	"Session taken over by another terminal\n":                             "Sesión continuada en otra terminal\n",
+	"Discarded %s (%s), shorter than min_session\n":                        "Descartada %s (%s), más corta que min_session\n",
+	"Error writing to %s: %v\n":                                            "Error al escribir en %s: %v\n",
+	"Logged %s (%s) to %s\n":                                               "Registrada %s (%s) en %s\n",
+	"Timer stopped. Session shorter than min_session, not saved\n":         "Temporizador detenido. Sesión más corta que min_session, no se guardó\n",
+	"Timer stopped. Error writing to %s: %v\n":                             "Temporizador detenido. Error al escribir en %s: %v\n",
+	"Timer stopped. Data saved to ":                                        "Temporizador detenido. Datos guardados en ",
+	"Timer stopped.\n":                                                     "Temporizador detenido.\n",
+	"Title %d: %s":                                                         "Título %d: %s",
+	"Tags: ":                                                               "Etiquetas: ",
+	"Timer: %02d:%02d:%02d":                                                "Tiempo: %02d:%02d:%02d",
+	"Log the time away to task (titles separated by /, Esc to go back): ":  "Registrar el tiempo fuera en la tarea (títulos separados por /, Esc para volver): ",
+	"Away for %s (%s): [k] keep it, [d] discard it, [a] assign it to another task, [s] stop\n": "Fuera durante %s (%s): [k] conservarlo, [d] descartarlo, [a] asignarlo a otra tarea, [s] detener\n",
+	"Paused (%s since %s)\n":                            "En pausa (%s desde las %s)\n",
+	"Paused, press %s to resume\n":                      "En pausa, pulsa %s para continuar\n",
+	"Type %s and Enter to pause or resume, %s to stop.": "Escribe %s y Enter para pausar o continuar, %s para detener.",
+	"Today: %s of %s":                                   "Hoy: %s de %s",
+	"This week: %s of %s":                               "Esta semana: %s de %s",
+	"Tracking %s, %s":                                   "Midiendo %s, %s",
+	"less than a minute":                                "menos de un minuto",
+	"1 hour":                                            "1 hora",
+	"%d hours":                                          "%d horas",
+	"1 minute":                                          "1 minuto",
+	"%d minutes":                                        "%d minutos",
+	"Switch to task (titles separated by /, Esc to go back): ": "Cambiar a la tarea (títulos separados por /, Esc para volver): ",
+	"cancel":      "cancelar",
+	"keep":        "conservar",
+	"discard":     "descartar",
+	"assign":      "asignar",
+	"stop":        "detener",
+	"pause":       "pausar",
+	"resume":      "continuar",
+	"switch task": "cambiar de tarea",
+	"save":        "guardar",
+	"back":        "volver",
+	"Review the session before saving (Tab to change field, Enter to save, Esc to go back):": "Revisa la sesión antes de guardarla (Tab para cambiar de campo, Enter para guardar, Esc para volver):",
+	"Task (titles separated by /): ":                "Tarea (títulos separados por /): ",
+	"Notes: ":                                       "Notas: ",
+	"p pause/resume  l lap  q quit":                 "p pausar/continuar  l vuelta  q salir",
+	"Lap %d: %s (+%s)":                              "Vuelta %d: %s (+%s)",
+	"Stopwatch stopped at %s, nothing was logged\n": "Cronómetro detenido en %s, no se registró nada\n",
+	"Remaining: %s":                                 "Restante: %s",
+	"Time is up (%s), press %s to stop\n":           "Se acabó el tiempo (%s), presiona %s para detener\n",
+	"Time is up: %s tracked on %s":                  "Se acabó el tiempo: %s registrado en %s",
+	"Pomodoro %d, %s left":                          "Pomodoro %d, quedan %s",
+	"Short break, %s left, press p to skip it\n":    "Pausa corta, quedan %s, presiona p para saltearla\n",
+	"Long break, %s left, press p to skip it\n":     "Pausa larga, quedan %s, presiona p para saltearla\n",
+	"Pomodoro %d done, take a %s break":             "Pomodoro %d terminado, toma una pausa de %s",
+	"Break over, back to %s":                        "Terminó la pausa, de vuelta a %s",
+	"Warning: numbering pomodoros from 1: %v\n":     "Aviso: numerando los pomodoros desde 1: %v\n",
+	"session limit reached":                         "límite de sesión alcanzado",
+	"%s reached the maximum session length of %s":   "%s alcanzó la duración máxima de sesión de %s",
+	"Timer stopped at the maximum session length, after %s. Data saved to %s, the last record tagged %s\n": "Temporizador detenido en la duración máxima de sesión, tras %s. Datos guardados en %s, el último registro con la etiqueta %s\n",
+	"Nothing is being tracked. Start a session with talogo log.":                                           "No se está registrando nada. Inicia una sesión con talogo log.",
+	"The last session ended at %s, %s before this one.\n":                                                  "La última sesión terminó a las %s, %s antes de esta.\n",
+	"Log the gap to a task (titles separated by /), or press Enter to leave it untracked: ":                "Registrar el hueco en una tarea (títulos separados por /), o pulsar Enter para dejarlo sin registrar: ",
+	"Log the gap to %s or another task (titles separated by /), or press Enter to leave it untracked: ":    "Registrar el hueco en %s u otra tarea (títulos separados por /), o pulsar Enter para dejarlo sin registrar: ",
+	"Logged the gap as %s\n":                                   "Hueco registrado como %s\n",
+	"No sessions match":                                        "Ninguna sesión coincide",
+	"%d sessions, %s in total\n":                               "%d sesiones, %s en total\n",
+	"The logs hold the same sessions":                          "Los registros contienen las mismas sesiones",
+	"%d added, %d removed, %d modified\n":                      "%d añadidas, %d eliminadas, %d modificadas\n",
+	"The trash is empty":                                       "La papelera está vacía",
+	"Restored %d sessions\n":                                   "Restauradas %d sesiones\n",
+	"Dropped %d sessions from the trash\n":                     "Eliminadas %d sesiones de la papelera\n",
+	"Wrote the checksums of %d records\n":                      "Escritas las sumas de verificación de %d entradas\n",
+	"The log does not match its checksums: %s\n":               "El registro no coincide con sus sumas de verificación: %s\n",
+	"The log matches its checksums\n":                          "El registro coincide con sus sumas de verificación\n",
+	"Warning: failed to update the checksums of the log: %v\n": "Aviso: no se pudieron actualizar las sumas de verificación del registro: %v\n",
+	"Estimated %s at %s\n":                                     "Estimado %s en %s\n",
+	"No estimates, set them with 'talogo estimate set'":        "No hay estimaciones, def\u00ednelas con 'talogo estimate set'",
+	"Task":                         "Tarea",
+	"Estimated":                    "Estimado",
+	"Logged":                       "Registrado",
+	"Variance":                     "Desv\u00edo",
+	"Marked %d sessions as %s\n":   "Marcadas %d sesiones como %s\n",
+	"Unmarked %d sessions as %s\n": "Desmarcadas %d sesiones como %s\n",
+	"ID":                           "ID",
+	"Start":                        "Inicio",
+	"End":                          "Fin",
+	"Duration":                     "Duraci\u00f3n",
+	"Origin":                       "Origen",
+	"Date":                         "Fecha",
+	"Switches":                     "Cambios",
+	"Average block":                "Bloque medio",
+	"Hour":                         "Hora",
+	"Day":                          "D\u00eda",
+	"Time":                         "Tiempo",
+	"Share":                        "Proporci\u00f3n",
+	"Pomodoros":                    "Pomodoros",
+	"Sessions":                     "Sesiones",
+	"Days":                         "D\u00edas",
+	"Total":                        "Total",
+	"Average per day":              "Promedio por d\u00eda",
+	"Skipped %d records deleted from the log\n": "Omitidas %d entradas borradas del registro\n",
+	"Deleted %d records\n":                      "Borradas %d entradas\n",
+	"Earlier today:":                            "Antes, hoy:",
+	"idle":                                      "inactivo",
+	"screen locked":                             "pantalla bloqueada",
+
+	// Browse
+	"No tasks match the filter":                       "Ninguna tarea coincide con el filtro",
+	"↑/↓ move  ←/→ collapse/expand  / filter  q quit": "↑/↓ mover  ←/→ contraer/expandir  / filtrar  q salir",
+	"Filter tasks (Enter to apply, Esc to cancel): ":  "Filtrar tareas (Enter para aplicar, Esc para cancelar): ",
+	"Filter: %s (Esc to clear)  %s":                   "Filtro: %s (Esc para quitarlo)  %s",
+	"No records between %s and %s\n":                  "No hay entradas entre %s y %s\n",
+
+	// Status
+	"Not tracking":                    "Sin medir",
+	"Tracking %s for %s (since %s)\n": "Midiendo %s durante %s (desde las %s)\n",
+	"Paused since %s\n":               "En pausa desde las %s\n",
+	"\nPaused since %s":               "\nEn pausa desde las %s",
+	"Tags: %s\n":                      "Etiquetas: %s\n",
+	"Log: %s\n":                       "Registro: %s\n",
+	"%s\nStarted at %s":               "%s\nIniciada a las %s",
+	"No pending sessions":             "No hay sesiones pendientes",
+	"%s: %d records for %s (%s)\n":    "%s: %d entradas para %s (%s)\n",
+	"No active project":               "No hay un proyecto activo",
+	"Generated token: %s\n":           "Token generado: %s\n",
+	"Serving %s on http://%s\n":       "Sirviendo %s en http://%s\n",
+	"%s  %s %s by %s\n    %s\n":       "%s  %s %s por %s\n    %s\n",
+
+	// Reports and statistics
+	"No data in CSV file (only header or empty)":     "No hay datos en el archivo CSV (solo cabecera o vacío)",
+	"No records match the given filters":             "Ninguna entrada coincide con los filtros",
+	"No tracked time to report":                      "No hay tiempo medido para informar",
+	"No pomodoros logged":                            "No hay pomodoros registrados",
+	"\nPomodoros: %d, %.1f per day with pomodoros\n": "\nPomodoros: %d, %.1f por día con pomodoros\n",
+	"\nAverage switches per day: %.1f\n":             "\nPromedio de cambios por día: %.1f\n",
+	"Report sent to %s\n":                            "Informe enviado a %s\n",
+	"Monday":                                         "Lunes",
+	"Tuesday":                                        "Martes",
+	"Wednesday":                                      "Miércoles",
+	"Thursday":                                       "Jueves",
+	"Friday":                                         "Viernes",
+	"Saturday":                                       "Sábado",
+	"Sunday":                                         "Domingo",
+	"No overlapping records":                         "No hay entradas superpuestas",
+	"%d overlapping pairs found\n":                   "Se encontraron %d pares superpuestos\n",
+	"Overlap of %s:\n":                               "Superposición de %s:\n",
+	"  line %d: %s - %s %s\n":                        "  línea %d: %s - %s %s\n",
+	"[ok]   %s\n":                                    "[ok]    %s\n",
+	"[fail] %s\n":                                    "[falla] %s\n",
+	"Goal: %.2f hs (%.0f%%)\n":                       "Meta: %.2f hs (%.0f%%)\n",
+
+	// Confirmations
+	"Added %d records, updated %d records\n":                             "Agregadas %d entradas, actualizadas %d entradas\n",
+	"Added project %s logging to %s\n":                                   "Agregado el proyecto %s, registrado en %s\n",
+	"Added template @%s for %s\n":                                        "Agregada la plantilla @%s para %s\n",
+	"Backed up %s to %s\n":                                               "Copia de seguridad de %s en %s\n",
+	"Merged %d records into %d\n":                                        "Unidas %d entradas en %d\n",
+	"Removed %d records shorter than min_session\n":                      "Borradas %d entradas más cortas que min_session\n",
+	"Renamed %d records\n":                                               "Renombradas %d entradas\n",
+	"Wrote %d records to %s\n":                                           "Escritas %d entradas en %s\n",
+	"Wrote %s\n":                                                         "Escrito %s\n",
+	"Imported %d events":                                                 "Se importaron %d eventos",
+	", skipped %d already in the log":                                    ", se omitieron %d que ya estaban en el registro",
+	"Would merge %d records into %d\n":                                   "Se unirían %d entradas en %d\n",
+	"Pulled %d records, pushed %d records\n":                             "Se recibieron %d entradas y se enviaron %d entradas\n",
+	"Nothing to synchronize, neither the local nor the remote log exist": "Nada que sincronizar, no existe ni el registro local ni el remoto",
+	"Local log is newer, uploaded it":                                    "El registro local es más reciente, se subió",
+	"Downloaded remote log":                                              "Se descargó el registro remoto",
+	"Remote log is newer, replaced the local one (previous version kept in %s.bak)\n": "El registro remoto es más reciente, reemplazó al local (la versión anterior se conserva en %s.bak)\n",
+	"Conflicting versions of record %s:\n":                                            "Versiones en conflicto de la entrada %s:\n",
+	"  [l]ocal: %s\n":                                                                 "  [l] local: %s\n",
+	"  [o]ther: %s\n":                                                                 "  [o] otra: %s\n",
+	"Keep which version? [l/o] ":                                                      "¿Qué versión conservar? [l/o] ",
+	"%s is not in the log, but %s is (%d records). Use it instead? [Y/n] ":            "%s no está en el registro, pero %s sí (%d entradas). ¿Usarlo en su lugar? [Y/n] ",
+	"%s is already at schema v%d\n":                                                   "%s ya está en la versión v%d del formato\n",
+	"Migrated %s from schema v%d to v%d (previous version kept in %s.bak)\n":          "Se actualizó %s de la versión v%d a la v%d del formato (la versión anterior se conserva en %s.bak)\n",
+	"%s needs no migration\n":                                                         "%s no necesita actualizarse\n",
+	"Assigned ids to %d records of %s (previous version kept in %s.bak)\n":            "Se asignaron ids a %d entradas de %s (la versión anterior se conserva en %s.bak)\n",
+	"Would push to %s: %s\n":                                                          "Se enviaría a %s: %s\n",
+	"Pushed to %s: %s\n":                                                              "Enviada a %s: %s\n",
+	"Created %d entries in %s\n":                                                      "Se crearon %d entradas en %s\n",
+
+	// Errors
+	"Error: %v\n":                                                              "Error: %v\n",
+	"Error adding project: %v\n":                                               "Error al agregar el proyecto: %v\n",
+	"Error adding template: %v\n":                                              "Error al agregar la plantilla: %v\n",
+	"Error backing up log: %v\n":                                               "Error al copiar el registro: %v\n",
+	"Error clearing active project: %v\n":                                      "Error al desactivar el proyecto: %v\n",
+	"Error coalescing log: %v\n":                                               "Error al unir las sesiones: %v\n",
+	"Error deleting records: %v\n":                                             "Error al borrar las entradas: %v\n",
+	"Error exporting records: %v\n":                                            "Error al exportar las entradas: %v\n",
+	"Error generating report: %v\n":                                            "Error al generar el informe: %v\n",
+	"Error generating report: --template cannot be combined with --email":      "Error al generar el informe: --template no se puede combinar con --email",
+	"Error generating report: unknown format %q (expected markdown or html)\n": "Error al generar el informe: formato %q desconocido (se esperaba markdown o html)\n",
+	"Error generating summary: %v\n":                                           "Error al generar el resumen: %v\n",
+	"Error generating summary: invalid --by value %q (expected task or tag)\n": "Error al generar el resumen: valor de --by %q inválido (se esperaba task o tag)\n",
+	"Error generating token: %v\n":                                             "Error al generar el token: %v\n",
+	"Error importing events: %v\n":                                             "Error al importar los eventos: %v\n",
+	"Error listing pending sessions: %v\n":                                     "Error al listar las sesiones pendientes: %v\n",
+	"Error listing projects: %v\n":                                             "Error al listar los proyectos: %v\n",
+	"Error listing templates: %v\n":                                            "Error al listar las plantillas: %v\n",
+	"Error merging logs: %v\n":                                                 "Error al unir los registros: %v\n",
+	"Error migrating log: %v\n":                                                "Error al actualizar el registro: %v\n",
+	"Error printing config: %v\n":                                              "Error al mostrar la configuración: %v\n",
+	"Error pruning short records: %v\n":                                        "Error al borrar las entradas cortas: %v\n",
+	"Error reading calendar: %v\n":                                             "Error al leer el calendario: %v\n",
+	"Error reading config: %v\n":                                               "Error al leer la configuración: %v\n",
+	"Error reading log: %v\n":                                                  "Error al leer el registro: %v\n",
+	"Error removing project: %v\n":                                             "Error al quitar el proyecto: %v\n",
+	"Error removing template: %v\n":                                            "Error al quitar la plantilla: %v\n",
+	"Error renaming task: %v\n":                                                "Error al renombrar la tarea: %v\n",
+	"Error rendering chart: %v\n":                                              "Error al dibujar el gráfico: %v\n",
+	"Error replaying %s: %v\n":                                                 "Error al escribir %s: %v\n",
+	"Error replaying pending sessions: %v\n":                                   "Error al escribir las sesiones pendientes: %v\n",
+	"Error running plugin %s: %v\n":                                            "Error al ejecutar el plugin %s: %v\n",
+	"Error sending report: %v\n":                                               "Error al enviar el informe: %v\n",
+	"Error serving API: %v\n":                                                  "Error al servir la API: %v\n",
+	"Error setting %s: %v\n":                                                   "Error al cambiar %s: %v\n",
+	"Error setting active project: %v\n":                                       "Error al activar el proyecto: %v\n",
+	"Error syncing with Clockify: %v\n":                                        "Error al sincronizar con Clockify: %v\n",
+	"Error syncing with Harvest: %v\n":                                         "Error al sincronizar con Harvest: %v\n",
+	"Error syncing with Jira: %v\n":                                            "Error al sincronizar con Jira: %v\n",
+	"Error syncing: %v\n":                                                      "Error al sincronizar: %v\n",
+	"Error writing config: %v\n":                                               "Error al escribir la configuración: %v\n",
+	"Error: --at %s is in the future\n":                                        "Error: --at %s está en el futuro\n",
+	"Error: invalid on_max_session %q in config (expected stop or prompt)\n":   "Error: on_max_session %q inválido en la configuración (se esperaba stop o prompt)\n",
+	"Error: either --by or --until is required":                                "Error: se requiere --by o --until",
+	"Error extending session: %v\n":                                            "Error al extender la sesión: %v\n",
+	"Error: invalid --every %s (expected a positive duration such as 20m)\n":   "Error: --every %s inválido (se esperaba una duración positiva como 20m)\n",
+	"Error: invalid pattern %q: %v\n":                                          "Error: patrón %q no válido: %v\n",
+	"Error: --titles and --notes cannot be combined":                           "Error: --titles y --notes no se pueden combinar",
+	"Error reading %s: %v\n":                                                   "Error al leer %s: %v\n",
+	"Error reading the trash: %v\n":                                            "Error al leer la papelera: %v\n",
+	"Error restoring sessions: %v\n":                                           "Error al restaurar sesiones: %v\n",
+	"Error emptying the trash: %v\n":                                           "Error al vaciar la papelera: %v\n",
+	"Error writing checksums: %v\n":                                            "Error al escribir las sumas de verificación: %v\n",
+	"Error verifying the log: %v\n":                                            "Error al verificar el registro: %v\n",
+	"Error setting estimate: %v\n":                                             "Error al definir la estimaci\u00f3n: %v\n",
+	"Error setting estimate: invalid duration %q\n":                            "Error al definir la estimaci\u00f3n: duraci\u00f3n %q no v\u00e1lida\n",
+	"Error removing estimate: %v\n":                                            "Error al eliminar la estimaci\u00f3n: %v\n",
+	"Error marking sessions: unknown mark %q (expected billed or synced)\n":    "Error al marcar sesiones: marca %q desconocida (se esperaba billed o synced)\n",
+	"Error marking sessions: give the ids of the sessions or select them with --from, --to or --task": "Error al marcar sesiones: indica los ids de las sesiones o selecci\u00f3nalas con --from, --to o --task",
+	"Error marking sessions: %v\n":                                                     "Error al marcar sesiones: %v\n",
+	"Warning: failed to mark the sessions of the report as billed: %v\n":               "Aviso: no se pudieron marcar las sesiones del informe como facturadas: %v\n",
+	"Error: --for cannot be combined with --pomodoro":                                  "Error: --for no se puede combinar con --pomodoro",
+	"Error: --at cannot be combined with taking over a session":                        "Error: --at no se puede combinar con continuar una sesión",
+	"Error: --days must be at least 1":                                                 "Error: --days debe ser al menos 1",
+	"Error: --switches, --hours, --weekdays and --pomodoros cannot be combined":        "Error: --switches, --hours, --weekdays y --pomodoros no se pueden combinar",
+	"Error: --width must be at least 24":                                               "Error: --width debe ser al menos 24",
+	"Error: give either an ICS file or --calendar-url":                                 "Error: indica un archivo ICS o --calendar-url",
+	"Error: invalid --from date %q (expected YYYY-MM-DD)\n":                            "Error: fecha de --from %q inválida (se esperaba AAAA-MM-DD)\n",
+	"Error: invalid --to date %q (expected YYYY-MM-DD)\n":                              "Error: fecha de --to %q inválida (se esperaba AAAA-MM-DD)\n",
+	"Error: invalid date %q (expected YYYY-MM-DD)\n":                                   "Error: fecha %q inválida (se esperaba AAAA-MM-DD)\n",
+	"Error: invalid idle threshold %q in config\n":                                     "Error: umbral de inactividad %q inválido en la configuración\n",
+	"Error: invalid on_unlock %q in config (expected resume or prompt)\n":              "Error: on_unlock %q inválido en la configuración (se esperaba resume o prompt)\n",
+	"Error: invalid remind_every %q in config\n":                                       "Error: remind_every %q inválido en la configuración\n",
+	"Error: requires at least 1 title, or --from-git":                                  "Error: se necesita al menos 1 título, o --from-git",
+	"Error: set account_id and token in the [harvest] section of the config file\n":    "Error: configura account_id y token en la sección [harvest] del archivo de configuración\n",
+	"Error: set url and token in the [jira] section of the config file\n":              "Error: configura url y token en la sección [jira] del archivo de configuración\n",
+	"Error: set workspace_id and token in the [clockify] section of the config file\n": "Error: configura workspace_id y token en la sección [clockify] del archivo de configuración\n",
+	"Invalid project name %q: names cannot contain dots or spaces\n":                   "Nombre de proyecto %q inválido: los nombres no pueden contener puntos ni espacios\n",
+	"Unknown project %q (register it with 'talogo project add')\n":                     "Proyecto %q desconocido (agrégalo con 'talogo project add')\n",
+	"Key %s is not set\n": "La clave %s no está definida\n",
+	"Invalid template name %q: names cannot be empty or contain dots or spaces\n": "Nombre de plantilla %q inválido: los nombres no pueden estar vacíos ni contener puntos o espacios\n",
+	"Invalid tag %q: tags cannot contain %q\n":                                    "Etiqueta %q inválida: las etiquetas no pueden contener %q\n",
+
+	// Warnings
+	"Warning: %v\n": "Aviso: %v\n",
+	"Warning: %s has no %s column, those values are not saved (run 'talogo migrate' to add them)\n": "Aviso: %s no tiene la columna %s, esos valores no se guardan (ejecuta 'talogo migrate' para agregarla)\n",
+	"Warning: failed to check for overlapping records: %v\n":                                        "Aviso: no se pudo comprobar si hay entradas superpuestas: %v\n",
+	"Warning: not detecting idle time: %v\n":                                                        "Aviso: no se detecta la inactividad: %v\n",
+	"Warning: not pausing on screen lock: %v\n":                                                     "Aviso: no se pausa al bloquear la pantalla: %v\n",
+	"Warning: skipped %d sessions without an id (run 'talogo migrate' to assign them)\n":            "Aviso: se omitieron %d sesiones sin id (ejecuta 'talogo migrate' para asignarlos)\n",
+	"Warning: the session overlaps line %d (%s - %s %s) by %s\n":                                    "Aviso: la sesión se superpone con la línea %d (%s - %s %s) durante %s\n",
+	"Warning: unsupported recurrence of %q (%s), only its first occurrence is imported\n":           "Aviso: repetición de %q no soportada (%s), solo se importa su primera ocurrencia\n",
+	"Warning: failed to send notification: %v\n":                                                    "Aviso: no se pudo enviar la notificación: %v\n",
+	"Warning: not showing goals: %v\n":                                                              "Aviso: no se muestran las metas: %v\n",
+	"Warning: not showing recent sessions: %v\n":                                                    "Aviso: no se muestran las sesiones recientes: %v\n",
+	"Warning: webhook %s failed: %v\n":                                                              "Aviso: falló el webhook %s: %v\n",
+	"Skipping %s: %v\n":                                                                             "Omitiendo %s: %v\n",
+	"Skipping %s: shorter than a minute\n":                                                          "Omitiendo %s: dura menos de un minuto\n",
+	"Skipping record on line %d: %s\n":                                                              "Omitiendo la entrada de la línea %d: %s\n",
+	"Skipping entry %s: %v\n":                                                                       "Omitiendo la entrada %s: %v\n",
+}
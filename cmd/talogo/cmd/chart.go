@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+	"github.com/spf13/cobra"
+	"github.com/wcharczuk/go-chart/v2"
+	"github.com/wcharczuk/go-chart/v2/drawing"
+)
+
+// chartBarColor is the color of the bars of daily totals
+var chartBarColor = drawing.ColorFromHex("4a90d9")
+
+var (
+	chartCmdLogFile string
+	chartCmdType    string
+	chartCmdOut     string
+	chartCmdFrom    string
+	chartCmdTo      string
+	chartCmdWidth   int
+	chartCmdHeight  int
+)
+
+// chartCmd defines the chart subcommand
+var chartCmd = &cobra.Command{
+	Use:   "chart",
+	Short: "Render the time of a period as an SVG or PNG chart",
+	Long: `Render the time tracked between --from and --to, by default the current
+week, as a standalone chart for slides and reports:
+
+  pie  share of each top-level task
+  bar  total of each day
+
+The image format follows the extension of --out, .svg or .png.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		from, to, err := reportDateRange(chartCmdFrom, chartCmdTo)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error rendering chart: %v\n"), err)
+			os.Exit(1)
+		}
+		report, err := buildReport(chartCmdLogFile, from, to, 0, false, false)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error rendering chart: %v\n"), err)
+			os.Exit(1)
+		}
+		if err := renderChart(report, chartCmdType, chartCmdOut, chartCmdWidth, chartCmdHeight); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error rendering chart: %v\n"), err)
+			os.Exit(1)
+		}
+		printInfo("Wrote %s\n", chartCmdOut)
+	},
+}
+
+func init() {
+	chartCmd.Flags().StringVarP(&chartCmdLogFile, "file", "f", defaultLogFile(), "Log file to read")
+	chartCmd.Flags().StringVar(&chartCmdType, "type", "bar", "Chart type (pie, bar)")
+	chartCmd.Flags().StringVarP(&chartCmdOut, "out", "o", "chart.svg", "Destination file, .svg or .png")
+	chartCmd.Flags().StringVar(&chartCmdFrom, "from", "", "First day of the chart (YYYY-MM-DD, default first day of this week)")
+	chartCmd.Flags().StringVar(&chartCmdTo, "to", "", "Last day of the chart (YYYY-MM-DD, default today)")
+	chartCmd.Flags().IntVar(&chartCmdWidth, "width", 1024, "Width of the image in pixels")
+	chartCmd.Flags().IntVar(&chartCmdHeight, "height", 640, "Height of the image in pixels")
+	rootCmd.AddCommand(chartCmd)
+}
+
+// renderer is a chart that can be rendered to an image
+type renderer interface {
+	Render(provider chart.RendererProvider, w io.Writer) error
+}
+
+// renderChart writes the chart of the given type of report to out, as SVG
+// or PNG by its extension
+func renderChart(report *talogo.Report, kind, out string, width, height int) error {
+	var provider chart.RendererProvider
+	switch strings.ToLower(filepath.Ext(out)) {
+	case ".svg":
+		provider = chart.SVG
+	case ".png":
+		provider = chart.PNG
+	default:
+		return fmt.Errorf("unknown image format of %s (expected .svg or .png)", out)
+	}
+	if width < 100 || height < 100 {
+		return fmt.Errorf("the chart must be at least 100x100 pixels")
+	}
+
+	var renderable renderer
+	switch kind {
+	case "pie":
+		renderable = taskPieChart(report, width, height)
+	case "bar":
+		renderable = dailyBarChart(report, width, height)
+	default:
+		return fmt.Errorf("unknown chart type %q (expected pie or bar)", kind)
+	}
+	if report.Total.TotalTime == 0 {
+		return fmt.Errorf("no time tracked between %s and %s", report.From, report.To)
+	}
+
+	file, err := os.Create(out)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	defer file.Close()
+	if err := renderable.Render(provider, file); err != nil {
+		return fmt.Errorf("failed to render chart: %v", err)
+	}
+	return file.Close()
+}
+
+// taskPieChart returns a pie chart of the share of each top-level task of
+// report
+func taskPieChart(report *talogo.Report, width, height int) *chart.PieChart {
+	var values []chart.Value
+	for _, name := range talogo.SortedTaskNames(report.Total.Children) {
+		hours := report.Total.Children[name].TotalTime.Hours()
+		values = append(values, chart.Value{
+			Value: hours,
+			Label: fmt.Sprintf("%s %.1f hs", name, hours),
+		})
+	}
+	// The pie chart draws its title over the pie, draw it above instead
+	title := func(r chart.Renderer, _ chart.Box, defaults chart.Style) {
+		chart.Draw.TextWithin(r, report.Title(), chart.Box{Top: 12, Left: 0, Right: width, Bottom: 50}, chart.Style{
+			FontColor:           chart.DefaultTextColor,
+			FontSize:            18,
+			TextHorizontalAlign: chart.TextHorizontalAlignCenter,
+			TextVerticalAlign:   chart.TextVerticalAlignTop,
+		}.InheritFrom(defaults))
+	}
+	return &chart.PieChart{
+		Width:      width,
+		Height:     height,
+		Background: chart.Style{Padding: chart.Box{Top: 60, Bottom: 10}},
+		Values:     values,
+		Elements:   []chart.Renderable{title},
+	}
+}
+
+// dailyBarChart returns a bar chart of the total of each day of report,
+// days without time included
+func dailyBarChart(report *talogo.Report, width, height int) *chart.BarChart {
+	var bars []chart.Value
+	first, _ := time.Parse("2006-01-02", report.From)
+	last, _ := time.Parse("2006-01-02", report.To)
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		var hours float64
+		if node, ok := report.Days[day.Format("2006-01-02")]; ok {
+			hours = node.TotalTime.Hours()
+		}
+		// Days without time get no bar, not a sliver
+		color := chartBarColor
+		if hours == 0 {
+			color = drawing.ColorTransparent
+		}
+		bars = append(bars, chart.Value{
+			Value: hours,
+			Label: day.Format("Mon 2"),
+			Style: chart.Style{FillColor: color, StrokeColor: color, StrokeWidth: 1},
+		})
+	}
+
+	// Bars share the width left by the axis, with a gap of a third
+	barWidth := max((width-120)*2/(3*len(bars)), 2)
+	return &chart.BarChart{
+		Title:      report.Title(),
+		Width:      width,
+		Height:     height,
+		Background: chart.Style{Padding: chart.Box{Top: 40}},
+		BarWidth:   barWidth,
+		BarSpacing: barWidth / 2,
+		YAxis: chart.YAxis{
+			ValueFormatter: func(v interface{}) string {
+				if hours, ok := v.(float64); ok {
+					return fmt.Sprintf("%.1f hs", hours)
+				}
+				return fmt.Sprint(v)
+			},
+		},
+		Bars: bars,
+	}
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncClockifyCmdLogFile string
+	syncClockifyCmdFrom    string
+	syncClockifyCmdTo      string
+	syncClockifyCmdDryRun  bool
+)
+
+// clockifyAPI is the base URL of the Clockify API
+const clockifyAPI = "https://api.clockify.me/api/v1"
+
+var syncClockifyCmd = &cobra.Command{
+	Use:   "clockify",
+	Short: "Create Clockify time entries for sessions of mapped tasks",
+	Long: `Create Clockify time entries for the sessions in the date range whose
+task is mapped to a project in the [clockify.projects] section of the
+config file, e.g.
+
+  [clockify]
+  workspace_id = "..."
+  token = "..."
+
+  [clockify.projects."client-x/coding"]
+  project_id = "..."
+  task_id = "..."     # optional
+  billable = true
+
+Synced sessions are remembered in a .clockify.json file next to the log,
+so they are never posted twice.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := loadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		if config.Clockify.WorkspaceID == "" || config.Clockify.Token == "" {
+			fmt.Fprint(os.Stderr, tr("Error: set workspace_id and token in the [clockify] section of the config file\n"))
+			os.Exit(1)
+		}
+
+		from, to, err := syncDateRange(syncClockifyCmdFrom, syncClockifyCmdTo)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+
+		if err := pushRecords(syncClockifyCmdLogFile, clockifyService(config.Clockify), from, to, syncClockifyCmdDryRun); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error syncing with Clockify: %v\n"), err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	syncClockifyCmd.Flags().StringVarP(&syncClockifyCmdLogFile, "file", "f", defaultLogFile(), "Log file to read")
+	syncClockifyCmd.Flags().StringVar(&syncClockifyCmdFrom, "from", "", "First day to sync (YYYY-MM-DD, default today)")
+	syncClockifyCmd.Flags().StringVar(&syncClockifyCmdTo, "to", "", "Last day to sync (YYYY-MM-DD, default today)")
+	syncClockifyCmd.Flags().BoolVar(&syncClockifyCmdDryRun, "dry-run", false, "Print the time entries that would be created without posting them")
+	syncCmd.AddCommand(syncClockifyCmd)
+}
+
+// clockifyService returns the Clockify time entry service of the
+// workspace in config
+func clockifyService(clockify ClockifyConfig) pushService {
+	headers := map[string]string{"X-Api-Key": clockify.Token}
+
+	return pushService{
+		name: "clockify",
+		target: func(record Record) string {
+			project, ok := longestPathMatch(clockify.Projects, record.Titles)
+			if !ok {
+				return ""
+			}
+			return "clockify project " + project.ProjectID
+		},
+		post: func(record Record, target string) (string, error) {
+			project, _ := longestPathMatch(clockify.Projects, record.Titles)
+			description := strings.Join(record.Titles, " / ")
+			if record.Notes != "" {
+				description += ": " + record.Notes
+			}
+			body := map[string]interface{}{
+				"start":       record.Start.UTC().Format(time.RFC3339),
+				"end":         record.Start.Add(record.Duration()).UTC().Format(time.RFC3339),
+				"projectId":   project.ProjectID,
+				"description": description,
+				"billable":    project.Billable,
+			}
+			if project.TaskID != "" {
+				body["taskId"] = project.TaskID
+			}
+
+			var entry struct {
+				ID string `json:"id"`
+			}
+			url := clockifyAPI + "/workspaces/" + clockify.WorkspaceID + "/time-entries"
+			if err := postJSON(url, body, headers, &entry); err != nil {
+				return "", err
+			}
+			return entry.ID, nil
+		},
+	}
+}
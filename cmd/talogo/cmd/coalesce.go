@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	coalesceCmdLogFile string
+	coalesceCmdDryRun  bool
+)
+
+// coalesceCmd defines the coalesce subcommand
+var coalesceCmd = &cobra.Command{
+	Use:   "coalesce GAP",
+	Short: "Merge back-to-back sessions of the same task in the log, e.g. 'coalesce 5m'",
+	Long: `Merge the sessions of the same task that start less than GAP after the
+previous one ended, on the same day, into a single record whose duration
+leaves out the gaps. The log is rewritten in order of start time, keeping
+the previous version in a .bak file.
+
+Reports can merge sessions without changing the log with --coalesce.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		gap, err := parseCoalesceGap(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		before, after, err := coalesceLog(coalesceCmdLogFile, gap, coalesceCmdDryRun)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error coalescing log: %v\n"), err)
+			os.Exit(1)
+		}
+		if coalesceCmdDryRun {
+			fmt.Printf(tr("Would merge %d records into %d\n"), before, after)
+			return
+		}
+		printInfo("Merged %d records into %d\n", before, after)
+	},
+}
+
+func init() {
+	coalesceCmd.Flags().StringVarP(&coalesceCmdLogFile, "file", "f", defaultLogFile(), "Log file to rewrite")
+	coalesceCmd.Flags().BoolVar(&coalesceCmdDryRun, "dry-run", false, "Print how many records would be merged without rewriting the log")
+	rootCmd.AddCommand(coalesceCmd)
+}
+
+// parseCoalesceGap parses the gap below which sessions are merged
+func parseCoalesceGap(value string) (time.Duration, error) {
+	gap, err := time.ParseDuration(value)
+	if err != nil || gap <= 0 {
+		return 0, fmt.Errorf("invalid gap %q (expected a duration such as 5m)", value)
+	}
+	return gap, nil
+}
+
+// coalesceRecords merges the back-to-back sessions of records separated
+// by less than gap, with the configured day start. A zero gap returns
+// records unchanged.
+func coalesceRecords(records []Record, gap time.Duration) ([]Record, error) {
+	if gap == 0 {
+		return records, nil
+	}
+	dayStart, err := configuredDayStart()
+	if err != nil {
+		return nil, err
+	}
+	return talogo.Coalesce(records, gap, dayStart), nil
+}
+
+// coalesceLog merges the back-to-back sessions of the log file separated
+// by less than gap, and returns the number of records before and after.
+// With dryRun the log is left untouched.
+func coalesceLog(logFile string, gap time.Duration, dryRun bool) (int, int, error) {
+	records, err := readRecordsStrict(logFile)
+	if err != nil {
+		return 0, 0, err
+	}
+	merged, err := coalesceRecords(records, gap)
+	if err != nil {
+		return 0, 0, err
+	}
+	if dryRun || len(merged) == len(records) {
+		return len(records), len(merged), nil
+	}
+	return len(records), len(merged), rewriteRecords(logFile, merged)
+}
@@ -0,0 +1,772 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/artilugio0/talogo/pkg/talogo"
+)
+
+// Config holds the user settings read from the config file
+type Config struct {
+	// Currency is the symbol printed next to money amounts
+	Currency string `toml:"currency"`
+	// Rates maps task paths (titles joined by "/") to hourly rates
+	Rates map[string]float64 `toml:"rates"`
+	// TagRates maps tags to hourly rates, used for records whose task has
+	// no rate
+	TagRates map[string]float64 `toml:"tag_rates"`
+	// Vacations lists absence days, either "2006-01-02" or a range
+	// "2006-01-02..2006-01-15"
+	Vacations []string `toml:"vacations"`
+	// Timezone is the IANA zone reports use to bucket records by day
+	Timezone string `toml:"timezone"`
+	// DayStart is the time of day ("04:00") at which a new day begins for
+	// day-splitting and reports, so late sessions count on the previous
+	// day. Midnight by default.
+	DayStart string `toml:"day_start"`
+	// WeekStart is the day ("monday", "sunday") weeks begin on in reports
+	// grouping by week and for the default period of the current week.
+	// Monday by default, as ISO weeks.
+	WeekStart string `toml:"week_start"`
+	// Locale formats the dates and numbers of reports (e.g. "es" for
+	// "Lunes 6 de mayo" and "7,50"). Reports use ISO dates and a decimal
+	// point if empty.
+	Locale string `toml:"locale"`
+	// Language is the language of messages and the interactive timer
+	// ("en", "es"), instead of the one of LANG
+	Language string `toml:"language"`
+	// Accessible runs the timer as with 'log --accessible', printing plain
+	// status lines for screen readers instead of redrawing the view
+	Accessible bool `toml:"accessible"`
+	// Mouse runs the timer as with 'log --mouse', with its actions as
+	// buttons to click
+	Mouse bool `toml:"mouse"`
+	// Review asks to correct the titles and notes of a session when the
+	// timer is stopped, as with 'log --review'
+	Review bool `toml:"review"`
+	// RecordHost and RecordUser store the machine hostname and user name
+	// in each new record, to tell apart logs merged from several machines
+	RecordHost bool `toml:"record_host"`
+	RecordUser bool `toml:"record_user"`
+	// Rounding writes new sessions in blocks of this length (e.g. "15m"),
+	// rounding their start down and their end up on the wall clock, for
+	// logs that must be kept in blocks. Sessions are written as tracked
+	// if empty.
+	Rounding string `toml:"rounding"`
+	// MinSession is the shortest session written to the log (e.g. "30s"),
+	// so accidental starts don't pollute reports. Every session is
+	// written if empty.
+	MinSession string `toml:"min_session"`
+	// ShortSessionTag writes sessions shorter than MinSession with this
+	// tag instead of discarding them, to be reviewed or pruned with
+	// 'talogo doctor --prune-short'
+	ShortSessionTag string `toml:"short_session_tag"`
+	// MaxSession is the longest time tracked in a session (e.g. "10h"),
+	// after which the timer stops by itself, as when it was forgotten.
+	// Sessions have no limit if empty.
+	MaxSession string `toml:"max_session"`
+	// OnMaxSession is either "stop", to save the session ending when it
+	// reached MaxSession (default), or "prompt", to pause it and ask what
+	// to do with the time since
+	OnMaxSession string `toml:"on_max_session"`
+	// TimeFormat is how times of day are printed: "24h" (15:04, default)
+	// or "12h" (3:04pm)
+	TimeFormat string `toml:"time_format"`
+	// Theme is the palette of colored output such as 'talogo timeline':
+	// "default", "light" for terminals with a light background, or
+	// "none" to leave colors out
+	Theme string `toml:"theme"`
+	// Keybindings replaces the keys of the timer of 'talogo log', by
+	// action: pause, switch and stop, e.g. pause = "space". Keys are
+	// single characters or "space", and ctrl+c always stops.
+	Keybindings map[string]string `toml:"keybindings"`
+	// Goals sets the time meant to be tracked per day and per week, shown
+	// by the timer and the summary
+	Goals GoalsConfig `toml:"goals"`
+	// Remote configures the storage used by 'sync remote'
+	Remote RemoteConfig `toml:"remote"`
+	// Jira configures the server 'sync jira' posts worklogs to
+	Jira JiraConfig `toml:"jira"`
+	// Harvest and Clockify configure 'sync harvest' and 'sync clockify'
+	Harvest  HarvestConfig  `toml:"harvest"`
+	Clockify ClockifyConfig `toml:"clockify"`
+	// Backup configures the backups directory and retention
+	Backup BackupConfig `toml:"backup"`
+	// PreciseTimestamps stores start and end times with sub-second
+	// precision (RFC3339Nano) and fractional durations, for very short
+	// measurements. Logs with either precision can always be read.
+	PreciseTimestamps bool `toml:"precise_timestamps"`
+	// Checksums keeps a hash chain of the records of the log in a
+	// .checksums file next to it, so 'talogo verify' detects edits made
+	// outside talogo and corruption
+	Checksums bool `toml:"checksums"`
+	// CSVDelimiter is the field delimiter of new CSV log files, a single
+	// character or "tab". Existing files keep their delimiter.
+	CSVDelimiter string `toml:"csv_delimiter"`
+	// Notifications configures desktop notifications
+	Notifications NotificationsConfig `toml:"notifications"`
+	// SMTP configures the server reports are emailed through
+	SMTP SMTPConfig `toml:"smtp"`
+	// DailyNote lists the logged sessions in Markdown daily notes
+	DailyNote DailyNoteConfig `toml:"daily_note"`
+	// Idle configures pausing sessions while away from the computer
+	Idle IdleConfig `toml:"idle"`
+	// Pomodoro configures the intervals of 'log --pomodoro'
+	Pomodoro PomodoroConfig `toml:"pomodoro"`
+	// Breaks logs the pauses of the timer as sessions of their own
+	Breaks BreaksConfig `toml:"breaks"`
+	// Gaps asks what to log the time between sessions of a day to, when
+	// a session starts long after the last one ended
+	Gaps GapsConfig `toml:"gaps"`
+	// Webhooks receive a JSON payload when sessions start, stop or are
+	// cancelled
+	Webhooks []WebhookConfig `toml:"webhooks"`
+	// Hooks are shell commands run when sessions start, stop or pause, and
+	// when their countdown is over
+	Hooks HooksConfig `toml:"hooks"`
+	// Titles controls how the titles of new records are written
+	Titles TitlesConfig `toml:"titles"`
+	// Aliases maps shorthand titles to the task paths (titles joined by
+	// "/") they stand for when starting a session, e.g. px =
+	// "work/project-x" makes 'talogo log px review' log
+	// work/project-x/review
+	Aliases map[string]string `toml:"aliases"`
+	// Templates maps names to the titles of the sessions logged with
+	// 'talogo log @name', see 'talogo template'
+	Templates map[string][]string `toml:"templates"`
+	// Estimates maps task paths (titles joined by "/") to the time they
+	// are expected to take, e.g. "work/project-x" = "40h", compared with
+	// the logged time by 'talogo estimate report'
+	Estimates map[string]string `toml:"estimates"`
+	// Projects maps project names to their log files, see 'talogo project'
+	Projects map[string]ProjectConfig `toml:"projects"`
+	// Flags holds default values for command line flags. Top level keys
+	// apply to the flags of every command, subtables named after a
+	// command path (e.g. [flags.summary]) apply to that command only.
+	Flags map[string]interface{} `toml:"flags"`
+}
+
+// RemoteConfig holds the location and credentials of the remote copy of
+// the log
+type RemoteConfig struct {
+	// URL is either s3://bucket/key or an http(s) WebDAV URL
+	URL string `toml:"url"`
+	// Username and Password authenticate WebDAV requests
+	Username string `toml:"username"`
+	Password string `toml:"password"`
+	// Region, Endpoint and the keys configure S3. Endpoint is only needed
+	// for S3 compatible services, keys default to AWS_ACCESS_KEY_ID and
+	// AWS_SECRET_ACCESS_KEY
+	Region          string `toml:"region"`
+	Endpoint        string `toml:"endpoint"`
+	AccessKeyID     string `toml:"access_key_id"`
+	SecretAccessKey string `toml:"secret_access_key"`
+}
+
+// JiraConfig holds the location and credentials of a Jira server
+type JiraConfig struct {
+	// URL is the base URL of the server, e.g. https://example.atlassian.net
+	URL string `toml:"url"`
+	// Email and Token authenticate Jira Cloud requests. Without an email
+	// the token is sent as a personal access token (Jira Data Center).
+	Email string `toml:"email"`
+	Token string `toml:"token"`
+}
+
+// HarvestConfig holds the Harvest account time entries are created in
+type HarvestConfig struct {
+	AccountID string `toml:"account_id"`
+	Token     string `toml:"token"`
+	// Projects maps task paths (titles joined by "/") to the project and
+	// task their sessions are logged to. The most specific path wins.
+	Projects map[string]HarvestProject `toml:"projects"`
+}
+
+// HarvestProject identifies a Harvest project and task
+type HarvestProject struct {
+	ProjectID int64 `toml:"project_id"`
+	TaskID    int64 `toml:"task_id"`
+}
+
+// ClockifyConfig holds the Clockify workspace time entries are created in
+type ClockifyConfig struct {
+	WorkspaceID string `toml:"workspace_id"`
+	Token       string `toml:"token"`
+	// Projects maps task paths (titles joined by "/") to the project and
+	// optional task their sessions are logged to. The most specific path
+	// wins.
+	Projects map[string]ClockifyProject `toml:"projects"`
+}
+
+// ClockifyProject identifies a Clockify project and optional task
+type ClockifyProject struct {
+	ProjectID string `toml:"project_id"`
+	TaskID    string `toml:"task_id"`
+	Billable  bool   `toml:"billable"`
+}
+
+// BackupConfig controls where backups are stored and how many are kept
+type BackupConfig struct {
+	// Dir is the backups directory, by default backups in the data
+	// directory
+	Dir string `toml:"dir"`
+	// Keep is the number of backups kept per log file, 10 by default
+	Keep int `toml:"keep"`
+	// Auto backs up the log before every command that rewrites it
+	Auto bool `toml:"auto"`
+}
+
+// NotificationsConfig controls desktop notifications
+type NotificationsConfig struct {
+	// Disabled turns off all desktop notifications
+	Disabled bool `toml:"disabled"`
+	// RemindEvery is the interval of the reminders sent while a session
+	// runs (e.g. "2h"), none if empty
+	RemindEvery string `toml:"remind_every"`
+}
+
+// SMTPConfig holds the mail server and addresses used to email reports
+type SMTPConfig struct {
+	// Host and Port of the server. Port 465 uses implicit TLS, other
+	// ports upgrade the connection with STARTTLS when the server offers
+	// it. Port defaults to 587.
+	Host string `toml:"host"`
+	Port int    `toml:"port"`
+	// Username and Password authenticate with the server, if set
+	Username string `toml:"username"`
+	Password string `toml:"password"`
+	// From is the sender address and To the default recipients
+	From string   `toml:"from"`
+	To   []string `toml:"to"`
+}
+
+// DailyNoteConfig locates the daily notes sessions are appended to, such
+// as the daily notes of an Obsidian vault
+type DailyNoteConfig struct {
+	// Path is the note of each day. Text in braces is replaced by the
+	// date, formatted with that Go time layout, e.g.
+	// "~/vault/Daily/{2006-01-02}.md". Nothing is written if empty.
+	Path string `toml:"path"`
+	// Heading is the section sessions are listed under, e.g. "## Time
+	// log", added at the end of the note if missing. Without heading
+	// sessions are appended at the end of the note.
+	Heading string `toml:"heading"`
+}
+
+// PomodoroConfig holds the lengths of the intervals of 'log --pomodoro',
+// which its flags override for a session
+type PomodoroConfig struct {
+	// Work is the length of the pomodoros (e.g. "50m"), 25m if empty
+	Work string `toml:"work"`
+	// ShortBreak is the length of the breaks between pomodoros, 5m if
+	// empty
+	ShortBreak string `toml:"short_break"`
+	// LongBreak is the length of the break taking the place of the short
+	// one every LongBreakEvery pomodoros, 15m if empty
+	LongBreak string `toml:"long_break"`
+	// LongBreakEvery is how many pomodoros go before a long break, 4 if
+	// not set
+	LongBreakEvery int `toml:"long_break_every"`
+}
+
+// BreaksConfig holds the tasks, as titles joined by "/", the breaks of
+// the timer are logged to when it resumes. Breaks are the pauses taken
+// with p and the pomodoro breaks, not the time away when idle or with the
+// screen locked.
+type BreaksConfig struct {
+	// Task is the task of the pauses, e.g. "break", and of pomodoro
+	// breaks without a task of their own. Breaks are not logged if empty.
+	Task string `toml:"task"`
+	// ShortTask is the task of the short pomodoro breaks, e.g.
+	// "break/short"
+	ShortTask string `toml:"short_task"`
+	// LongTask is the task of the long pomodoro breaks, e.g. "break/long"
+	LongTask string `toml:"long_task"`
+}
+
+// GoalsConfig holds the time meant to be tracked per period
+type GoalsConfig struct {
+	// Daily is the goal of each day, e.g. "8h", none if empty
+	Daily string `toml:"daily"`
+	// Weekly is the goal of each week, e.g. "40h", none if empty. Weeks
+	// begin on week_start.
+	Weekly string `toml:"weekly"`
+}
+
+// GapsConfig controls the question 'talogo log' asks about the untracked
+// time since the last session of the day
+type GapsConfig struct {
+	// Threshold is the shortest gap asked about, e.g. "30m". Gaps are
+	// left untracked without asking if empty.
+	Threshold string `toml:"threshold"`
+	// Tasks are offered as answers, as titles joined by "/", e.g.
+	// ["lunch", "break"]
+	Tasks []string `toml:"tasks"`
+}
+
+// IdleConfig controls pausing sessions while away from the computer
+type IdleConfig struct {
+	// Threshold is how long the keyboard and mouse must go unused for the
+	// running session to pause (e.g. "5m"), never if empty. On return
+	// talogo asks whether to keep, discard or reassign the idle time.
+	Threshold string `toml:"threshold"`
+	// PauseOnLock pauses the running session while the screen is locked.
+	// Only supported on Linux, through logind and the D-Bus screensaver
+	// interface.
+	PauseOnLock bool `toml:"pause_on_lock"`
+	// OnUnlock is either "resume", to continue tracking when the screen is
+	// unlocked (default), or "prompt", to ask what to do with the session
+	OnUnlock string `toml:"on_unlock"`
+}
+
+// HooksConfig holds the shell commands run on session events. They get
+// the session in TALOGO_EVENT and TALOGO_SESSION_* environment variables,
+// e.g. on_start = "makoctl mode -a do-not-disturb".
+type HooksConfig struct {
+	// OnStart runs when a session starts
+	OnStart string `toml:"on_start"`
+	// OnStop runs when a session is saved
+	OnStop string `toml:"on_stop"`
+	// OnPause runs when a session is paused, by hand or while away
+	OnPause string `toml:"on_pause"`
+	// OnExpire runs when the countdown of 'log --for' is over, e.g. to
+	// play a sound or lock the screen
+	OnExpire string `toml:"on_expire"`
+}
+
+// TitlesConfig controls how the titles of new records are written, and
+// the rules the titles of new sessions and renamed tasks must follow
+type TitlesConfig struct {
+	// KeepControlCharacters writes titles as given. By default line
+	// breaks, tabs and other control characters become spaces, so each
+	// title fits in a single line.
+	KeepControlCharacters bool `toml:"keep_control_characters"`
+	// Case converts titles to "lower" or "upper" case, as typed if empty
+	Case string `toml:"case"`
+	// MaxDepth is the most titles a session can have, e.g. 3 allows
+	// client/project/task. No limit if 0.
+	MaxDepth int `toml:"max_depth"`
+	// MaxLength is the most characters a title can have. No limit if 0.
+	MaxLength int `toml:"max_length"`
+	// ForbiddenCharacters lists the characters titles cannot contain,
+	// e.g. ",;#"
+	ForbiddenCharacters string `toml:"forbidden_characters"`
+}
+
+// WebhookConfig describes a URL notified of session events
+type WebhookConfig struct {
+	// URL receives a POST request with the event payload
+	URL string `toml:"url"`
+	// Events limits the events sent (start, stop, cancel), all if empty
+	Events []string `toml:"events"`
+	// Template is a Go template producing the request body, executed with
+	// the fields of the default JSON payload (e.g. {{.Task}}, {{.Event}},
+	// {{.DurationMinutes}}). The json function quotes values, e.g.
+	// {"text": {{json .Task}}}. The default payload is sent if empty.
+	Template string `toml:"template"`
+	// ContentType of the request body, application/json by default
+	ContentType string `toml:"content_type"`
+}
+
+// ProjectConfig describes a named project with its own log file
+type ProjectConfig struct {
+	// File is the log file of the project
+	File string `toml:"file"`
+	// Dir, if set, makes the project active while working inside it
+	Dir string `toml:"dir"`
+}
+
+var loadedConfig *Config
+
+// configFile returns the path of the config file: $TALOGO_CONFIG if set,
+// otherwise talogo/config.toml in the user config directory
+func configFile() (string, error) {
+	if path := os.Getenv("TALOGO_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find config directory: %v", err)
+	}
+	return filepath.Join(dir, "talogo", "config.toml"), nil
+}
+
+// loadConfig reads the config file once and returns it. A missing config
+// file results in an empty config.
+func loadConfig() (*Config, error) {
+	if loadedConfig != nil {
+		return loadedConfig, nil
+	}
+
+	config := &Config{}
+	path, err := configFile()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := toml.DecodeFile(path, config); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read config file %s: %v", path, err)
+	}
+
+	loadedConfig = config
+	return config, nil
+}
+
+// flagDefault returns the configured default for the named flag of the
+// command at path (e.g. ["summary"]), preferring command specific values
+func (c *Config) flagDefault(path []string, name string) (interface{}, bool) {
+	table := c.Flags
+	var found interface{}
+	ok := false
+	if v, exists := table[name]; exists {
+		if _, isTable := v.(map[string]interface{}); !isTable {
+			found, ok = v, true
+		}
+	}
+	for _, p := range path {
+		sub, isTable := table[p].(map[string]interface{})
+		if !isTable {
+			break
+		}
+		table = sub
+		if v, exists := table[name]; exists {
+			if _, isTable := v.(map[string]interface{}); !isTable {
+				found, ok = v, true
+			}
+		}
+	}
+	return found, ok
+}
+
+// expandHome replaces a leading "~/" in path with the home directory
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
+
+// rateFor returns the hourly rate of the most specific task path of titles
+// that has a rate configured, falling back to the rate of the first tag
+// that has one. It also reports whether any rate was found.
+func (c *Config) rateFor(titles, tags []string) (float64, bool) {
+	for i := len(titles); i > 0; i-- {
+		if rate, ok := c.Rates[strings.Join(titles[:i], "/")]; ok {
+			return rate, true
+		}
+	}
+	for _, tag := range tags {
+		if rate, ok := c.TagRates[tag]; ok {
+			return rate, true
+		}
+	}
+	return 0, false
+}
+
+// isVacation reports whether the date of t falls in a configured vacation
+func (c *Config) isVacation(t time.Time) (bool, error) {
+	date := t.Format("2006-01-02")
+	for _, vacation := range c.Vacations {
+		from, to, isRange := strings.Cut(vacation, "..")
+		if !isRange {
+			to = from
+		}
+		for _, d := range []string{from, to} {
+			if _, err := time.Parse("2006-01-02", d); err != nil {
+				return false, fmt.Errorf("invalid vacation date %q in config", vacation)
+			}
+		}
+		// Dates in YYYY-MM-DD format can be compared lexicographically
+		if from <= date && date <= to {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// dayStart returns the configured day boundary as an offset from midnight
+func (c *Config) dayStart() (time.Duration, error) {
+	if c.DayStart == "" {
+		return 0, nil
+	}
+	t, err := time.Parse("15:04", c.DayStart)
+	if err != nil {
+		return 0, fmt.Errorf("invalid day_start %q in config (expected HH:MM)", c.DayStart)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// weekStart returns the configured first day of the week, Monday if none
+func (c *Config) weekStart() (time.Weekday, error) {
+	if c.WeekStart == "" {
+		return time.Monday, nil
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(c.WeekStart, day.String()) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid week_start %q in config (expected a day such as monday or sunday)", c.WeekStart)
+}
+
+// clockLayout is the layout times of day are printed with, set from the
+// time_format of the config when a command starts
+var clockLayout = "15:04"
+
+// applyTimeFormat sets clockLayout from the time_format of the config
+func applyTimeFormat() error {
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	layout, err := config.timeFormat()
+	if err != nil {
+		return err
+	}
+	clockLayout = layout
+	return nil
+}
+
+// timeFormat returns the layout times of day are printed with, as set by
+// time_format
+func (c *Config) timeFormat() (string, error) {
+	switch c.TimeFormat {
+	case "", "24h":
+		return "15:04", nil
+	case "12h":
+		return "3:04pm", nil
+	}
+	return "", fmt.Errorf("invalid time_format %q in config (expected 24h or 12h)", c.TimeFormat)
+}
+
+// palette returns the ANSI colors of the configured theme, none if colors
+// are left out
+func (c *Config) palette() ([]string, error) {
+	name := c.Theme
+	if name == "" {
+		name = "default"
+	}
+	colors, ok := themes[name]
+	if !ok {
+		return nil, fmt.Errorf("invalid theme %q in config (expected default, light or none)", c.Theme)
+	}
+	return colors, nil
+}
+
+// timerKeys returns the keys of the timer actions, replaced by those of
+// keybindings
+func (c *Config) timerKeys() (timerKeys, error) {
+	keys := defaultTimerKeys
+	for action, key := range c.Keybindings {
+		if key == "space" {
+			key = " "
+		}
+		if len([]rune(key)) != 1 {
+			return keys, fmt.Errorf("invalid key %q for %s in keybindings in config (expected a single character or space)", key, action)
+		}
+		switch action {
+		case "pause":
+			keys.pause = key
+		case "switch":
+			keys.switchTask = key
+		case "stop":
+			keys.stop = key
+		default:
+			return keys, fmt.Errorf("unknown action %q in keybindings in config (expected pause, switch or stop)", action)
+		}
+	}
+	if keys.pause == keys.switchTask || keys.pause == keys.stop || keys.switchTask == keys.stop {
+		return keys, fmt.Errorf("keybindings in config give two actions the same key")
+	}
+	return keys, nil
+}
+
+// goals returns the configured daily and weekly goals, 0 if not set
+func (c *Config) goals() (daily, weekly time.Duration, err error) {
+	if daily, err = parseGoal("daily", c.Goals.Daily); err != nil {
+		return 0, 0, err
+	}
+	if weekly, err = parseGoal("weekly", c.Goals.Weekly); err != nil {
+		return 0, 0, err
+	}
+	return daily, weekly, nil
+}
+
+// parseGoal parses the goal of the named period, 0 if empty
+func parseGoal(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid %s goal %q in config", name, value)
+	}
+	return d, nil
+}
+
+// rounding returns the configured block length sessions are written in,
+// 0 if none
+func (c *Config) rounding() (time.Duration, error) {
+	if c.Rounding == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(c.Rounding)
+	if err != nil || d <= 0 || d > 24*time.Hour {
+		return 0, fmt.Errorf("invalid rounding %q in config", c.Rounding)
+	}
+	return d, nil
+}
+
+// maxSession returns the configured maximum session duration, 0 if none
+func (c *Config) maxSession() (time.Duration, error) {
+	if c.MaxSession == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(c.MaxSession)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid max_session %q in config", c.MaxSession)
+	}
+	return d, nil
+}
+
+// gapThreshold returns the shortest gap between sessions asked about, 0
+// if gaps are not asked about
+func (c *Config) gapThreshold() (time.Duration, error) {
+	if c.Gaps.Threshold == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(c.Gaps.Threshold)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid gaps threshold %q in config", c.Gaps.Threshold)
+	}
+	return d, nil
+}
+
+// estimates returns the estimated time of each task path of the config
+func (c *Config) estimates() (map[string]time.Duration, error) {
+	estimates := make(map[string]time.Duration, len(c.Estimates))
+	for task, value := range c.Estimates {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid estimate %q for %s in config", value, task)
+		}
+		estimates[strings.Trim(task, "/")] = d
+	}
+	return estimates, nil
+}
+
+// minSession returns the configured minimum session duration, 0 if none
+func (c *Config) minSession() (time.Duration, error) {
+	if c.MinSession == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(c.MinSession)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid min_session %q in config", c.MinSession)
+	}
+	return d, nil
+}
+
+// pomodoroCycle returns the configured lengths of the pomodoro intervals,
+// by default 25m pomodoros with 5m breaks and a 15m break every 4
+func (c *Config) pomodoroCycle() (pomodoroCycle, error) {
+	cycle := pomodoroCycle{
+		Work:           25 * time.Minute,
+		ShortBreak:     5 * time.Minute,
+		LongBreak:      15 * time.Minute,
+		LongBreakEvery: 4,
+	}
+	lengths := []struct {
+		name   string
+		value  string
+		length *time.Duration
+	}{
+		{"work", c.Pomodoro.Work, &cycle.Work},
+		{"short_break", c.Pomodoro.ShortBreak, &cycle.ShortBreak},
+		{"long_break", c.Pomodoro.LongBreak, &cycle.LongBreak},
+	}
+	for _, l := range lengths {
+		if l.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(l.value)
+		if err != nil || d <= 0 {
+			return cycle, fmt.Errorf("invalid pomodoro %s %q in config", l.name, l.value)
+		}
+		*l.length = d
+	}
+	if c.Pomodoro.LongBreakEvery < 0 {
+		return cycle, fmt.Errorf("invalid pomodoro long_break_every %d in config", c.Pomodoro.LongBreakEvery)
+	}
+	if c.Pomodoro.LongBreakEvery > 0 {
+		cycle.LongBreakEvery = c.Pomodoro.LongBreakEvery
+	}
+	return cycle, nil
+}
+
+// configuredDayStart loads the config and returns its day boundary
+func configuredDayStart() (time.Duration, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return 0, err
+	}
+	return config.dayStart()
+}
+
+// configuredWeekStart loads the config and returns its first day of the
+// week
+func configuredWeekStart() (time.Weekday, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return 0, err
+	}
+	return config.weekStart()
+}
+
+// reportLocale returns the locale reports are formatted with: the locale
+// flag value if given, otherwise the configured one. A nil locale means
+// ISO dates and a decimal point.
+func reportLocale(flag string) (*talogo.Locale, error) {
+	name := flag
+	if name == "" {
+		config, err := loadConfig()
+		if err != nil {
+			return nil, err
+		}
+		name = config.Locale
+	}
+	if name == "" {
+		return nil, nil
+	}
+	return talogo.LookupLocale(name)
+}
+
+// reportLocation returns the location reports should convert timestamps
+// to: the tz flag value if given, otherwise the configured timezone. A nil
+// location means timestamps keep the offset they were logged with.
+func reportLocation(tz string) (*time.Location, error) {
+	if tz == "" {
+		config, err := loadConfig()
+		if err != nil {
+			return nil, err
+		}
+		tz = config.Timezone
+	}
+	if tz == "" {
+		return nil, nil
+	}
+
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %v", tz, err)
+	}
+	return loc, nil
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/cobra"
+)
+
+// configCmd defines the config subcommand
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Read and write settings in the config file",
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [KEY]",
+	Short: "Print a config value, or the whole config if no key is given",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		values, _, err := readConfigTable()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error reading config: %v\n"), err)
+			os.Exit(1)
+		}
+
+		var value interface{} = values
+		if len(args) == 1 {
+			var ok bool
+			value, ok = lookupConfigKey(values, strings.Split(args[0], "."))
+			if !ok {
+				fmt.Fprintf(os.Stderr, tr("Key %s is not set\n"), args[0])
+				os.Exit(1)
+			}
+		}
+
+		if table, ok := value.(map[string]interface{}); ok {
+			if err := toml.NewEncoder(os.Stdout).Encode(table); err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error printing config: %v\n"), err)
+				os.Exit(1)
+			}
+			return
+		}
+		fmt.Println(value)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set KEY VALUE",
+	Short: "Set a config value, e.g. 'config set flags.file ~/talogo.csv'",
+	Long: `Set a config value. Nested keys are separated by dots, e.g.
+'config set rates.work 50'. Values are stored as booleans or numbers when
+they parse as such, and as strings otherwise. Comments in the config file
+are not preserved.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		values, path, err := readConfigTable()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error reading config: %v\n"), err)
+			os.Exit(1)
+		}
+
+		if err := setConfigKey(values, strings.Split(args[0], "."), parseConfigValue(args[1])); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error setting %s: %v\n"), args[0], err)
+			os.Exit(1)
+		}
+
+		if err := writeConfigTable(path, values); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error writing config: %v\n"), err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// readConfigTable reads the config file as a generic table. It also
+// returns the path of the config file.
+func readConfigTable() (map[string]interface{}, string, error) {
+	path, err := configFile()
+	if err != nil {
+		return nil, "", err
+	}
+	values := make(map[string]interface{})
+	if _, err := toml.DecodeFile(path, &values); err != nil && !os.IsNotExist(err) {
+		return nil, "", fmt.Errorf("failed to read config file %s: %v", path, err)
+	}
+	return values, path, nil
+}
+
+// writeConfigTable writes values to the config file at path, through a
+// temporary file renamed over it. The config holds tokens and passwords,
+// so a new file is only readable by the user, and an existing one keeps
+// its permissions.
+func writeConfigTable(path string, values map[string]interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %v", err)
+	}
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+		// Replace the target of a symlinked config, not the link
+		if path, err = filepath.EvalSymlinks(path); err != nil {
+			return fmt.Errorf("failed to resolve config file: %v", err)
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create config file: %v", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+	defer tmp.Close()
+
+	if err := tmp.Chmod(mode); err != nil {
+		return fmt.Errorf("failed to set config file permissions: %v", err)
+	}
+	if err := toml.NewEncoder(tmp).Encode(values); err != nil {
+		return fmt.Errorf("failed to write config file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write config file: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace config file: %v", err)
+	}
+	return nil
+}
+
+// lookupConfigKey returns the value at the dotted key path of values
+func lookupConfigKey(values map[string]interface{}, key []string) (interface{}, bool) {
+	var value interface{} = values
+	for _, k := range key {
+		table, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = table[k]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// setConfigKey sets the value at the dotted key path of values, creating
+// the intermediate tables
+func setConfigKey(values map[string]interface{}, key []string, value interface{}) error {
+	table := values
+	for i, k := range key[:len(key)-1] {
+		next, exists := table[k]
+		if !exists {
+			next = make(map[string]interface{})
+			table[k] = next
+		}
+		sub, ok := next.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s is not a table", strings.Join(key[:i+1], "."))
+		}
+		table = sub
+	}
+	table[key[len(key)-1]] = value
+	return nil
+}
+
+// updateConfigTable sets the value at the dotted key path of the config
+// file, or deletes the key if value is nil, and writes the file back
+func updateConfigTable(key []string, value interface{}) error {
+	values, path, err := readConfigTable()
+	if err != nil {
+		return err
+	}
+
+	if value == nil {
+		parent, ok := lookupConfigKey(values, key[:len(key)-1])
+		table, isTable := parent.(map[string]interface{})
+		if !ok || !isTable {
+			return fmt.Errorf("%s is not set", strings.Join(key, "."))
+		}
+		if _, exists := table[key[len(key)-1]]; !exists {
+			return fmt.Errorf("%s is not set", strings.Join(key, "."))
+		}
+		delete(table, key[len(key)-1])
+	} else if err := setConfigKey(values, key, value); err != nil {
+		return err
+	}
+
+	return writeConfigTable(path, values)
+}
+
+// parseConfigValue converts a command line value to the most specific TOML
+// type it represents
+func parseConfigValue(s string) interface{} {
+	if b, err := strconv.ParseBool(s); err == nil {
+		return b
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return s
+}
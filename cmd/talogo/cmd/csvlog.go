@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+)
+
+// readCSVLayout returns the schema version and the header of a CSV log
+// file, set to write times with the configured precision. Files without a
+// version line are version 1.
+func readCSVLayout(logFile string) (int, talogo.CSVSchema, error) {
+	file, err := os.Open(logFile)
+	if err != nil {
+		return 0, talogo.CSVSchema{}, fmt.Errorf("failed to open CSV file: %v", err)
+	}
+	defer file.Close()
+
+	version, schema, err := talogo.ReadCSVLayout(file)
+	schema.Precision = logPrecision()
+	return version, schema, err
+}
+
+// scanCSVRecords calls fn for each valid record of a CSV log file, and
+// onBad for each invalid one
+func scanCSVRecords(logFile string, onBad talogo.BadRecordFunc, fn func(Record) error) error {
+	file, err := os.Open(logFile)
+	if err != nil {
+		return fmt.Errorf("failed to open CSV file: %v", err)
+	}
+	defer file.Close()
+
+	return talogo.ScanCSV(file, onBad, fn)
+}
+
+// appendCSVRecords appends records to a CSV log file, creating it with a
+// header if it does not exist. Records are written following the layout of
+// the existing header; columns missing from it are not written.
+func appendCSVRecords(logFile string, records []Record) error {
+	// Ensure file is created with proper permissions
+	file, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open/create CSV file: %v", err)
+	}
+	defer file.Close()
+
+	// Check if file is empty to add header
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %v", err)
+	}
+
+	writer := csv.NewWriter(file)
+
+	var schema talogo.CSVSchema
+	if fileInfo.Size() > 0 {
+		// Read the existing header to follow its layout and delimiter
+		existing := io.NewSectionReader(file, 0, fileInfo.Size())
+		comma, err := talogo.DetectCSVDelimiter(existing)
+		if err != nil {
+			return err
+		}
+		headers, err := talogo.NewCSVReader(existing, comma).Read()
+		if err != nil {
+			return fmt.Errorf("failed to read CSV headers: %v", err)
+		}
+		schema = talogo.NewCSVSchema(headers)
+		schema.Comma = comma
+		writer.Comma = comma
+
+		// A log edited by hand may lack the final newline, which would
+		// glue the first appended record to the last one
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, fileInfo.Size()-1); err != nil {
+			return fmt.Errorf("failed to read CSV file: %v", err)
+		}
+		if last[0] != '\n' {
+			if _, err := file.Write([]byte("\n")); err != nil {
+				return fmt.Errorf("failed to write to CSV file: %v", err)
+			}
+		}
+	} else {
+		config, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		schema = talogo.CanonicalSchema(records)
+		if config.CSVDelimiter != "" {
+			if schema.Comma, err = talogo.ParseCSVDelimiter(config.CSVDelimiter); err != nil {
+				return err
+			}
+		}
+		writer.Comma = schema.Comma
+		if _, err := fmt.Fprintf(file, "%s%d\n", talogo.CSVVersionPrefix, talogo.CSVSchemaVersion); err != nil {
+			return fmt.Errorf("failed to write CSV header: %v", err)
+		}
+		if err := writer.Write(schema.Header()); err != nil {
+			return fmt.Errorf("failed to write CSV header: %v", err)
+		}
+	}
+	schema.Precision = logPrecision()
+
+	for _, record := range records {
+		if missing := schema.MissingColumns(record); len(missing) > 0 {
+			fmt.Fprintf(os.Stderr, tr("Warning: %s has no %s column, those values are not saved (run 'talogo migrate' to add them)\n"),
+				logFile, strings.Join(missing, ", "))
+		}
+		if err := writer.Write(schema.Row(record)); err != nil {
+			return fmt.Errorf("failed to write CSV record: %v", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV record: %v", err)
+	}
+
+	// Ensure all data is written to disk
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync file: %v", err)
+	}
+
+	return nil
+}
+
+// csvStore is the default backend, a CSV file
+type csvStore struct {
+	path string
+}
+
+func (s csvStore) Append(records []Record) error {
+	return appendCSVRecords(s.path, records)
+}
+
+func (s csvStore) List(onBad talogo.BadRecordFunc, fn func(Record) error) error {
+	return scanCSVRecords(s.path, onBad, fn)
+}
+
+func (s csvStore) Query(from, to time.Time, fn func(Record) error) error {
+	return queryByScanning(s, from, to, fn)
+}
+
+// Rewrite keeps the schema version and layout of the existing file
+func (s csvStore) Rewrite(records []Record) error {
+	version, schema, err := readCSVLayout(s.path)
+	if err != nil {
+		return err
+	}
+	return replaceLogFile(s.path, func(w io.Writer) error {
+		return talogo.WriteCSVLog(w, version, schema, records)
+	})
+}
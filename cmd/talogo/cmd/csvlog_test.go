@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAppendCSVRecordsWithoutFinalNewline(t *testing.T) {
+	useConfig(t, &Config{})
+	logFile := writeTestLog(t, `# talogo schema v6
+id,start_time,end_time,duration_seconds,source,tags,notes,host,user,title1,title2
+a1,2026-09-01T09:00:00Z,2026-09-01T10:00:00Z,3600,add,,,,,work,a`)
+
+	start := time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC)
+	record := Record{ID: "a2", Start: start, End: start.Add(time.Hour), Titles: []string{"work", "b"}, Source: "add"}
+	if err := appendCSVRecords(logFile, []Record{record}); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := readRecordsStrict(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].ID != "a1" || records[1].ID != "a2" {
+		t.Fatalf("log holds %+v, want records a1 and a2", records)
+	}
+}
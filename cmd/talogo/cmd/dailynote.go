@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+)
+
+// dailyNoteLayoutPattern matches the date layouts in braces of a daily
+// note path
+var dailyNoteLayoutPattern = regexp.MustCompile(`\{([^}]*)\}`)
+
+// appendToDailyNotes lists records in the daily note of their day, if
+// daily notes are configured. Failures are reported as warnings and never
+// fail the command.
+func appendToDailyNotes(records []Record) {
+	config, err := loadConfig()
+	if err != nil || config.DailyNote.Path == "" {
+		return
+	}
+	dayStart, err := config.dayStart()
+	if err != nil {
+		return
+	}
+	for _, record := range records {
+		if err := appendDailyNote(config.DailyNote, record, dayStart); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Warning: %v\n"), err)
+		}
+	}
+}
+
+// appendDailyNote adds a line for record to the daily note of its day
+func appendDailyNote(note DailyNoteConfig, record Record, dayStart time.Duration) error {
+	day, err := time.ParseInLocation("2006-01-02", talogo.DayOf(record.Start, dayStart), record.Start.Location())
+	if err != nil {
+		return err
+	}
+	path := dailyNotePath(note.Path, day)
+
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read daily note: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create daily note directory: %v", err)
+	}
+	updated := insertUnderHeading(string(content), note.Heading, dailyNoteLine(record))
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("failed to write daily note: %v", err)
+	}
+	return nil
+}
+
+// dailyNotePath returns the path of the daily note of day, replacing the
+// layouts in braces of pattern with the formatted date
+func dailyNotePath(pattern string, day time.Time) string {
+	path := dailyNoteLayoutPattern.ReplaceAllStringFunc(pattern, func(layout string) string {
+		return day.Format(layout[1 : len(layout)-1])
+	})
+	return expandHome(path)
+}
+
+// dailyNoteLine formats record as a list item such as
+// "- 09:10–10:25 work/project-x (1h15m): notes"
+func dailyNoteLine(record Record) string {
+	line := fmt.Sprintf("- %s–%s %s (%s)", record.Start.Format("15:04"), record.End.Format("15:04"),
+		strings.Join(record.Titles, "/"), formatShortDuration(record.Duration()))
+	if notes := strings.Join(strings.Fields(record.Notes), " "); notes != "" {
+		line += ": " + notes
+	}
+	return line
+}
+
+// formatShortDuration formats d rounded to minutes, e.g. 1h15m, 45m or 2h
+func formatShortDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	switch {
+	case minutes < 60:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh%dm", minutes/60, minutes%60)
+}
+
+// insertUnderHeading adds line at the end of the section of content
+// starting at heading, after its last non blank line. The heading is
+// added at the end of content if missing, and line is appended at the end
+// of content if heading is empty.
+func insertUnderHeading(content, heading, line string) string {
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	heading = strings.TrimSpace(heading)
+	if heading == "" {
+		return content + line + "\n"
+	}
+
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	start := -1
+	for i, l := range lines {
+		if strings.TrimSpace(l) == heading {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		if content != "" {
+			content += "\n"
+		}
+		return content + heading + "\n" + line + "\n"
+	}
+
+	// The section ends at the next heading of the same or a higher level
+	level := headingLevel(heading)
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		if l := headingLevel(lines[i]); l > 0 && (level == 0 || l <= level) {
+			end = i
+			break
+		}
+	}
+	at := end
+	for at > start+1 && strings.TrimSpace(lines[at-1]) == "" {
+		at--
+	}
+
+	lines = append(lines[:at], append([]string{line}, lines[at:]...)...)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// headingLevel returns the level of a Markdown heading line, 0 if line is
+// not a heading
+func headingLevel(line string) int {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	if level == 0 || level > 6 || !strings.HasPrefix(line[level:], " ") {
+		return 0
+	}
+	return level
+}
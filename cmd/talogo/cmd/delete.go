@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	deleteCmdLogFile string
+)
+
+// deleteCmd defines the delete subcommand
+var deleteCmd = &cobra.Command{
+	Use:   "delete ID...",
+	Short: "Delete sessions by id (unique id prefixes are accepted)",
+	Long: `Delete sessions by id (unique id prefixes are accepted). Deleted
+sessions are moved to the trash of the log, to be restored with 'talogo
+trash restore' if deleted by mistake.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := deleteRecords(deleteCmdLogFile, args); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error deleting records: %v\n"), err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	deleteCmd.Flags().StringVarP(&deleteCmdLogFile, "file", "f", defaultLogFile(), "Log file to rewrite")
+	rootCmd.AddCommand(deleteCmd)
+}
+
+// deleteRecords removes the records with the given ids from the log
+func deleteRecords(logFile string, ids []string) error {
+	records, err := readRecordsStrict(logFile)
+	if err != nil {
+		return err
+	}
+
+	deleted := make(map[int]bool)
+	for _, id := range ids {
+		i, err := findRecord(records, id)
+		if err != nil {
+			return err
+		}
+		deleted[i] = true
+	}
+
+	var kept []Record
+	for i, record := range records {
+		if deleted[i] {
+			if !quiet {
+				printRecord(record)
+			}
+			continue
+		}
+		kept = append(kept, record)
+	}
+
+	if err := rewriteRecords(logFile, kept); err != nil {
+		return err
+	}
+	printInfo("Deleted %d records\n", len(deleted))
+	return nil
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffCmdLogFile string
+)
+
+// diffCmd defines the diff subcommand
+var diffCmd = &cobra.Command{
+	Use:   "diff OLD [NEW]",
+	Short: "Compare two log files, listing added, removed and modified sessions",
+	Long: `Compare two log files, e.g. the copies of two machines before a merge,
+or a log and the .bak file a rewrite left, listing the sessions only in
+NEW (+), only in OLD (-) and those modified (~) with the fields that
+changed. NEW defaults to the log.
+
+Sessions are matched by id, or by start time and titles when logged
+before ids existed. The exit status is 4 when the logs differ.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		newFile := diffCmdLogFile
+		if len(args) == 2 {
+			newFile = args[1]
+		}
+		oldRecords, err := readRecordsStrict(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error reading %s: %v\n"), args[0], err)
+			os.Exit(exitError)
+		}
+		newRecords, err := readRecordsStrict(newFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error reading %s: %v\n"), newFile, err)
+			os.Exit(exitError)
+		}
+
+		d := diffRecords(oldRecords, newRecords)
+		if jsonOutput() {
+			if err := printJSON(d.json()); err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+				os.Exit(exitError)
+			}
+		} else {
+			d.print()
+		}
+		if !d.empty() {
+			os.Exit(exitDifferent)
+		}
+	},
+}
+
+func init() {
+	diffCmd.Flags().StringVarP(&diffCmdLogFile, "file", "f", defaultLogFile(), "Log file compared when NEW is not given")
+	rootCmd.AddCommand(diffCmd)
+}
+
+// recordChange is a session found in both logs with different content
+type recordChange struct {
+	Old Record
+	New Record
+}
+
+// logDiff holds the differences between two logs
+type logDiff struct {
+	Added    []Record // Only in the new log
+	Removed  []Record // Only in the old log
+	Modified []recordChange
+}
+
+// empty reports whether both logs hold the same sessions
+func (d logDiff) empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// diffKey identifies a session across logs: its id, or its start time and
+// titles for records logged before ids existed, so a changed end shows as
+// a modification
+func diffKey(record Record) string {
+	if record.ID != "" {
+		return record.ID
+	}
+	return record.Start.Format(time.RFC3339) + "|" + strings.Join(record.Titles, "/")
+}
+
+// diffRecords compares the records of two logs, in the order of the new
+// log, with the removed ones in the order of the old
+func diffRecords(oldRecords, newRecords []Record) logDiff {
+	old := make(map[string]Record, len(oldRecords))
+	for _, record := range oldRecords {
+		old[diffKey(record)] = record
+	}
+
+	var d logDiff
+	seen := make(map[string]bool, len(newRecords))
+	for _, record := range newRecords {
+		key := diffKey(record)
+		seen[key] = true
+		previous, ok := old[key]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, record)
+		case !sameContent(previous, record) || previous.Duration() != record.Duration():
+			d.Modified = append(d.Modified, recordChange{Old: previous, New: record})
+		}
+	}
+	for _, record := range oldRecords {
+		if !seen[diffKey(record)] {
+			d.Removed = append(d.Removed, record)
+		}
+	}
+	return d
+}
+
+// changedFields describes the fields of a session that differ between its
+// two versions, one per line
+func (c recordChange) changedFields() []string {
+	var fields []string
+	add := func(name, old, new string) {
+		if old != new {
+			fields = append(fields, fmt.Sprintf("%s: %s -> %s", name, old, new))
+		}
+	}
+	add("start", c.Old.Start.Format("2006-01-02 15:04:05"), c.New.Start.Format("2006-01-02 15:04:05"))
+	add("end", c.Old.End.Format("2006-01-02 15:04:05"), c.New.End.Format("2006-01-02 15:04:05"))
+	add("duration", c.Old.Duration().Round(time.Second).String(), c.New.Duration().Round(time.Second).String())
+	add("titles", strings.Join(sanitizedTitles(c.Old.Titles), " / "), strings.Join(sanitizedTitles(c.New.Titles), " / "))
+	if !reflect.DeepEqual(c.Old.Tags, c.New.Tags) {
+		add("tags", fmt.Sprintf("%q", c.Old.Tags), fmt.Sprintf("%q", c.New.Tags))
+	}
+	add("notes", fmt.Sprintf("%q", c.Old.Notes), fmt.Sprintf("%q", c.New.Notes))
+	return fields
+}
+
+// print prints the differences in a human readable way
+func (d logDiff) print() {
+	if d.empty() {
+		fmt.Println(tr("The logs hold the same sessions"))
+		return
+	}
+	for _, record := range d.Removed {
+		fmt.Printf("- %s\n", formatRecord(record))
+	}
+	for _, record := range d.Added {
+		fmt.Printf("+ %s\n", formatRecord(record))
+	}
+	for _, c := range d.Modified {
+		fmt.Printf("~ %s\n", formatRecord(c.New))
+		for _, field := range c.changedFields() {
+			fmt.Printf("    %s\n", field)
+		}
+	}
+	fmt.Printf(tr("%d added, %d removed, %d modified\n"), len(d.Added), len(d.Removed), len(d.Modified))
+}
+
+// diffJSON is the JSON output of the diff subcommand
+type diffJSON struct {
+	Added    []talogo.JSONRecord `json:"added"`
+	Removed  []talogo.JSONRecord `json:"removed"`
+	Modified []changeJSON        `json:"modified"`
+}
+
+// changeJSON is a modified session in the JSON output of diff
+type changeJSON struct {
+	Old talogo.JSONRecord `json:"old"`
+	New talogo.JSONRecord `json:"new"`
+}
+
+// json returns the differences in the form printed as JSON
+func (d logDiff) json() diffJSON {
+	precision := logPrecision()
+	out := diffJSON{
+		Added:    []talogo.JSONRecord{},
+		Removed:  []talogo.JSONRecord{},
+		Modified: []changeJSON{},
+	}
+	for _, record := range d.Added {
+		out.Added = append(out.Added, talogo.NewJSONRecord(record, precision))
+	}
+	for _, record := range d.Removed {
+		out.Removed = append(out.Removed, talogo.NewJSONRecord(record, precision))
+	}
+	for _, c := range d.Modified {
+		out.Modified = append(out.Modified, changeJSON{
+			Old: talogo.NewJSONRecord(c.Old, precision),
+			New: talogo.NewJSONRecord(c.New, precision),
+		})
+	}
+	return out
+}
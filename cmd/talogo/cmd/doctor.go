@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	doctorCmdLogFile    string
+	doctorCmdPruneShort bool
+)
+
+// doctorCheck is a consistency check run by the doctor subcommand. It
+// returns a description of each problem found.
+type doctorCheck struct {
+	Name string
+	Run  func(records []Record) []string
+}
+
+// doctorChecks lists the checks run by the doctor subcommand
+var doctorChecks = []doctorCheck{
+	{Name: "overlapping records", Run: checkOverlaps},
+	{Name: "similar task names", Run: checkSimilarTasks},
+	{Name: "short records", Run: checkShortRecords},
+}
+
+// doctorReport is the output of the doctor subcommand with --output json
+type doctorReport struct {
+	OK     bool           `json:"ok"`
+	Pruned *int           `json:"pruned,omitempty"`
+	Checks []doctorResult `json:"checks"`
+}
+
+// doctorResult is the outcome of a doctor check
+type doctorResult struct {
+	Name     string   `json:"name"`
+	OK       bool     `json:"ok"`
+	Problems []string `json:"problems"`
+}
+
+// doctorCmd defines the doctor subcommand
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the log file for common problems",
+	Long: `Check the log file for common problems, exiting with code 3 if some
+check finds any. With --quiet only the problems are printed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		report := doctorReport{OK: true}
+		if doctorCmdPruneShort {
+			count, err := pruneShortRecords(doctorCmdLogFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error pruning short records: %v\n"), err)
+				os.Exit(1)
+			}
+			report.Pruned = &count
+			if !jsonOutput() {
+				printInfo("Removed %d records shorter than min_session\n", count)
+			}
+		}
+
+		records, err := readRecords(doctorCmdLogFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error reading log: %v\n"), err)
+			os.Exit(1)
+		}
+
+		for _, check := range doctorChecks {
+			problems := check.Run(records)
+			if problems == nil {
+				problems = []string{}
+			}
+			report.Checks = append(report.Checks, doctorResult{Name: check.Name, OK: len(problems) == 0, Problems: problems})
+			report.OK = report.OK && len(problems) == 0
+		}
+
+		if jsonOutput() {
+			if err := printJSON(report); err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+				os.Exit(1)
+			}
+		} else {
+			for _, result := range report.Checks {
+				if quiet {
+					for _, problem := range result.Problems {
+						fmt.Printf("%s: %s\n", result.Name, problem)
+					}
+					continue
+				}
+				if result.OK {
+					fmt.Printf(tr("[ok]   %s\n"), result.Name)
+					continue
+				}
+				fmt.Printf(tr("[fail] %s\n"), result.Name)
+				for _, problem := range result.Problems {
+					fmt.Printf("         %s\n", problem)
+				}
+			}
+		}
+
+		if !report.OK {
+			os.Exit(exitCheckFailed)
+		}
+	},
+}
+
+func init() {
+	doctorCmd.Flags().StringVarP(&doctorCmdLogFile, "file", "f", defaultLogFile(), "Log file to check")
+	doctorCmd.Flags().BoolVar(&doctorCmdPruneShort, "prune-short", false, "Remove the records shorter than min_session in the config before checking")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// checkOverlaps reports records whose time ranges overlap
+func checkOverlaps(records []Record) []string {
+	var problems []string
+	for _, o := range findOverlaps(records) {
+		problems = append(problems, fmt.Sprintf("lines %d and %d overlap by %s",
+			o.First.Line, o.Second.Line, o.Duration().Round(time.Second)))
+	}
+	return problems
+}
+
+// checkShortRecords reports records shorter than min_session in the
+// config, such as accidental starts logged before it was set
+func checkShortRecords(records []Record) []string {
+	config, err := loadConfig()
+	if err != nil {
+		return []string{err.Error()}
+	}
+	shortest, err := config.minSession()
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	var problems []string
+	for _, i := range shortRecords(records, shortest) {
+		record := records[i]
+		problems = append(problems, fmt.Sprintf("line %d: %s lasted %s, shorter than min_session (%s)",
+			record.Line, strings.Join(record.Titles, "/"), record.Duration().Round(time.Second), shortest))
+	}
+	return problems
+}
+
+// shortRecords returns the indexes of the records shorter than shortest.
+// Parts of a session split by day are left out, since the session they
+// belong to may be long: records right after or before another record
+// of the same task.
+func shortRecords(records []Record, shortest time.Duration) []int {
+	if shortest == 0 {
+		return nil
+	}
+	// Times are compared to the second, the precision logs are written
+	// with by default
+	type boundary struct {
+		task string
+		at   int64
+	}
+	starts := make(map[boundary]bool)
+	ends := make(map[boundary]bool)
+	for _, record := range records {
+		task := strings.Join(record.Titles, "/")
+		starts[boundary{task, record.Start.Unix()}] = true
+		ends[boundary{task, record.End.Unix()}] = true
+	}
+
+	var short []int
+	for i, record := range records {
+		if record.Duration() >= shortest {
+			continue
+		}
+		task := strings.Join(record.Titles, "/")
+		start, end := record.Start.Unix(), record.End.Unix()
+		if ends[boundary{task, start}] || ends[boundary{task, start - 1}] ||
+			starts[boundary{task, end}] || starts[boundary{task, end + 1}] {
+			continue
+		}
+		short = append(short, i)
+	}
+	return short
+}
+
+// pruneShortRecords removes the records shorter than min_session in the
+// config from the log, and returns the number of records removed
+func pruneShortRecords(logFile string) (int, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return 0, err
+	}
+	shortest, err := config.minSession()
+	if err != nil {
+		return 0, err
+	}
+	if shortest == 0 {
+		return 0, fmt.Errorf("min_session is not set in the config")
+	}
+
+	records, err := readRecordsStrict(logFile)
+	if err != nil {
+		return 0, err
+	}
+	short := shortRecords(records, shortest)
+	if len(short) == 0 {
+		return 0, nil
+	}
+	pruned := make(map[int]bool)
+	for _, i := range short {
+		pruned[i] = true
+	}
+	var kept []Record
+	for i, record := range records {
+		if !pruned[i] {
+			kept = append(kept, record)
+		}
+	}
+	return len(short), rewriteRecords(logFile, kept)
+}
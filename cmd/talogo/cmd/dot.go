@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+)
+
+// writeRecordsDot writes the task hierarchy of records as a Graphviz DOT
+// graph, e.g. for 'dot -Tsvg'. Nodes show the time of each task with its
+// subtasks, their font and the edges leading to them growing with their
+// share of the total.
+func writeRecordsDot(w io.Writer, records []Record) error {
+	dayStart, err := configuredDayStart()
+	if err != nil {
+		return err
+	}
+	days := make(map[string]*TaskNode)
+	for _, record := range records {
+		talogo.AddToSummary(days, record, talogo.SummaryOptions{DayStart: dayStart})
+	}
+	total := talogo.NewTaskNode("All tasks")
+	for _, day := range days {
+		total.Merge(day)
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph talogo {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=\"#dbe9f6\", fontname=\"Helvetica\"];\n")
+	b.WriteString("  edge [color=\"#7a9cc6\", arrowhead=none];\n")
+	fmt.Fprintf(&b, "  %s [label=%s, fontsize=%.1f];\n", dotQuote(""), dotQuote(fmt.Sprintf("%s\n%.2f hs", total.Name, total.TotalTime.Hours())), dotFontSize(1))
+	writeDotTasks(&b, "", total.Children, total.TotalTime.Hours())
+	b.WriteString("}\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write DOT: %v", err)
+	}
+	return nil
+}
+
+// writeDotTasks writes the nodes of tasks, children of the node with id
+// parent, and the edges to them
+func writeDotTasks(b *strings.Builder, parent string, tasks map[string]*TaskNode, total float64) {
+	for _, name := range talogo.SortedTaskNames(tasks) {
+		task := tasks[name]
+		id := parent + "/" + name
+		share := 0.0
+		if total > 0 {
+			share = task.TotalTime.Hours() / total
+		}
+		label := fmt.Sprintf("%s\n%.2f hs (%.0f%%)", name, task.TotalTime.Hours(), share*100)
+		fmt.Fprintf(b, "  %s [label=%s, fontsize=%.1f];\n", dotQuote(id), dotQuote(label), dotFontSize(share))
+		fmt.Fprintf(b, "  %s -> %s [penwidth=%.1f];\n", dotQuote(parent), dotQuote(id), 1+7*share)
+		writeDotTasks(b, id, task.Children, total)
+	}
+}
+
+// dotFontSize returns the font size of a node with the given share of the
+// total, so the area of its label grows with its time
+func dotFontSize(share float64) float64 {
+	return 10 + 26*math.Sqrt(share)
+}
+
+// dotQuote returns s as a quoted DOT string, with line breaks as \n
+func dotQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
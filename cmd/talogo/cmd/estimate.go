@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	estimateCmdLogFile string
+	estimateCmdFrom    string
+	estimateCmdTo      string
+)
+
+// estimateCmd defines the estimate subcommand
+var estimateCmd = &cobra.Command{
+	Use:   "estimate",
+	Short: "Manage the estimated time of tasks and compare it with the logged time",
+	Long: `Manage the estimated time of tasks, kept in the [estimates] table of the
+config, and compare it with the time logged to them:
+
+  talogo estimate set work/project-x 40h
+  talogo estimate report --from 2026-09-01
+
+The logged time of a task includes its subtasks.`,
+}
+
+var estimateSetCmd = &cobra.Command{
+	Use:   "set TASK DURATION",
+	Short: "Set the estimated time of a task (e.g. work/project-x 40h)",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		task, err := estimateTask(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error setting estimate: %v\n"), err)
+			os.Exit(1)
+		}
+		// Estimates are kept in minutes, as formatted in the config
+		d, err := time.ParseDuration(args[1])
+		d = d.Round(time.Minute)
+		if err != nil || d <= 0 {
+			fmt.Fprintf(os.Stderr, tr("Error setting estimate: invalid duration %q\n"), args[1])
+			os.Exit(1)
+		}
+
+		if err := updateConfigTable([]string{"estimates", task}, formatShortDuration(d)); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error setting estimate: %v\n"), err)
+			os.Exit(1)
+		}
+		printInfo("Estimated %s at %s\n", task, formatShortDuration(d))
+	},
+}
+
+var estimateRemoveCmd = &cobra.Command{
+	Use:   "remove TASK",
+	Short: "Remove the estimate of a task",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		task, err := estimateTask(args[0])
+		if err == nil {
+			err = updateConfigTable([]string{"estimates", task}, nil)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error removing estimate: %v\n"), err)
+			os.Exit(1)
+		}
+	},
+}
+
+var estimateReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Compare the estimated and logged time of the estimated tasks",
+	Long: `Compare the estimated time of each task with the time logged to it and
+its subtasks, optionally between --from and --to. The variance is the
+logged time over or under the estimate, as a percentage of it.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := loadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		estimates, err := config.estimates()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+
+		records, err := readRecords(estimateCmdLogFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error reading log: %v\n"), err)
+			os.Exit(1)
+		}
+		records, err = recordsBetween(records, estimateCmdFrom, estimateCmdTo)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+
+		variances := estimateVariances(estimates, records)
+		if jsonOutput() {
+			if err := printJSON(variances); err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+				os.Exit(1)
+			}
+			return
+		}
+		if len(variances) == 0 {
+			fmt.Println(tr("No estimates, set them with 'talogo estimate set'"))
+			return
+		}
+		rows := make([][]string, len(variances))
+		for i, v := range variances {
+			rows[i] = []string{
+				v.Task,
+				fmt.Sprintf("%.2f hs", v.EstimatedHours),
+				fmt.Sprintf("%.2f hs", v.LoggedHours),
+				fmt.Sprintf("%+.0f%%", v.VariancePercent),
+			}
+		}
+		printTable([]string{tr("Task"), tr("Estimated"), tr("Logged"), tr("Variance")}, rows, 1, 2, 3)
+	},
+}
+
+func init() {
+	estimateReportCmd.Flags().StringVarP(&estimateCmdLogFile, "file", "f", defaultLogFile(), "Log file to read")
+	estimateReportCmd.Flags().StringVar(&estimateCmdFrom, "from", "", "Only include days from this date (YYYY-MM-DD)")
+	estimateReportCmd.Flags().StringVar(&estimateCmdTo, "to", "", "Only include days up to this date (YYYY-MM-DD)")
+	estimateCmd.AddCommand(estimateSetCmd)
+	estimateCmd.AddCommand(estimateRemoveCmd)
+	estimateCmd.AddCommand(estimateReportCmd)
+	rootCmd.AddCommand(estimateCmd)
+}
+
+// estimateTask returns the task path arg names, with aliases expanded
+func estimateTask(arg string) (string, error) {
+	titles, err := expandTitles(strings.Split(strings.Trim(arg, "/"), "/"))
+	if err != nil {
+		return "", err
+	}
+	titles, err = checkTitles(titles)
+	if err == nil && len(titles) == 0 {
+		err = fmt.Errorf("no task given")
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(titles, "/"), nil
+}
+
+// estimateVariance compares the estimated and logged time of a task
+type estimateVariance struct {
+	Task            string  `json:"task"`
+	EstimatedHours  float64 `json:"estimated_hours"`
+	LoggedHours     float64 `json:"logged_hours"`
+	VariancePercent float64 `json:"variance_percent"` // Positive when over the estimate
+}
+
+// estimateVariances returns the variance of each estimated task given the
+// records logged, sorted by task
+func estimateVariances(estimates map[string]time.Duration, records []Record) []estimateVariance {
+	variances := []estimateVariance{}
+	for task, estimate := range estimates {
+		var logged time.Duration
+		for _, record := range records {
+			if record.MatchesTask([]string{task}) {
+				logged += record.Duration()
+			}
+		}
+		variances = append(variances, estimateVariance{
+			Task:            task,
+			EstimatedHours:  estimate.Hours(),
+			LoggedHours:     logged.Hours(),
+			VariancePercent: float64(logged-estimate) / float64(estimate) * 100,
+		})
+	}
+	sort.Slice(variances, func(i, j int) bool {
+		return variances[i].Task < variances[j].Task
+	})
+	return variances
+}
@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+	"github.com/spf13/cobra"
+)
+
+// exportFormats are the formats export writes
+var exportFormats = []string{"csv", "json", "jsonl", "org", "timeclock", "dot", "xlsx"}
+
+var (
+	exportCmdLogFile   string
+	exportCmdFormat    string
+	exportCmdOut       string
+	exportCmdSinceLast bool
+	exportCmdDelimiter string
+	exportCmdFrom      string
+	exportCmdTo        string
+	exportCmdAnonymize bool
+	exportCmdUnbilled  bool
+)
+
+// exportCmd defines the export subcommand
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export logged sessions to CSV, JSON, org-mode, timeclock, DOT or Excel",
+	Long: `Export logged sessions to CSV, JSON, org-mode, timeclock, DOT or Excel.
+
+The dot format is a Graphviz graph of the task hierarchy, with the time of
+each task and labels sized by their share, e.g. for a map of a quarter:
+
+  talogo export --format dot --from 2026-01-01 --to 2026-03-31 | dot -Tsvg > q1.svg
+
+The xlsx format is an Excel workbook with a Sessions sheet, one session
+per row, and a By day sheet with the time of each task per day, both with
+totals. Times are spreadsheet durations, formatted as hours and minutes:
+
+  talogo export --format xlsx --from 2026-09-01 --to 2026-09-30 -o september.xlsx
+
+With --anonymize, titles are replaced by pseudonyms keeping the task
+hierarchy and the durations, to share the data without client names:
+top level tasks become Project A, Project B..., their subtasks Task 1,
+Task 2... and tags tag1, tag2... Names are numbered in order of first
+appearance in the log, so they keep their pseudonyms in the exports of a
+growing log. Notes, hosts and users are left out.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		comma, err := talogo.ParseCSVDelimiter(exportCmdDelimiter)
+		if err == nil && exportCmdSinceLast && (exportCmdFrom != "" || exportCmdTo != "") {
+			err = fmt.Errorf("--since-last cannot be combined with --from or --to")
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error exporting records: %v\n"), err)
+			os.Exit(1)
+		}
+		if err := exportRecords(exportCmdLogFile, exportCmdFormat, exportCmdOut, exportCmdFrom, exportCmdTo, exportCmdSinceLast, exportCmdAnonymize, exportCmdUnbilled, comma); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error exporting records: %v\n"), err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportCmdLogFile, "file", "f", defaultLogFile(), "Log file to read")
+	exportCmd.Flags().StringVar(&exportCmdFormat, "format", "csv", "Output format ("+strings.Join(exportFormats, ", ")+")")
+	exportCmd.Flags().StringVar(&exportCmdFrom, "from", "", "Only export days from this date (YYYY-MM-DD)")
+	exportCmd.Flags().StringVar(&exportCmdTo, "to", "", "Only export days up to this date (YYYY-MM-DD)")
+	exportCmd.Flags().StringVarP(&exportCmdOut, "out", "o", "-", "Destination file ('-' for stdout)")
+	exportCmd.Flags().StringVar(&exportCmdDelimiter, "delimiter", ",", "Field delimiter of CSV output, a single character or \"tab\" (e.g. ';' for spreadsheets in some locales)")
+	exportCmd.Flags().BoolVar(&exportCmdSinceLast, "since-last", false, "Only export entries added since the previous export to the same destination")
+	exportCmd.Flags().BoolVar(&exportCmdAnonymize, "anonymize", false, "Replace titles and tags with pseudonyms and leave out notes, hosts and users")
+	exportCmd.Flags().BoolVar(&exportCmdUnbilled, "unbilled", false, "Leave out the sessions marked as billed")
+	rootCmd.AddCommand(exportCmd)
+}
+
+// exportRecords writes the records of logFile to out in the given format.
+// When sinceLast is set, only the records added to the log after those
+// written by the previous export to the same destination are written, and
+// with from or to only those of the days between them. With unbilled,
+// records marked as billed are left out, and with anonymize titles and
+// tags are replaced by pseudonyms. CSV output is delimited by comma. A
+// file is replaced once the whole export is written, so a failed export
+// leaves it as it was.
+func exportRecords(logFile, format, out, from, to string, sinceLast, anonymize, unbilled bool, comma rune) error {
+	if !slices.Contains(exportFormats, format) {
+		return fmt.Errorf("unknown export format %q (expected %s)", format, strings.Join(exportFormats, ", "))
+	}
+	records, err := readRecords(logFile)
+	if err != nil {
+		return err
+	}
+
+	destination := out
+	if out != "-" {
+		if abs, err := filepath.Abs(out); err == nil {
+			destination = abs
+		}
+	}
+	marks, err := loadExportMarks(logFile, records)
+	if err != nil {
+		return err
+	}
+
+	pending := records
+	if sinceLast {
+		mark := marks[destination]
+		pending = nil
+		for _, record := range records {
+			if mark.before(record) {
+				pending = append(pending, record)
+			}
+		}
+	}
+	if pending, err = recordsBetween(pending, from, to); err != nil {
+		return err
+	}
+	if unbilled {
+		pending = unbilledRecords(pending)
+	}
+	written := pending
+	if anonymize {
+		// Named from the whole log, for the same pseudonyms whatever
+		// is exported
+		anonymizer := newAnonymizer(records)
+		anonymous := make([]Record, len(pending))
+		for i, record := range pending {
+			anonymous[i] = anonymizer.record(record)
+		}
+		pending = anonymous
+	}
+
+	var buf bytes.Buffer
+	var w io.Writer = os.Stdout
+	if out != "-" {
+		w = &buf
+	}
+	switch format {
+	case "csv":
+		err = talogo.WriteCSV(w, pending, comma, logPrecision())
+	case "json":
+		err = writeRecordsJSON(w, pending)
+	case "jsonl":
+		err = talogo.WriteJSONL(w, pending, logPrecision())
+	case "org":
+		err = writeRecordsOrg(w, pending)
+	case "timeclock":
+		err = writeRecordsTimeclock(w, pending)
+	case "dot":
+		err = writeRecordsDot(w, pending)
+	case "xlsx":
+		err = writeRecordsXLSX(w, pending)
+	}
+	if err != nil {
+		return err
+	}
+	if out != "-" {
+		if err := replaceFile(out, buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to write output file: %v", err)
+		}
+	}
+
+	// Every export to the destination moves its mark, so --since-last
+	// follows whatever was last written there
+	if len(written) == 0 {
+		return nil
+	}
+	marks[destination] = marks[destination].after(written)
+	return saveExportMarks(logFile, marks)
+}
+
+// writeRecordsJSON writes records as a JSON array
+func writeRecordsJSON(w io.Writer, records []Record) error {
+	exported := make([]talogo.JSONRecord, 0, len(records))
+	for _, record := range records {
+		exported = append(exported, talogo.NewJSONRecord(record, logPrecision()))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(exported); err != nil {
+		return fmt.Errorf("failed to write JSON: %v", err)
+	}
+	return nil
+}
+
+// exportMark is the high-water mark of the records exported to a
+// destination: the greatest id and start time of those written. Ids are
+// generated in time order, so records added later have greater ones;
+// start times stand in for them in logs from before ids.
+type exportMark struct {
+	LastID    string    `json:"last_id,omitempty"`
+	LastStart time.Time `json:"last_start,omitzero"`
+}
+
+// before reports whether record was added to the log after the records
+// the mark covers
+func (m exportMark) before(record Record) bool {
+	if record.ID != "" {
+		return record.ID > m.LastID
+	}
+	return record.Start.After(m.LastStart)
+}
+
+// after returns the mark moved past records
+func (m exportMark) after(records []Record) exportMark {
+	for _, record := range records {
+		if record.ID > m.LastID {
+			m.LastID = record.ID
+		}
+		if record.Start.After(m.LastStart) {
+			m.LastStart = record.Start
+		}
+	}
+	return m
+}
+
+// exportMarksFile returns the path of the file holding the export marks
+// of logFile
+func exportMarksFile(logFile string) string {
+	return logPath(logFile) + ".exports.json"
+}
+
+// loadExportMarks returns the mark of the records of logFile exported to
+// each destination. Marks of older versions, holding the number of
+// records exported or their keys, are converted with records.
+func loadExportMarks(logFile string, records []Record) (map[string]exportMark, error) {
+	marks := make(map[string]exportMark)
+	data, err := os.ReadFile(exportMarksFile(logFile))
+	if os.IsNotExist(err) {
+		return marks, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export marks: %v", err)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse export marks: %v", err)
+	}
+	for destination, value := range raw {
+		var mark exportMark
+		var count int
+		var keys []string
+		switch {
+		case json.Unmarshal(value, &count) == nil:
+			mark = mark.after(records[:max(0, min(count, len(records)))])
+		case json.Unmarshal(value, &keys) == nil:
+			exported := make(map[string]bool, len(keys))
+			for _, key := range keys {
+				exported[key] = true
+			}
+			for _, record := range records {
+				if exported[recordKey(record)] {
+					mark = mark.after([]Record{record})
+				}
+			}
+		default:
+			if err := json.Unmarshal(value, &mark); err != nil {
+				return nil, fmt.Errorf("failed to parse export marks: %v", err)
+			}
+		}
+		marks[destination] = mark
+	}
+	return marks, nil
+}
+
+// saveExportMarks persists the export marks of logFile
+func saveExportMarks(logFile string, marks map[string]exportMark) error {
+	data, err := json.MarshalIndent(marks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode export marks: %v", err)
+	}
+	if err := os.WriteFile(exportMarksFile(logFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write export marks: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	extendCmdLogFile string
+	extendCmdBy      time.Duration
+	extendCmdUntil   string
+)
+
+// extendCmd defines the extend subcommand
+var extendCmd = &cobra.Command{
+	Use:   "extend (--by DURATION | --until TIME)",
+	Short: "Push the end of the last session forward, e.g. 'extend --by 15m'",
+	Long: `Push the end of the most recent session of the log forward, for when the
+timer was stopped but the work went on for a bit. --by adds a duration and
+--until sets the new end, e.g. 18:00 or "yesterday 19:30". The new end
+can't be in the future. A session extended into the next day is split in
+one session per day, like those tracked across midnight.
+
+The log is rewritten keeping the previous version in a .bak file.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if (extendCmdBy == 0) == (extendCmdUntil == "") {
+			fmt.Fprintln(os.Stderr, tr("Error: either --by or --until is required"))
+			os.Exit(1)
+		}
+		records, err := extendLastRecord(extendCmdLogFile, extendCmdBy, extendCmdUntil, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error extending session: %v\n"), err)
+			os.Exit(1)
+		}
+		if !quiet {
+			for _, record := range records {
+				printRecord(record)
+			}
+		}
+	},
+}
+
+func init() {
+	extendCmd.Flags().StringVarP(&extendCmdLogFile, "file", "f", defaultLogFile(), "Log file to rewrite")
+	extendCmd.Flags().DurationVar(&extendCmdBy, "by", 0, "Time to add to the end of the session, e.g. 15m")
+	extendCmd.Flags().StringVar(&extendCmdUntil, "until", "", "New end of the session, e.g. 18:00 or \"yesterday 19:30\"")
+	rootCmd.AddCommand(extendCmd)
+}
+
+// extendLastRecord moves the end of the record of the log file ending
+// last to by after it, or to the time until, and returns the records
+// written for it: the record, and one more per day it now reaches into.
+// Stored durations grow by the same amount.
+func extendLastRecord(logFile string, by time.Duration, until string, now time.Time) ([]Record, error) {
+	records, err := readRecordsStrict(logFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("the log has no sessions")
+	}
+	dayStart, err := configuredDayStart()
+	if err != nil {
+		return nil, err
+	}
+	last := 0
+	for i, record := range records {
+		if record.End.After(records[last].End) {
+			last = i
+		}
+	}
+	record := records[last]
+
+	end := record.End.Add(by)
+	if until != "" {
+		if end, err = parseTime(until, now); err != nil {
+			return nil, err
+		}
+	}
+	if !end.After(record.End) {
+		return nil, fmt.Errorf("%s is not after the end of the last session, %s", end.Format(time.RFC3339), record.End.Format(time.RFC3339))
+	}
+	if end.After(now) {
+		return nil, fmt.Errorf("%s is in the future", end.Format(time.RFC3339))
+	}
+
+	if record.Elapsed > 0 {
+		record.Elapsed += end.Sub(record.End)
+	}
+	record.End = end
+	// The record keeps its id, the parts of the following days get new
+	// ones
+	parts := talogo.SplitByDay(record, dayStart)
+	for i := 1; i < len(parts); i++ {
+		parts[i].ID = talogo.NewID()
+	}
+	records = append(records[:last], append(parts, records[last+1:]...)...)
+	return parts, rewriteRecords(logFile, records)
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+)
+
+// checkGap asks, when start is at least the gaps threshold of the config
+// after the end of the last session of its day, whether to log the time
+// in between to a task, e.g. lunch, or to leave it untracked
+func checkGap(logFile string, start time.Time, strict bool) error {
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	threshold, err := config.gapThreshold()
+	if err != nil || threshold == 0 {
+		return err
+	}
+	var tasks [][]string
+	for _, task := range config.Gaps.Tasks {
+		titles, err := expandTitles(strings.Split(strings.Trim(task, "/"), "/"))
+		if err == nil {
+			titles, err = checkTitles(titles)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid gaps task %q in config: %v", task, err)
+		}
+		tasks = append(tasks, titles)
+	}
+
+	end, err := lastSessionEnd(logFile, start)
+	if err != nil || end.IsZero() || start.Sub(end) < threshold {
+		return err
+	}
+
+	fmt.Printf(tr("The last session ended at %s, %s before this one.\n"), end.Local().Format(clockLayout), formatShortDuration(start.Sub(end)))
+	var choices []string
+	for i, titles := range tasks {
+		choices = append(choices, fmt.Sprintf("[%d] %s", i+1, strings.Join(titles, "/")))
+	}
+	prompt := tr("Log the gap to a task (titles separated by /), or press Enter to leave it untracked: ")
+	if len(choices) > 0 {
+		prompt = fmt.Sprintf(tr("Log the gap to %s or another task (titles separated by /), or press Enter to leave it untracked: "), strings.Join(choices, ", "))
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	var titles []string
+	for titles == nil {
+		fmt.Print(prompt)
+		answer, _ := reader.ReadString('\n')
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			return nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(tasks) {
+			titles = tasks[n-1]
+			break
+		}
+		titles, err = expandTitles(strings.Split(strings.Trim(answer, "/"), "/"))
+		if err == nil {
+			titles, err = checkTitles(titles)
+		}
+		if err != nil {
+			fmt.Printf(tr("Error: %v\n"), err)
+			titles = nil
+		}
+	}
+
+	dayStart, err := configuredDayStart()
+	if err != nil {
+		return err
+	}
+	record := withOrigin(Record{
+		Start:   end,
+		End:     start,
+		Titles:  titles,
+		Source:  talogo.SourceAdd,
+		Elapsed: start.Sub(end),
+	})
+	gap := model{logFile: logFile, strict: strict}
+	if _, err := gap.save(talogo.SplitByDay(record, dayStart)); err != nil {
+		return err
+	}
+	printInfo("Logged the gap as %s\n", strings.Join(titles, " / "))
+	return nil
+}
+
+// lastSessionEnd returns the end of the last session of the log file
+// started before t on the same day, the zero time if none. Parallel
+// records are left out, and a log not created yet has none.
+func lastSessionEnd(logFile string, t time.Time) (time.Time, error) {
+	if _, err := os.Stat(logPath(logFile)); os.IsNotExist(err) {
+		return time.Time{}, nil
+	}
+	dayStart, err := configuredDayStart()
+	if err != nil {
+		return time.Time{}, err
+	}
+	day := talogo.DayOf(t, dayStart)
+	records, err := queryDays(logFile, day, day, dayStart)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var end time.Time
+	for _, record := range records {
+		if record.Start.Before(t) && record.End.After(end) && !isParallel(record) {
+			end = record.End
+		}
+	}
+	return end, nil
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	grepCmdLogFile    string
+	grepCmdFrom       string
+	grepCmdTo         string
+	grepCmdIgnoreCase bool
+	grepCmdTitles     bool
+	grepCmdNotes      bool
+)
+
+// grepCmd defines the grep subcommand
+var grepCmd = &cobra.Command{
+	Use:   "grep PATTERN",
+	Short: "Search the titles and notes of the logged sessions, e.g. 'grep -i deploy'",
+	Long: `Search the titles and notes of the logged sessions with a regular
+expression (Go syntax, e.g. "review|PR-[0-9]+"). Each matching session is
+listed with its id and duration, followed by the lines of its notes that
+match, and the time of all of them is added up at the end.
+
+--titles or --notes restrict the search to one of them, and --from and
+--to to the sessions of those days.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pattern := args[0]
+		if grepCmdIgnoreCase {
+			pattern = "(?i)" + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: invalid pattern %q: %v\n"), args[0], err)
+			os.Exit(1)
+		}
+		if grepCmdTitles && grepCmdNotes {
+			fmt.Fprintln(os.Stderr, tr("Error: --titles and --notes cannot be combined"))
+			os.Exit(1)
+		}
+
+		records, err := readRecords(grepCmdLogFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error reading log: %v\n"), err)
+			os.Exit(1)
+		}
+		if records, err = recordsBetween(records, grepCmdFrom, grepCmdTo); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+
+		var matches []grepMatch
+		for _, record := range records {
+			if match, ok := grepRecord(re, record, !grepCmdNotes, !grepCmdTitles); ok {
+				matches = append(matches, match)
+			}
+		}
+
+		if jsonOutput() {
+			entries := make([]talogo.JSONRecord, len(matches))
+			for i, match := range matches {
+				entries[i] = talogo.NewJSONRecord(match.Record, logPrecision())
+			}
+			if err := printJSON(entries); err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+				os.Exit(1)
+			}
+			return
+		}
+		if len(matches) == 0 {
+			fmt.Println(tr("No sessions match"))
+			return
+		}
+		var total time.Duration
+		for _, match := range matches {
+			printRecord(match.Record)
+			for _, line := range match.Notes {
+				fmt.Printf("    %s\n", line)
+			}
+			total += match.Record.Duration()
+		}
+		fmt.Printf(tr("%d sessions, %s in total\n"), len(matches), formatShortDuration(total))
+	},
+}
+
+func init() {
+	grepCmd.Flags().StringVarP(&grepCmdLogFile, "file", "f", defaultLogFile(), "Log file to read")
+	grepCmd.Flags().StringVar(&grepCmdFrom, "from", "", "Only search days from this date (YYYY-MM-DD)")
+	grepCmd.Flags().StringVar(&grepCmdTo, "to", "", "Only search days up to this date (YYYY-MM-DD)")
+	grepCmd.Flags().BoolVarP(&grepCmdIgnoreCase, "ignore-case", "i", false, "Ignore case when matching")
+	grepCmd.Flags().BoolVar(&grepCmdTitles, "titles", false, "Only search the titles")
+	grepCmd.Flags().BoolVar(&grepCmdNotes, "notes", false, "Only search the notes")
+	rootCmd.AddCommand(grepCmd)
+}
+
+// grepMatch is a session matching the pattern of the grep subcommand
+type grepMatch struct {
+	Record Record
+	Notes  []string // Lines of the notes that match
+}
+
+// grepRecord matches re against the titles, joined by " / ", and each
+// line of the notes of record, as asked
+func grepRecord(re *regexp.Regexp, record Record, titles, notes bool) (grepMatch, bool) {
+	match := grepMatch{Record: record}
+	found := titles && re.MatchString(strings.Join(sanitizedTitles(record.Titles), " / "))
+	if notes && record.Notes != "" {
+		for _, line := range strings.Split(record.Notes, "\n") {
+			if re.MatchString(line) {
+				match.Notes = append(match.Notes, strings.TrimSpace(line))
+				found = true
+			}
+		}
+	}
+	return match, found
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncHarvestCmdLogFile string
+	syncHarvestCmdFrom    string
+	syncHarvestCmdTo      string
+	syncHarvestCmdDryRun  bool
+)
+
+// harvestAPI is the base URL of the Harvest API
+const harvestAPI = "https://api.harvestapp.com/v2"
+
+var syncHarvestCmd = &cobra.Command{
+	Use:   "harvest",
+	Short: "Create Harvest time entries for sessions of mapped tasks",
+	Long: `Create Harvest time entries for the sessions in the date range whose
+task is mapped to a project in the [harvest.projects] section of the
+config file, e.g.
+
+  [harvest]
+  account_id = "123456"
+  token = "..."
+
+  [harvest.projects."client-x"]
+  project_id = 111
+  task_id = 222
+
+Synced sessions are remembered in a .harvest.json file next to the log, so
+they are never posted twice.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := loadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		if config.Harvest.AccountID == "" || config.Harvest.Token == "" {
+			fmt.Fprint(os.Stderr, tr("Error: set account_id and token in the [harvest] section of the config file\n"))
+			os.Exit(1)
+		}
+
+		from, to, err := syncDateRange(syncHarvestCmdFrom, syncHarvestCmdTo)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+
+		if err := pushRecords(syncHarvestCmdLogFile, harvestService(config.Harvest), from, to, syncHarvestCmdDryRun); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error syncing with Harvest: %v\n"), err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	syncHarvestCmd.Flags().StringVarP(&syncHarvestCmdLogFile, "file", "f", defaultLogFile(), "Log file to read")
+	syncHarvestCmd.Flags().StringVar(&syncHarvestCmdFrom, "from", "", "First day to sync (YYYY-MM-DD, default today)")
+	syncHarvestCmd.Flags().StringVar(&syncHarvestCmdTo, "to", "", "Last day to sync (YYYY-MM-DD, default today)")
+	syncHarvestCmd.Flags().BoolVar(&syncHarvestCmdDryRun, "dry-run", false, "Print the time entries that would be created without posting them")
+	syncCmd.AddCommand(syncHarvestCmd)
+}
+
+// harvestService returns the Harvest time entry service of the account in
+// config
+func harvestService(harvest HarvestConfig) pushService {
+	headers := map[string]string{
+		"Authorization":      "Bearer " + harvest.Token,
+		"Harvest-Account-Id": harvest.AccountID,
+		"User-Agent":         "talogo",
+	}
+
+	return pushService{
+		name: "harvest",
+		target: func(record Record) string {
+			project, ok := longestPathMatch(harvest.Projects, record.Titles)
+			if !ok {
+				return ""
+			}
+			return fmt.Sprintf("harvest project %d task %d", project.ProjectID, project.TaskID)
+		},
+		post: func(record Record, target string) (string, error) {
+			project, _ := longestPathMatch(harvest.Projects, record.Titles)
+			notes := strings.Join(record.Titles, " / ")
+			if record.Notes != "" {
+				notes += "\n" + record.Notes
+			}
+			body := map[string]interface{}{
+				"project_id": project.ProjectID,
+				"task_id":    project.TaskID,
+				"spent_date": record.Start.Format("2006-01-02"),
+				// Harvest stores hours with two decimals
+				"hours": math.Round(record.Duration().Hours()*100) / 100,
+				"notes": notes,
+			}
+
+			var entry struct {
+				ID int64 `json:"id"`
+			}
+			if err := postJSON(harvestAPI+"/time_entries", body, headers, &entry); err != nil {
+				return "", err
+			}
+			return strconv.FormatInt(entry.ID, 10), nil
+		},
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// hookTimeout bounds each hook command, so a stuck hook doesn't hold up
+// the command
+const hookTimeout = 30 * time.Second
+
+// runHook runs the hook command of event for the session in record.
+// Failures are reported as warnings and never fail the command.
+func runHook(event string, record Record) {
+	if err := execHook(event, record); err != nil {
+		fmt.Fprintf(os.Stderr, tr("Warning: %v\n"), err)
+	}
+}
+
+// hookCmd runs the hook command of event from the log TUI, reporting
+// failures in a hookMsg instead of printing over the view
+func hookCmd(event string, record Record) tea.Cmd {
+	return func() tea.Msg {
+		return hookMsg{execHook(event, record)}
+	}
+}
+
+// hookMsg reports the result of a hook run from the log TUI
+type hookMsg struct {
+	err error
+}
+
+// execHook runs the hook command configured for event, if any, through
+// the shell, with the session in record described by environment
+// variables. Its output is only shown if it fails.
+func execHook(event string, record Record) error {
+	config, err := loadConfig()
+	if err != nil {
+		return nil
+	}
+	var command string
+	switch event {
+	case EventStart:
+		command = config.Hooks.OnStart
+	case EventStop:
+		command = config.Hooks.OnStop
+	case EventPause:
+		command = config.Hooks.OnPause
+	case EventExpire:
+		command = config.Hooks.OnExpire
+	}
+	if command == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Env = append(os.Environ(), hookEnv(event, record)...)
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if output := strings.TrimSpace(string(out)); output != "" {
+			err = fmt.Errorf("%v: %s", err, output)
+		}
+		return fmt.Errorf("on_%s hook failed: %v", event, err)
+	}
+	return nil
+}
+
+// hookEnv returns the environment variables describing the session in
+// record to hooks. The end time is only set on stop, the duration is the
+// time tracked so far.
+func hookEnv(event string, record Record) []string {
+	env := []string{
+		"TALOGO_EVENT=" + event,
+		"TALOGO_SESSION_TASK=" + strings.Join(record.Titles, "/"),
+		"TALOGO_SESSION_TAGS=" + strings.Join(record.Tags, ","),
+		"TALOGO_SESSION_NOTES=" + record.Notes,
+		"TALOGO_SESSION_START=" + formatTimestamp(record.Start),
+		"TALOGO_SESSION_DURATION=" + strconv.FormatFloat(durationSeconds(record.Duration()), 'f', -1, 64),
+	}
+	if event == EventStop {
+		env = append(env, "TALOGO_SESSION_END="+formatTimestamp(record.End))
+	}
+	return env
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// language is the language of user-facing messages, "en" unless another
+// catalog is selected with setLanguage
+var language = "en"
+
+// catalogs are the translations of user-facing messages, by language and
+// English message. Messages are looked up by their English text, so
+// English needs no catalog and messages missing from a catalog are shown
+// in English.
+var catalogs = map[string]map[string]string{
+	"es": catalogES,
+}
+
+// tr returns the translation of the English message msg to the language
+// of messages. Format strings are translated before formatting, e.g.
+// fmt.Printf(tr("Renamed %d records\n"), count).
+func tr(msg string) string {
+	if translated, ok := catalogs[language][msg]; ok {
+		return translated
+	}
+	return msg
+}
+
+// setLanguage selects the language of messages: configured, the language
+// setting of the config, if not empty, otherwise the one of the LC_ALL,
+// LC_MESSAGES or LANG environment variables. Languages without a catalog
+// fall back to English.
+func setLanguage(configured string) {
+	name := configured
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if name != "" {
+			break
+		}
+		name = os.Getenv(env)
+	}
+	// e.g. "es_AR.UTF-8"
+	name = strings.ToLower(name)
+	if i := strings.IndexAny(name, "_-.@"); i >= 0 {
+		name = name[:i]
+	}
+	language = "en"
+	if _, ok := catalogs[name]; ok {
+		language = name
+	}
+}
+
+// usageHeadings are the lines of the cobra help template translated with
+// the commands
+var usageHeadings = []string{
+	"Usage:",
+	"Aliases:",
+	"Examples:",
+	"Available Commands:",
+	"Flags:",
+	"Global Flags:",
+	"Additional help topics:",
+	`Use "{{.CommandPath}} [command] --help" for more information about a command.`,
+	"[command]",
+}
+
+// translateCommands translates the descriptions and flag usages of cmd
+// and its subcommands, and the headings of their help
+func translateCommands(cmd *cobra.Command) {
+	var replacements []string
+	for _, heading := range usageHeadings {
+		replacements = append(replacements, heading, tr(heading))
+	}
+	cmd.SetUsageTemplate(strings.NewReplacer(replacements...).Replace(cmd.UsageTemplate()))
+
+	var walk func(*cobra.Command)
+	walk = func(c *cobra.Command) {
+		c.Short = tr(c.Short)
+		c.Long = tr(c.Long)
+		translateFlag := func(f *pflag.Flag) {
+			f.Usage = tr(f.Usage)
+		}
+		c.LocalNonPersistentFlags().VisitAll(translateFlag)
+		c.PersistentFlags().VisitAll(translateFlag)
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(cmd)
+}
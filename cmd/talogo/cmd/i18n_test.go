@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// untranslatedOutput are the literals printed as they are on purpose,
+// read by status bars and scripts rather than people
+var untranslatedOutput = map[string]bool{
+	"idle": true,
+}
+
+// parseSources parses the non-test Go files of the package
+func parseSources(t *testing.T) (*token.FileSet, []*ast.File) {
+	t.Helper()
+	fset := token.NewFileSet()
+	packages, err := parser.ParseDir(fset, ".", func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files []*ast.File
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			files = append(files, file)
+		}
+	}
+	return fset, files
+}
+
+// stringLiteral returns the value of expr if it is a string literal or a
+// concatenation of them
+func stringLiteral(expr ast.Expr) (string, bool) {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		if expr.Kind != token.STRING {
+			return "", false
+		}
+		value, err := strconv.Unquote(expr.Value)
+		return value, err == nil
+	case *ast.BinaryExpr:
+		if expr.Op != token.ADD {
+			return "", false
+		}
+		x, ok := stringLiteral(expr.X)
+		if !ok {
+			return "", false
+		}
+		y, ok := stringLiteral(expr.Y)
+		return x + y, ok
+	case *ast.ParenExpr:
+		return stringLiteral(expr.X)
+	}
+	return "", false
+}
+
+// hasLetters reports whether s holds text to translate, rather than only
+// format verbs, numbers and symbols
+func hasLetters(s string) bool {
+	for _, verb := range []string{"%v", "%s", "%d", "%q", "%x", "%f"} {
+		s = strings.ReplaceAll(s, verb, "")
+	}
+	return strings.IndexFunc(s, unicode.IsLetter) >= 0
+}
+
+func TestCatalogCoversMessages(t *testing.T) {
+	fset, files := parseSources(t)
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			name, ok := call.Fun.(*ast.Ident)
+			if !ok || (name.Name != "tr" && name.Name != "printInfo") {
+				return true
+			}
+			msg, ok := stringLiteral(call.Args[0])
+			if !ok || !hasLetters(msg) {
+				return true
+			}
+			if _, ok := catalogES[msg]; !ok {
+				t.Errorf("%s: %q is missing from catalogES", fset.Position(call.Pos()), msg)
+			}
+			return true
+		})
+	}
+}
+
+func TestOutputIsTranslated(t *testing.T) {
+	fset, files := parseSources(t)
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			fun, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if pkg, ok := fun.X.(*ast.Ident); !ok || pkg.Name != "fmt" {
+				return true
+			}
+			var msg ast.Expr
+			switch fun.Sel.Name {
+			case "Print", "Printf", "Println":
+				if len(call.Args) > 0 {
+					msg = call.Args[0]
+				}
+			case "Fprint", "Fprintf", "Fprintln":
+				// Only the terminal, other writers get data such as
+				// exported records
+				if len(call.Args) > 1 && isStdStream(call.Args[0]) {
+					msg = call.Args[1]
+				}
+			}
+			if msg == nil {
+				return true
+			}
+			if value, ok := stringLiteral(msg); ok && hasLetters(value) && !untranslatedOutput[value] {
+				t.Errorf("%s: %q is printed without tr", fset.Position(call.Pos()), value)
+			}
+			return true
+		})
+	}
+}
+
+// isStdStream reports whether expr is os.Stdout or os.Stderr
+func isStdStream(expr ast.Expr) bool {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := selector.X.(*ast.Ident)
+	return ok && pkg.Name == "os" && (selector.Sel.Name == "Stdout" || selector.Sel.Name == "Stderr")
+}
+
+func TestCatalogCoversCommands(t *testing.T) {
+	var walk func(*cobra.Command)
+	walk = func(c *cobra.Command) {
+		for _, text := range []string{c.Short, c.Long} {
+			if _, ok := catalogES[text]; text != "" && !ok {
+				t.Errorf("%s: %q is missing from catalogES", c.CommandPath(), text)
+			}
+		}
+		checkFlag := func(f *pflag.Flag) {
+			if _, ok := catalogES[f.Usage]; !ok {
+				t.Errorf("%s --%s: %q is missing from catalogES", c.CommandPath(), f.Name, f.Usage)
+			}
+		}
+		c.LocalNonPersistentFlags().VisitAll(checkFlag)
+		c.PersistentFlags().VisitAll(checkFlag)
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(rootCmd)
+}
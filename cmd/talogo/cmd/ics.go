@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// icsEvent is a calendar event read from an ICS file
+type icsEvent struct {
+	UID          string
+	Summary      string
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	Cancelled    bool
+	Attendees    []string
+	RRule        string
+	ExDates      []time.Time
+	RecurrenceID time.Time // Start of the occurrence this event overrides
+}
+
+// icsProperty is a content line of an ICS file
+type icsProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// parseICS reads the events of an ICS calendar
+func parseICS(r io.Reader) ([]icsEvent, error) {
+	lines, err := unfoldICSLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []icsEvent
+	var event *icsEvent
+	var duration time.Duration
+	hasEnd := false
+	for i, line := range lines {
+		prop := parseICSProperty(line)
+		switch {
+		case prop.Name == "BEGIN" && prop.Value == "VEVENT":
+			event, duration, hasEnd = &icsEvent{}, 0, false
+			continue
+		case prop.Name == "END" && prop.Value == "VEVENT":
+			if event == nil {
+				continue
+			}
+			if !hasEnd {
+				event.End = event.Start.Add(duration)
+			}
+			if !event.Start.IsZero() {
+				events = append(events, *event)
+			}
+			event = nil
+			continue
+		case event == nil:
+			continue
+		}
+
+		var err error
+		switch prop.Name {
+		case "UID":
+			event.UID = prop.Value
+		case "SUMMARY":
+			event.Summary = unescapeICSText(prop.Value)
+		case "STATUS":
+			event.Cancelled = prop.Value == "CANCELLED"
+		case "DTSTART":
+			event.Start, event.AllDay, err = parseICSTime(prop)
+		case "DTEND":
+			event.End, _, err = parseICSTime(prop)
+			hasEnd = true
+		case "DURATION":
+			duration, err = parseICSDuration(prop.Value)
+		case "RRULE":
+			event.RRule = prop.Value
+		case "EXDATE":
+			for _, value := range strings.Split(prop.Value, ",") {
+				var exdate time.Time
+				exdate, _, err = parseICSTime(icsProperty{Params: prop.Params, Value: value})
+				if err != nil {
+					break
+				}
+				event.ExDates = append(event.ExDates, exdate)
+			}
+		case "RECURRENCE-ID":
+			event.RecurrenceID, _, err = parseICSTime(prop)
+		case "ATTENDEE":
+			name := prop.Params["CN"]
+			if name == "" {
+				name = strings.TrimPrefix(strings.TrimPrefix(prop.Value, "mailto:"), "MAILTO:")
+			}
+			event.Attendees = append(event.Attendees, name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+	}
+	return events, nil
+}
+
+// unfoldICSLines returns the logical lines of an ICS file, joining the
+// continuation lines that start with a space or a tab
+func unfoldICSLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %v", err)
+	}
+	return lines, nil
+}
+
+// parseICSProperty splits a content line into its name, parameters and
+// value. Parameter values may be quoted and contain colons.
+func parseICSProperty(line string) icsProperty {
+	prop := icsProperty{Params: make(map[string]string)}
+
+	// The value starts at the first colon outside quotes
+	quoted, split := false, len(line)
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			split = i
+			break
+		}
+	}
+	if split < len(line) {
+		prop.Value = line[split+1:]
+	}
+
+	parts := strings.Split(line[:split], ";")
+	prop.Name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		prop.Params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return prop
+}
+
+// parseICSTime parses a DATE or DATE-TIME value, in UTC, in the zone of
+// its TZID parameter or in local time. It also reports whether the value
+// is a date without time.
+func parseICSTime(prop icsProperty) (time.Time, bool, error) {
+	value := prop.Value
+	if prop.Params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, time.Local)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid time %q", value)
+		}
+		return t, false, nil
+	}
+
+	loc := time.Local
+	if tzid := prop.Params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid time %q", value)
+	}
+	return t, false, nil
+}
+
+// icsDurationPattern matches durations such as P1W, PT1H30M or P1DT2H
+var icsDurationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseICSDuration parses a DURATION value
+func parseICSDuration(value string) (time.Duration, error) {
+	m := icsDurationPattern.FindStringSubmatch(value)
+	if m == nil {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	var d time.Duration
+	for i, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if m[i+2] != "" {
+			n, _ := strconv.Atoi(m[i+2])
+			d += time.Duration(n) * unit
+		}
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+// unescapeICSText unescapes a TEXT value
+func unescapeICSText(value string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
+
+// icsWeekdays maps RRULE day names to weekdays
+var icsWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// occurrences returns the start times of the occurrences of event up to
+// until, expanding its recurrence rule. Supported rules are DAILY, WEEKLY
+// (optionally with BYDAY), MONTHLY and YEARLY with INTERVAL, COUNT and
+// UNTIL; for other rules only the first occurrence is returned and ok is
+// false.
+func (event icsEvent) occurrences(until time.Time) (starts []time.Time, ok bool) {
+	if event.RRule == "" {
+		return []time.Time{event.Start}, true
+	}
+
+	var freq string
+	interval, count := 1, 0
+	var ruleUntil time.Time
+	var byDay []time.Weekday
+	for _, part := range strings.Split(event.RRule, ";") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "FREQ":
+			freq = value
+		case "INTERVAL":
+			interval, _ = strconv.Atoi(value)
+		case "COUNT":
+			count, _ = strconv.Atoi(value)
+		case "UNTIL":
+			ruleUntil, _, _ = parseICSTime(icsProperty{Value: value})
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, known := icsWeekdays[day]
+				if !known {
+					return []time.Time{event.Start}, false // e.g. 1MO, the first Monday
+				}
+				byDay = append(byDay, weekday)
+			}
+		case "WKST":
+		default:
+			return []time.Time{event.Start}, false
+		}
+	}
+	if interval < 1 || (len(byDay) > 0 && freq != "WEEKLY") {
+		return []time.Time{event.Start}, false
+	}
+	if !ruleUntil.IsZero() && ruleUntil.Before(until) {
+		until = ruleUntil
+	}
+
+	// Occurrences keep the wall clock time of the first one, also across
+	// daylight saving changes
+	start := event.Start
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	}
+
+	var candidates func(i int) []time.Time
+	switch freq {
+	case "DAILY":
+		candidates = func(i int) []time.Time {
+			return []time.Time{at(start.Year(), start.Month(), start.Day()+i*interval)}
+		}
+	case "WEEKLY":
+		if len(byDay) == 0 {
+			byDay = []time.Weekday{start.Weekday()}
+		}
+		// Days of each week, counted from its Monday
+		var offsets []int
+		for _, weekday := range byDay {
+			offsets = append(offsets, (int(weekday)+6)%7)
+		}
+		sort.Ints(offsets)
+		monday := start.Day() - (int(start.Weekday())+6)%7
+		candidates = func(i int) []time.Time {
+			var days []time.Time
+			for _, offset := range offsets {
+				days = append(days, at(start.Year(), start.Month(), monday+i*7*interval+offset))
+			}
+			return days
+		}
+	case "MONTHLY", "YEARLY":
+		candidates = func(i int) []time.Time {
+			months := i * interval
+			if freq == "YEARLY" {
+				months *= 12
+			}
+			t := at(start.Year(), start.Month()+time.Month(months), start.Day())
+			if t.Day() != start.Day() {
+				return nil // e.g. the 31st in a shorter month
+			}
+			return []time.Time{t}
+		}
+	default:
+		return []time.Time{event.Start}, false
+	}
+
+	for i := 0; ; i++ {
+		for _, t := range candidates(i) {
+			if t.Before(start) {
+				continue
+			}
+			if t.After(until) || (count > 0 && len(starts) == count) {
+				return starts, true
+			}
+			starts = append(starts, t)
+		}
+		// Stop on rules whose candidates never reach until, like the 31st
+		// of every February
+		if i > 100000 {
+			return starts, true
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// hidIdleTimePattern matches the idle time, in nanoseconds, reported by
+// the IOHIDSystem entry of the macOS I/O Kit registry
+var hidIdleTimePattern = regexp.MustCompile(`"HIDIdleTime" = (\d+)`)
+
+// macIdleTime returns the time since the last keyboard or mouse input on
+// macOS, read from the I/O Kit registry
+func macIdleTime() (time.Duration, error) {
+	out, err := exec.Command("ioreg", "-c", "IOHIDSystem", "-d", "4").Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to run ioreg: %v", err)
+	}
+	m := hidIdleTimePattern.FindSubmatch(out)
+	if m == nil {
+		return 0, fmt.Errorf("HIDIdleTime not found in the I/O Kit registry")
+	}
+	ns, err := strconv.ParseInt(string(m[1]), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid HIDIdleTime %q", m[1])
+	}
+	return time.Duration(ns), nil
+}
+
+// linuxIdleTime returns the time since the last keyboard or mouse input
+// on Linux, as reported by the idle monitor of GNOME or the screensaver
+// interface of KDE over the session D-Bus
+func linuxIdleTime() (time.Duration, error) {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to D-Bus: %v", err)
+	}
+
+	var ms uint64
+	err = conn.Object("org.gnome.Mutter.IdleMonitor", "/org/gnome/Mutter/IdleMonitor/Core").
+		Call("org.gnome.Mutter.IdleMonitor.GetIdletime", 0).Store(&ms)
+	if err == nil {
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+
+	var seconds uint32
+	err = conn.Object("org.freedesktop.ScreenSaver", "/org/freedesktop/ScreenSaver").
+		Call("org.freedesktop.ScreenSaver.GetSessionIdleTime", 0).Store(&seconds)
+	if err != nil {
+		return 0, fmt.Errorf("no idle monitor found on D-Bus")
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
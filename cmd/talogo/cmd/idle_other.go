@@ -0,0 +1,20 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// systemIdleTime returns the time since the last keyboard or mouse input
+func systemIdleTime() (time.Duration, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return macIdleTime()
+	case "linux":
+		return linuxIdleTime()
+	}
+	return 0, fmt.Errorf("idle detection is not supported on %s", runtime.GOOS)
+}
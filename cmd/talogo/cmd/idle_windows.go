@@ -0,0 +1,33 @@
+//go:build windows
+
+package cmd
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	procGetLastInputInfo = syscall.NewLazyDLL("user32.dll").NewProc("GetLastInputInfo")
+	procGetTickCount     = syscall.NewLazyDLL("kernel32.dll").NewProc("GetTickCount")
+)
+
+// lastInputInfo is the LASTINPUTINFO structure of the Windows API
+type lastInputInfo struct {
+	size uint32
+	time uint32 // Tick count of the last input
+}
+
+// systemIdleTime returns the time since the last keyboard or mouse input
+func systemIdleTime() (time.Duration, error) {
+	info := lastInputInfo{size: uint32(unsafe.Sizeof(lastInputInfo{}))}
+	if ok, _, err := procGetLastInputInfo.Call(uintptr(unsafe.Pointer(&info))); ok == 0 {
+		return 0, fmt.Errorf("GetLastInputInfo failed: %v", err)
+	}
+	// Tick counts wrap around every 49.7 days, which the unsigned
+	// subtraction accounts for
+	now, _, _ := procGetTickCount.Call()
+	return time.Duration(uint32(now)-info.time) * time.Millisecond, nil
+}
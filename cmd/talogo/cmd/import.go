@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importICSCmdLogFile     string
+	importICSCmdCalendarURL string
+	importICSCmdFrom        string
+	importICSCmdTo          string
+	importICSCmdPrefix      string
+	importICSCmdAttendees   bool
+)
+
+// importCmd groups the commands importing sessions from other sources
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import sessions from calendars and other tools",
+}
+
+var importICSCmd = &cobra.Command{
+	Use:   "ics [FILE]",
+	Short: "Import calendar events from an ICS file or URL as sessions",
+	Long: `Import the events of an ICS calendar, read from FILE or downloaded from
+--calendar-url (e.g. the secret iCal address of a Google Calendar), as
+sessions titled with the event summary.
+
+Only events that already ended are imported, and all-day and cancelled
+events are skipped. Events already in the log are not imported again.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if (len(args) == 1) == (importICSCmdCalendarURL != "") {
+			fmt.Fprintln(os.Stderr, tr("Error: give either an ICS file or --calendar-url"))
+			os.Exit(1)
+		}
+
+		var from, to time.Time
+		var err error
+		if importICSCmdFrom != "" {
+			if from, err = time.ParseInLocation("2006-01-02", importICSCmdFrom, time.Local); err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error: invalid --from date %q (expected YYYY-MM-DD)\n"), importICSCmdFrom)
+				os.Exit(1)
+			}
+		}
+		to = time.Now()
+		if importICSCmdTo != "" {
+			day, err := time.ParseInLocation("2006-01-02", importICSCmdTo, time.Local)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error: invalid --to date %q (expected YYYY-MM-DD)\n"), importICSCmdTo)
+				os.Exit(1)
+			}
+			if end := day.AddDate(0, 0, 1); end.Before(to) {
+				to = end
+			}
+		}
+
+		var calendar io.ReadCloser
+		if importICSCmdCalendarURL != "" {
+			calendar, err = fetchCalendar(importICSCmdCalendarURL)
+		} else {
+			calendar, err = os.Open(args[0])
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error reading calendar: %v\n"), err)
+			os.Exit(1)
+		}
+		defer calendar.Close()
+
+		events, err := parseICS(calendar)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error reading calendar: %v\n"), err)
+			os.Exit(1)
+		}
+
+		var prefix []string
+		if importICSCmdPrefix != "" {
+			prefix = strings.Split(strings.Trim(importICSCmdPrefix, "/"), "/")
+		}
+
+		if err := importEvents(importICSCmdLogFile, events, from, to, prefix, importICSCmdAttendees); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error importing events: %v\n"), err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	importICSCmd.Flags().StringVarP(&importICSCmdLogFile, "file", "f", defaultLogFile(), "Log file to write")
+	importICSCmd.Flags().StringVar(&importICSCmdCalendarURL, "calendar-url", "", "Download the calendar from this URL instead of reading a file")
+	importICSCmd.Flags().StringVar(&importICSCmdFrom, "from", "", "Only import events starting on or after this date (YYYY-MM-DD)")
+	importICSCmd.Flags().StringVar(&importICSCmdTo, "to", "", "Only import events starting on or before this date (YYYY-MM-DD, default today)")
+	importICSCmd.Flags().StringVar(&importICSCmdPrefix, "prefix", "", "Task path the event summaries are nested under (e.g. \"meetings\")")
+	importICSCmd.Flags().BoolVar(&importICSCmdAttendees, "attendees", false, "Store the attendees of each event in the session notes")
+	importCmd.AddCommand(importICSCmd)
+	rootCmd.AddCommand(importCmd)
+}
+
+// fetchCalendar downloads a calendar, accepting webcal:// URLs
+func fetchCalendar(url string) (io.ReadCloser, error) {
+	if rest, ok := strings.CutPrefix(url, "webcal://"); ok {
+		url = "https://" + rest
+	}
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// importEvents appends the occurrences of events that start between from
+// and to and have ended, skipping the ones already in the log. Sessions
+// are titled with the event summary nested under prefix, and list the
+// attendees in their notes if attendees is set.
+func importEvents(logFile string, events []icsEvent, from, to time.Time, prefix []string, attendees bool) error {
+	dayStart, err := configuredDayStart()
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]bool)
+	if _, err := os.Stat(logPath(logFile)); err == nil {
+		records, err := readRecords(logFile)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			existing[importKey(record)] = true
+		}
+	}
+
+	// Occurrences moved or cancelled individually replace the generated ones
+	overridden := make(map[string]bool)
+	for _, event := range events {
+		if !event.RecurrenceID.IsZero() {
+			overridden[event.UID+"|"+event.RecurrenceID.UTC().Format(time.RFC3339)] = true
+		}
+	}
+
+	now := time.Now()
+	var records []Record
+	imported, skipped := 0, 0
+	for _, event := range events {
+		if event.AllDay || event.Cancelled || strings.TrimSpace(event.Summary) == "" {
+			continue
+		}
+
+		starts, ok := event.occurrences(to)
+		if !ok {
+			fmt.Fprintf(os.Stderr, tr("Warning: unsupported recurrence of %q (%s), only its first occurrence is imported\n"), event.Summary, event.RRule)
+		}
+	occurrences:
+		for _, start := range starts {
+			end := start.Add(event.End.Sub(event.Start))
+			if start.Before(from) || start.After(to) || end.After(now) {
+				continue
+			}
+			if event.RecurrenceID.IsZero() && overridden[event.UID+"|"+start.UTC().Format(time.RFC3339)] {
+				continue
+			}
+			for _, exdate := range event.ExDates {
+				if exdate.Equal(start) {
+					continue occurrences
+				}
+			}
+
+			record := withOrigin(Record{
+				Start:  start,
+				End:    end,
+				Titles: append(append([]string{}, prefix...), event.Summary),
+				Source: talogo.SourceImport + ":ics",
+			})
+			if attendees && len(event.Attendees) > 0 {
+				record.Notes = "Attendees: " + strings.Join(event.Attendees, ", ")
+			}
+
+			added := false
+			for _, part := range talogo.SplitByDay(record, dayStart) {
+				if existing[importKey(part)] {
+					continue
+				}
+				existing[importKey(part)] = true
+				records = append(records, part)
+				added = true
+			}
+			if added {
+				imported++
+			} else {
+				skipped++
+			}
+		}
+	}
+
+	if len(records) > 0 {
+		if err := appendRecords(logFile, records); err != nil {
+			return err
+		}
+	}
+	message := fmt.Sprintf(tr("Imported %d events"), imported)
+	if skipped > 0 {
+		message += fmt.Sprintf(tr(", skipped %d already in the log"), skipped)
+	}
+	printInfo("%s\n", message)
+	return nil
+}
+
+// importKey identifies an imported session by its times and titles,
+// regardless of the zone the times are written in
+func importKey(record Record) string {
+	return recordKey(Record{Start: record.Start.UTC(), End: record.End.UTC(), Titles: record.Titles})
+}
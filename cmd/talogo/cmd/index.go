@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+)
+
+// indexPathSep separates titles in the task paths used as index keys
+const indexPathSep = "\x1f"
+
+// logIndex holds per-day per-task totals of a log file so reports don't
+// have to parse the whole log on every invocation. It is only valid while
+// the log file size and modification time match the recorded ones.
+type logIndex struct {
+	Size     int64                               `json:"size"`
+	ModTime  time.Time                           `json:"mod_time"`
+	Records  int                                 `json:"records"`
+	DayStart time.Duration                       `json:"day_start"`          // Day boundary the days were bucketed with
+	Days     map[string]map[string]time.Duration `json:"days"`               // date -> task path -> duration
+	Parallel bool                                `json:"parallel,omitempty"` // Whether the log has parallel records
+}
+
+// indexFile returns the path of the index of logFile
+func indexFile(logFile string) string {
+	return logPath(logFile) + ".index.json"
+}
+
+// newLogIndex returns an empty index bucketing records in days that begin
+// dayStart after midnight
+func newLogIndex(dayStart time.Duration) *logIndex {
+	return &logIndex{DayStart: dayStart, Days: make(map[string]map[string]time.Duration)}
+}
+
+// loadIndex returns the index of logFile if it exists and is up to date
+func loadIndex(logFile string) (*logIndex, bool) {
+	info, err := os.Stat(logPath(logFile))
+	if err != nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(indexFile(logFile))
+	if err != nil {
+		return nil, false
+	}
+
+	index := newLogIndex(0)
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, false
+	}
+	if index.Size != info.Size() || !index.ModTime.Equal(info.ModTime()) {
+		return nil, false
+	}
+	return index, true
+}
+
+// saveIndex stamps index with the current size and modification time of
+// logFile and writes it. Failing to save just leaves the index stale.
+func saveIndex(logFile string, index *logIndex) {
+	info, err := os.Stat(logPath(logFile))
+	if err != nil {
+		return
+	}
+	index.Size = info.Size()
+	index.ModTime = info.ModTime()
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		return
+	}
+	if err := os.WriteFile(indexFile(logFile), data, 0644); err != nil {
+		os.Remove(indexFile(logFile))
+	}
+}
+
+// invalidateIndex removes the index of logFile
+func invalidateIndex(logFile string) {
+	os.Remove(indexFile(logFile))
+}
+
+// add adds the duration of record to the totals of its day and task
+func (ix *logIndex) add(record Record) {
+	date := talogo.DayOf(record.Start, ix.DayStart)
+	if _, exists := ix.Days[date]; !exists {
+		ix.Days[date] = make(map[string]time.Duration)
+	}
+	ix.Days[date][strings.Join(record.Titles, indexPathSep)] += record.Duration()
+	ix.Records++
+	ix.Parallel = ix.Parallel || isParallel(record)
+}
+
+// forEach calls fn with a synthetic record for each day and task total,
+// starting at the beginning of its day
+func (ix *logIndex) forEach(fn func(Record)) {
+	for date, tasks := range ix.Days {
+		day, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			continue
+		}
+		day = day.Add(ix.DayStart)
+		for path, duration := range tasks {
+			var titles []string
+			if path != "" {
+				titles = strings.Split(path, indexPathSep)
+			}
+			fn(Record{
+				Start:   day,
+				End:     day.Add(duration),
+				Titles:  titles,
+				Elapsed: duration,
+			})
+		}
+	}
+}
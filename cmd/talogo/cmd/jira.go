@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncJiraCmdLogFile string
+	syncJiraCmdFrom    string
+	syncJiraCmdTo      string
+	syncJiraCmdDryRun  bool
+)
+
+// jiraIssueKey matches Jira issue keys such as PROJ-123
+var jiraIssueKey = regexp.MustCompile(`\b[A-Z][A-Z0-9_]+-[0-9]+\b`)
+
+var syncJiraCmd = &cobra.Command{
+	Use:   "jira",
+	Short: "Create Jira worklogs for sessions whose titles mention an issue key",
+	Long: `Create Jira worklogs for the sessions in the date range whose titles
+mention an issue key (e.g. PROJ-123), using the server in the [jira]
+section of the config file. Synced sessions are remembered in a
+.jira.json file next to the log, so they are never posted twice.
+
+Jira only accepts worklogs of at least one minute, so durations are
+rounded to the nearest minute and shorter sessions are skipped.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := loadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		if config.Jira.URL == "" || config.Jira.Token == "" {
+			fmt.Fprint(os.Stderr, tr("Error: set url and token in the [jira] section of the config file\n"))
+			os.Exit(1)
+		}
+
+		from, to, err := syncDateRange(syncJiraCmdFrom, syncJiraCmdTo)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+
+		if err := pushRecords(syncJiraCmdLogFile, jiraService(config.Jira), from, to, syncJiraCmdDryRun); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error syncing with Jira: %v\n"), err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	syncJiraCmd.Flags().StringVarP(&syncJiraCmdLogFile, "file", "f", defaultLogFile(), "Log file to read")
+	syncJiraCmd.Flags().StringVar(&syncJiraCmdFrom, "from", "", "First day to sync (YYYY-MM-DD, default today)")
+	syncJiraCmd.Flags().StringVar(&syncJiraCmdTo, "to", "", "Last day to sync (YYYY-MM-DD, default today)")
+	syncJiraCmd.Flags().BoolVar(&syncJiraCmdDryRun, "dry-run", false, "Print the worklogs that would be created without posting them")
+	syncCmd.AddCommand(syncJiraCmd)
+}
+
+// jiraService returns the Jira worklog service of the server in config
+func jiraService(jira JiraConfig) pushService {
+	headers := map[string]string{"Authorization": "Bearer " + jira.Token}
+	if jira.Email != "" {
+		auth := base64.StdEncoding.EncodeToString([]byte(jira.Email + ":" + jira.Token))
+		headers["Authorization"] = "Basic " + auth
+	}
+
+	return pushService{
+		name: "jira",
+		target: func(record Record) string {
+			issue := jiraIssueKey.FindString(strings.Join(record.Titles, " "))
+			if issue != "" && record.Duration().Round(time.Minute) == 0 {
+				fmt.Printf(tr("Skipping %s: shorter than a minute\n"), formatRecord(record))
+				return ""
+			}
+			return issue
+		},
+		post: func(record Record, issue string) (string, error) {
+			comment := strings.Join(record.Titles, " / ")
+			if record.Notes != "" {
+				comment += "\n\n" + record.Notes
+			}
+			body := map[string]interface{}{
+				"started":          record.Start.Format("2006-01-02T15:04:05.000-0700"),
+				"timeSpentSeconds": int(record.Duration().Round(time.Minute).Seconds()),
+				"comment":          comment,
+			}
+
+			var worklog struct {
+				ID string `json:"id"`
+			}
+			url := strings.TrimSuffix(jira.URL, "/") + "/rest/api/2/issue/" + issue + "/worklog"
+			if err := postJSON(url, body, headers, &worklog); err != nil {
+				return "", err
+			}
+			return worklog.ID, nil
+		},
+	}
+}
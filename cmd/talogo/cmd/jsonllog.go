@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+)
+
+// scanJSONLRecords calls fn for each valid record of a JSONL log file, and
+// onBad for each invalid one
+func scanJSONLRecords(logFile string, onBad talogo.BadRecordFunc, fn func(Record) error) error {
+	file, err := os.Open(logFile)
+	if err != nil {
+		return fmt.Errorf("failed to open JSONL file: %v", err)
+	}
+	defer file.Close()
+
+	return talogo.ScanJSONL(file, onBad, fn)
+}
+
+// appendJSONLRecords appends records to a JSONL log file, one JSON object
+// per line, creating the file if it does not exist
+func appendJSONLRecords(logFile string, records []Record) error {
+	file, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open/create JSONL file: %v", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	if err := talogo.WriteJSONL(writer, records, logPrecision()); err != nil {
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write JSONL record: %v", err)
+	}
+
+	// Ensure all data is written to disk
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync file: %v", err)
+	}
+
+	return nil
+}
+
+// jsonlStore is the JSON-Lines backend, one JSON object per record
+type jsonlStore struct {
+	path string
+}
+
+func (s jsonlStore) Append(records []Record) error {
+	return appendJSONLRecords(s.path, records)
+}
+
+func (s jsonlStore) List(onBad talogo.BadRecordFunc, fn func(Record) error) error {
+	return scanJSONLRecords(s.path, onBad, fn)
+}
+
+func (s jsonlStore) Query(from, to time.Time, fn func(Record) error) error {
+	return queryByScanning(s, from, to, fn)
+}
+
+func (s jsonlStore) Rewrite(records []Record) error {
+	return replaceLogFile(s.path, func(w io.Writer) error {
+		return talogo.WriteJSONL(w, records, logPrecision())
+	})
+}
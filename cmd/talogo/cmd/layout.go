@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"strings"
+)
+
+// cutLine returns line cut to width runes, ending in … when cut. A width
+// of 0 or less, as when the terminal size is not known, leaves it whole.
+func cutLine(line string, width int) string {
+	runes := []rune(line)
+	if width <= 0 || len(runes) <= width {
+		return line
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// wrapLine splits line in lines of at most width runes, breaking at
+// spaces when possible. A width of 0 or less leaves it whole.
+func wrapLine(line string, width int) []string {
+	var lines []string
+	runes := []rune(line)
+	for width > 0 && len(runes) > width {
+		cut := width
+		for i := width; i > 0; i-- {
+			if runes[i] == ' ' {
+				cut = i
+				break
+			}
+		}
+		lines = append(lines, strings.TrimRight(string(runes[:cut]), " "))
+		runes = []rune(strings.TrimLeft(string(runes[cut:]), " "))
+	}
+	return append(lines, string(runes))
+}
+
+// wrapText wraps each line of text to width, see wrapLine
+func wrapText(text string, width int) string {
+	if width <= 0 || text == "" {
+		return text
+	}
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		lines = append(lines, wrapLine(line, width)...)
+	}
+	wrapped := strings.Join(lines, "\n")
+	if strings.HasSuffix(text, "\n") {
+		wrapped += "\n"
+	}
+	return wrapped
+}
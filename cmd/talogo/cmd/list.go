@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listCmdLogFile  string
+	listCmdLast     int
+	listCmdDate     string
+	listCmdHosts    []string
+	listCmdCoalesce time.Duration
+)
+
+// listCmd defines the list subcommand
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List logged sessions with their ids",
+	Run: func(cmd *cobra.Command, args []string) {
+		records, err := readRecords(listCmdLogFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error reading log: %v\n"), err)
+			os.Exit(1)
+		}
+
+		if records, err = coalesceRecords(records, listCmdCoalesce); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+
+		var filtered []Record
+		for _, record := range records {
+			if listCmdDate != "" && record.Start.Format("2006-01-02") != listCmdDate {
+				continue
+			}
+			if !record.MatchesHost(listCmdHosts) {
+				continue
+			}
+			filtered = append(filtered, record)
+		}
+		records = filtered
+		if listCmdLast > 0 && len(records) > listCmdLast {
+			records = records[len(records)-listCmdLast:]
+		}
+
+		if jsonOutput() {
+			entries := make([]talogo.JSONRecord, len(records))
+			for i, record := range records {
+				entries[i] = talogo.NewJSONRecord(record, logPrecision())
+			}
+			if err := printJSON(entries); err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+				os.Exit(1)
+			}
+			return
+		}
+		printRecordsTable(records)
+	},
+}
+
+func init() {
+	listCmd.Flags().StringVarP(&listCmdLogFile, "file", "f", defaultLogFile(), "Log file to read")
+	listCmd.Flags().IntVarP(&listCmdLast, "last", "n", 0, "Only list the last N sessions")
+	listCmd.Flags().StringSliceVar(&listCmdHosts, "host", nil, "Only list sessions tracked on these hosts")
+	listCmd.Flags().StringVar(&listCmdDate, "date", "", "Only list sessions started on this date (YYYY-MM-DD)")
+	listCmd.Flags().DurationVar(&listCmdCoalesce, "coalesce", 0, "Merge back-to-back sessions of the same task separated by less than this (e.g. 5m)")
+	rootCmd.AddCommand(listCmd)
+}
+
+// printRecordsTable prints records as a table, with the host and user
+// they were tracked by if any of them has one
+func printRecordsTable(records []Record) {
+	if len(records) == 0 {
+		return
+	}
+	origins := false
+	for _, record := range records {
+		origins = origins || record.Host != "" || record.User != ""
+	}
+
+	headers := []string{tr("ID"), tr("Start"), tr("End"), tr("Duration"), tr("Task")}
+	if origins {
+		headers = append(headers, tr("Origin"))
+	}
+	rows := make([][]string, len(records))
+	for i, record := range records {
+		id := record.ID
+		if id == "" {
+			id = fmt.Sprintf("line:%d", record.Line)
+		}
+		rows[i] = []string{
+			id,
+			record.Start.Format("2006-01-02 " + clockLayout),
+			record.End.Format(clockLayout),
+			record.Duration().Round(time.Second).String(),
+			strings.Join(sanitizedTitles(record.Titles), " / "),
+		}
+		if origins {
+			origin := ""
+			if record.Host != "" || record.User != "" {
+				origin = fmt.Sprintf("%s@%s", record.User, record.Host)
+			}
+			rows[i] = append(rows[i], origin)
+		}
+	}
+	printTable(headers, rows, 3)
+}
+
+// printRecord prints a record on a single line
+func printRecord(record Record) {
+	fmt.Println(formatRecord(record))
+}
+
+// formatRecord formats a record on a single line
+func formatRecord(record Record) string {
+	id := record.ID
+	if id == "" {
+		id = fmt.Sprintf("line:%d", record.Line)
+	}
+	origin := ""
+	if record.Host != "" || record.User != "" {
+		origin = fmt.Sprintf("  (%s@%s)", record.User, record.Host)
+	}
+	// Titles logged with line breaks would split the line
+	return fmt.Sprintf("%s  %s - %s  %8s  %s%s",
+		id,
+		record.Start.Format("2006-01-02 "+clockLayout),
+		record.End.Format(clockLayout),
+		record.Duration().Round(time.Second),
+		strings.Join(sanitizedTitles(record.Titles), " / "),
+		origin,
+	)
+}
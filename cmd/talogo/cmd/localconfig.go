@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// localConfigName is the name of the per-directory config file, usually
+// dropped in the root of a repository
+const localConfigName = ".talogo.toml"
+
+// LocalConfig holds the defaults read from the .talogo.toml file of the
+// working directory or its closest parent that has one
+type LocalConfig struct {
+	// Prefix is a task path (titles joined by "/") prepended to the titles
+	// given to 'talogo log', e.g. "client-x"
+	Prefix string `toml:"prefix"`
+	// File is the log file, relative to the directory of .talogo.toml
+	File string `toml:"file"`
+	// Tags are attached to sessions started without --tag
+	Tags []string `toml:"tags"`
+}
+
+var loadedLocalConfig *LocalConfig
+
+// loadLocalConfig finds the closest .talogo.toml walking up from the
+// working directory and returns it, with File made absolute. It returns an
+// empty config if there is none.
+func loadLocalConfig() (*LocalConfig, error) {
+	if loadedLocalConfig != nil {
+		return loadedLocalConfig, nil
+	}
+
+	config := &LocalConfig{}
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %v", err)
+	}
+	for {
+		path := filepath.Join(dir, localConfigName)
+		if _, err := toml.DecodeFile(path, config); err == nil {
+			if config.File != "" {
+				config.File = expandHome(config.File)
+				if !filepath.IsAbs(config.File) {
+					config.File = filepath.Join(dir, config.File)
+				}
+			}
+			break
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read %s: %v", path, err)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	loadedLocalConfig = config
+	return config, nil
+}
+
+// withPrefix returns titles prepended with the task path of the prefix,
+// unless they already start with it
+func (c *LocalConfig) withPrefix(titles []string) []string {
+	if c.Prefix == "" {
+		return titles
+	}
+	prefix := strings.Split(strings.Trim(c.Prefix, "/"), "/")
+	if len(titles) >= len(prefix) && strings.Join(titles[:len(prefix)], "/") == strings.Join(prefix, "/") {
+		return titles
+	}
+	return append(prefix, titles...)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// watchScreenLock reports screen lock changes on the returned channel:
+// true when the screen locks and false when it is unlocked. It listens to
+// the lock state of the logind session and to the screensaver interfaces
+// of the desktop, on the system and session D-Bus respectively. Changes
+// may be reported more than once.
+func watchScreenLock() (<-chan bool, error) {
+	if runtime.GOOS != "linux" {
+		return nil, fmt.Errorf("screen lock detection is only supported on Linux")
+	}
+
+	events := make(chan bool, 8)
+	signals := make(chan *dbus.Signal, 16)
+	watching := false
+
+	if conn, err := dbus.ConnectSystemBus(); err == nil {
+		if path, err := logindSession(conn); err == nil {
+			conn.AddMatchSignal(dbus.WithMatchObjectPath(path), dbus.WithMatchInterface("org.freedesktop.login1.Session"))
+			conn.AddMatchSignal(dbus.WithMatchObjectPath(path), dbus.WithMatchInterface("org.freedesktop.DBus.Properties"),
+				dbus.WithMatchMember("PropertiesChanged"))
+			conn.Signal(signals)
+			watching = true
+		}
+	}
+
+	if conn, err := dbus.ConnectSessionBus(); err == nil {
+		for _, iface := range []string{"org.freedesktop.ScreenSaver", "org.gnome.ScreenSaver", "org.mate.ScreenSaver", "org.cinnamon.ScreenSaver"} {
+			conn.AddMatchSignal(dbus.WithMatchInterface(iface), dbus.WithMatchMember("ActiveChanged"))
+		}
+		conn.Signal(signals)
+		watching = true
+	}
+
+	if !watching {
+		return nil, fmt.Errorf("failed to connect to D-Bus")
+	}
+
+	go func() {
+		for signal := range signals {
+			if locked, ok := lockSignal(signal); ok {
+				events <- locked
+			}
+		}
+	}()
+	return events, nil
+}
+
+// logindSession returns the object path of the logind session of this
+// process, or of the user's graphical session when running outside one
+// (e.g. in a terminal multiplexer started by systemd)
+func logindSession(conn *dbus.Conn) (dbus.ObjectPath, error) {
+	manager := conn.Object("org.freedesktop.login1", "/org/freedesktop/login1")
+	var path dbus.ObjectPath
+	err := manager.Call("org.freedesktop.login1.Manager.GetSessionByPID", 0, uint32(os.Getpid())).Store(&path)
+	if err != nil {
+		err = manager.Call("org.freedesktop.login1.Manager.GetSession", 0, "auto").Store(&path)
+	}
+	return path, err
+}
+
+// lockSignal returns the lock state announced by a D-Bus signal, if any
+func lockSignal(signal *dbus.Signal) (locked bool, ok bool) {
+	switch signal.Name {
+	case "org.freedesktop.login1.Session.Lock":
+		return true, true
+	case "org.freedesktop.login1.Session.Unlock":
+		return false, true
+	case "org.freedesktop.DBus.Properties.PropertiesChanged":
+		if len(signal.Body) < 2 || signal.Body[0] != "org.freedesktop.login1.Session" {
+			return false, false
+		}
+		changed, _ := signal.Body[1].(map[string]dbus.Variant)
+		if hint, found := changed["LockedHint"]; found {
+			locked, ok = hint.Value().(bool)
+			return locked, ok
+		}
+		return false, false
+	}
+	// ActiveChanged of the screensaver interfaces
+	if len(signal.Body) == 1 {
+		locked, ok = signal.Body[0].(bool)
+	}
+	return locked, ok
+}
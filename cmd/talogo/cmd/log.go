@@ -1,199 +1,1597 @@
 package cmd
 
 import (
-	"encoding/csv"
+	"bufio"
+	"bytes"
 	"fmt"
 	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/artilugio0/talogo/pkg/talogo"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 )
 
 var (
 	logCmdLogFile string
+	logCmdForce   bool
+	logCmdTags    []string
+	logCmdNote    string
+	logCmdFromGit bool
+	logCmdRunning string
+	logCmdQuiet   bool
+	logCmdStrict  bool
+	logCmdSimilar bool
+	logCmdAt      string
+	logCmdAccess  bool
+	logCmdMouse   bool
+	logCmdRecent  int
+	logCmdReview  bool
+	logCmdFor     time.Duration
+	logCmdOverlap bool
+
+	logCmdPomodoro   bool
+	logCmdWork       time.Duration
+	logCmdShortBreak time.Duration
+	logCmdLongBreak  time.Duration
+	logCmdLongEvery  int
 )
 
 type model struct {
 	logFile   string
 	titles    []string
+	tags      []string
+	notes     string
 	startTime time.Time
 	elapsed   time.Duration
 	running   bool
 	quitting  bool
+	saved     bool // Whether the session was written to the log
+	discarded bool // Whether the session was shorter than min_session
+	takenOver bool // Whether another process took the session over
+	strict    bool // Whether sessions overlapping the log go to a pending file
+
+	remindEvery  time.Duration // Interval of the reminder notifications, 0 for none
+	nextReminder time.Duration // Elapsed time of the next reminder
+	countdown    time.Duration // Time tracked after which the session is over, 0 for none
+	expired      bool          // Whether the countdown is over
+	maxSession   time.Duration // Time tracked after which the timer stops by itself, 0 for none
+	promptMax    bool          // Pause and ask instead of stopping at maxSession
+	autoStopped  bool          // Whether the session ends where it reached maxSession
+
+	// Pomodoro mode alternates pomodoros of tracked time with breaks
+	// the timer is paused for
+	pomodoro  *pomodoroCycle // Lengths of the intervals, nil if not in pomodoro mode
+	pomodoros int            // Pomodoros completed today
+	workEnd   time.Duration  // Time tracked at which the current pomodoro ends
+	onBreak   bool           // Whether on a break after a pomodoro
+	breakEnd  time.Time      // When the current break ends
+	breaks    breakTasks     // Tasks the breaks are logged to
+
+	// Pauses split the session into spans of active time, which are
+	// logged as separate records
+	spans     []timeSpan    // Finished active spans
+	spanStart time.Time     // Start of the current active span
+	paused    bool          // Whether the timer is paused
+	pausedAt  time.Time     // Start of the current pause
+	pausedFor time.Duration // Total of the finished pauses
+
+	lockEvents   <-chan bool   // Screen lock changes, nil if not watched
+	promptUnlock bool          // Ask what to do when the screen is unlocked
+	idleAfter    time.Duration // Idle time after which the timer pauses, 0 to not check
+	away         string        // Why the timer paused by itself ("screen locked" or "idle")
+	asking       bool          // Waiting for what to do with the time away
+	reassigning  bool          // Typing the task the time away is logged to
+	switching    bool          // Typing the task to switch to
+	input        string        // Task typed while reassigning or switching
+	mouse        bool          // Show the actions as buttons to click
+	err          error         // Error of the last action, shown below the timer
+	width        int           // Terminal width, 0 until known
+	height       int           // Terminal height, 0 until known
+
+	// Stopping with review enabled shows the titles and notes to correct
+	// them before the session is written
+	review       bool   // Review the session when stopping
+	reviewing    bool   // Editing the titles and notes of the stopped session
+	reviewPaused bool   // Whether the timer was paused for the review
+	reviewField  int    // Field being edited, 0 for the task and 1 for the notes
+	reviewTask   string // Titles being edited, separated by /
+	reviewNotes  string // Notes being edited
+
+	recent     []Record // Sessions of the day logged before this one
+	showRecent int      // Number of recent sessions shown under the timer
+
+	accessible bool          // Print plain status lines instead of redrawing the view
+	nextStatus time.Duration // Elapsed time of the next status line in accessible mode
+	spoken     string        // Last notice printed in accessible mode
+
+	keys timerKeys // Keys of the timer actions
+
+	// Goals of the config, with the time logged before the timer started
+	dailyGoal    time.Duration
+	weeklyGoal   time.Duration
+	trackedToday time.Duration
+	trackedWeek  time.Duration
+
+	logged []Record // Records written to the log
+}
+
+// timeSpan is a span of active time of a session
+type timeSpan struct {
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Pomodoro int       `json:"pomodoro,omitempty"` // Number of the day of its pomodoro, 0 if none
 }
 
 type tickMsg time.Time
 
+// lockMsg reports that the screen was locked (true) or unlocked (false)
+type lockMsg bool
+
+// signalMsg reports a signal asking the process to end: an interrupt, a
+// kill, or the terminal (or Windows console) being closed
+type signalMsg struct {
+	signal os.Signal
+}
+
+// idleMsg reports for how long the keyboard and mouse have not been used
+type idleMsg struct {
+	idle time.Duration
+	err  error
+}
+
+// idleCheckInterval is how often the system idle time is checked
+const idleCheckInterval = 10 * time.Second
+
+// autoStoppedTag marks the last record of sessions stopped by themselves
+// at max_session, whose end is likely to need fixing
+const autoStoppedTag = "auto-stopped"
+
 var logCmd = &cobra.Command{
 	Use:   "log TITLE {SUBTITLES}",
 	Short: "Start tracking a task and log to file when finished",
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Start tracking a task and log to file when finished.
+
+Each argument is a title, the first the top level task. Aliases in the
+config file stand for their task path, and @NAME for the titles of the
+template NAME (see 'talogo template').
+
+Keys:
+  p        pause or resume
+  w        switch task: log the session so far and track another task,
+           typed with its titles separated by /
+  ctrl+c   stop and log the session
+
+The keybindings table of the config replaces these keys, e.g. pause =
+"space", though ctrl+c always stops.
+
+With --mouse, or mouse in the config, the timer takes the whole terminal
+and shows its actions as buttons to click.
+
+The last sessions logged today are listed under the timer, 5 by default;
+--recent sets how many, 0 hides them. With daily or weekly in the goals
+table of the config, e.g. daily = "8h", the timer shows the time logged
+today or this week next to them.
+
+With --review, or review in the config, stopping with ctrl+c shows the
+titles and notes of the session to correct them before it is written:
+tab moves between them, enter saves and esc goes back to the timer.
+Sessions stopped by a signal or by another command are written as they
+are.
+
+With --for, e.g. --for 25m, the timer counts down the time tracked. When
+it is over, a notification is sent and the on_expire hook of the config
+runs, while the session goes on until stopped.
+
+With --pomodoro the timer alternates pomodoros, 25m of tracked time by
+default, with breaks it is paused for: 5m long, or 15m every 4
+pomodoros. The pomodoro table of the config sets other lengths, which
+--work, --short-break, --long-break and --long-break-every override.
+Pressing p on a break skips the rest of it. Pomodoros are numbered from
+the first of the day, and their records are tagged pomodoro, plus
+pomodoro:N once pomodoro N is completed (see 'talogo stats --pomodoros').
+
+The breaks table of the config logs the pauses taken with p, and the
+pomodoro breaks, as sessions of a task of their own, e.g. break, when
+the timer resumes.
+
+With max_session in the config, e.g. "10h", a forgotten timer stops by
+itself once it tracked that long, saving the session up to then with its
+last record tagged auto-stopped. With on_max_session = "prompt" it pauses
+instead and asks what to do with the time since.
+
+With a threshold in the gaps table of the config, e.g. "30m", starting a
+session that long after the last one of the day ended asks whether to
+log the time in between, to one of the tasks of the table, e.g. lunch,
+or to another one, or to leave it untracked.
+
+With --allow-overlap the session is tracked alongside others on purpose,
+e.g. on-call underneath regular work: it runs next to the running
+sessions without asking, and its records are tagged parallel. Each
+session keeps its own state, so status shows them all and stopping one
+leaves the others running. It can't be combined with --running stop or
+take-over. Overlaps with parallel records are not warned about nor
+refused by --strict, and summary and report count the time they share
+with other records once in day totals, unless --count-overlaps is given.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		// Parallel sessions are meant to run next to others, which is
+		// safe as every process keeps its own session state
+		if logCmdOverlap {
+			if cmd.Flags().Changed("running") && logCmdRunning != "ask" && logCmdRunning != "both" {
+				fmt.Fprintf(os.Stderr, tr("Error: --allow-overlap runs next to the other sessions, it can't be used with --running %s\n"), logCmdRunning)
+				os.Exit(1)
+			}
+			logCmdRunning = "both"
+		}
+		takeOver, err := handleRunningSession(logCmdRunning)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+
+		// Checked here rather than with cobra.MinimumNArgs, so --from-git
+		// can also come from the environment or the config file
+		if logCmdFromGit && takeOver == nil {
+			repo, branch, err := gitTitles()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+				os.Exit(1)
+			}
+			args = append([]string{repo, branch}, args...)
+		}
+		if len(args) == 0 && takeOver == nil {
+			fmt.Fprintln(os.Stderr, tr("Error: requires at least 1 title, or --from-git"))
+			os.Exit(1)
+		}
+
+		for _, tag := range logCmdTags {
+			if strings.Contains(tag, talogo.TagSeparator) {
+				fmt.Fprintf(os.Stderr, tr("Invalid tag %q: tags cannot contain %q\n"), tag, talogo.TagSeparator)
+				os.Exit(1)
+			}
+		}
+
+		now := time.Now()
+		start := now
+		if logCmdAt != "" {
+			if takeOver != nil {
+				fmt.Fprintln(os.Stderr, tr("Error: --at cannot be combined with taking over a session"))
+				os.Exit(1)
+			}
+			if start, err = parseTime(logCmdAt, now); err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+				os.Exit(1)
+			}
+			if start.After(now) {
+				fmt.Fprintf(os.Stderr, tr("Error: --at %s is in the future\n"), start.Format(time.RFC3339))
+				os.Exit(1)
+			}
+		}
+
+		if !logCmdForce && takeOver == nil {
+			if err := checkVacation(start); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		local, err := loadLocalConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+
+		config, err := loadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		if onUnlock := config.Idle.OnUnlock; onUnlock != "" && onUnlock != "resume" && onUnlock != "prompt" {
+			fmt.Fprintf(os.Stderr, tr("Error: invalid on_unlock %q in config (expected resume or prompt)\n"), onUnlock)
+			os.Exit(1)
+		}
+		// Checked now rather than when the session is saved
+		if _, err := config.rounding(); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		if _, err := config.minSession(); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		maxSession, err := config.maxSession()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		if onMax := config.OnMaxSession; onMax != "" && onMax != "stop" && onMax != "prompt" {
+			fmt.Fprintf(os.Stderr, tr("Error: invalid on_max_session %q in config (expected stop or prompt)\n"), onMax)
+			os.Exit(1)
+		}
+		var pomodoro *pomodoroCycle
+		if logCmdPomodoro {
+			if logCmdFor > 0 {
+				fmt.Fprintln(os.Stderr, tr("Error: --for cannot be combined with --pomodoro"))
+				os.Exit(1)
+			}
+			if pomodoro, err = logPomodoroCycle(config); err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+				os.Exit(1)
+			}
+		}
+		breaks, err := configuredBreakTasks(config)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		var remindEvery time.Duration
+		if config.Notifications.RemindEvery != "" {
+			remindEvery, err = time.ParseDuration(config.Notifications.RemindEvery)
+			if err != nil || remindEvery <= 0 {
+				fmt.Fprintf(os.Stderr, tr("Error: invalid remind_every %q in config\n"), config.Notifications.RemindEvery)
+				os.Exit(1)
+			}
+		}
+		keys, err := config.timerKeys()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		dailyGoal, weeklyGoal, err := config.goals()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+
+		// Take all arguments as titles
+		titles, err := expandTitles(args)
+		if err == nil {
+			titles, err = checkTitles(local.withPrefix(titles))
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		if logCmdSimilar && takeOver == nil {
+			if titles, err = confirmSimilarTitles(logCmdLogFile, titles); err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error reading log: %v\n"), err)
+				os.Exit(1)
+			}
+		}
+		// Without a terminal to answer, gaps are left untracked. Parallel
+		// sessions don't fill them.
+		if !logCmdQuiet && takeOver == nil && !logCmdOverlap {
+			if err := checkGap(logCmdLogFile, start, logCmdStrict); err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+				os.Exit(1)
+			}
+		}
+
+		tags := logCmdTags
+		if logCmdOverlap {
+			tags = withTag(tags, parallelTag)
+		}
+
 		m := model{
-			logFile:   logCmdLogFile,
-			titles:    args, // Take all arguments as titles
-			startTime: time.Now(),
-			running:   true,
+			logFile:      logCmdLogFile,
+			titles:       titles,
+			tags:         tags,
+			notes:        logCmdNote,
+			startTime:    start,
+			spanStart:    start,
+			running:      true,
+			remindEvery:  remindEvery,
+			nextReminder: remindEvery,
+			countdown:    logCmdFor,
+			pomodoro:     pomodoro,
+			breaks:       breaks,
+			maxSession:   maxSession,
+			// Without keys to answer, the timer stops
+			promptMax:    config.OnMaxSession == "prompt" && !logCmdQuiet,
+			promptUnlock: config.Idle.OnUnlock == "prompt",
+			strict:       logCmdStrict && !logCmdOverlap,
+			accessible:   (logCmdAccess || config.Accessible) && !logCmdQuiet,
+			keys:         keys,
+			dailyGoal:    dailyGoal,
+			weeklyGoal:   weeklyGoal,
 		}
+		m.mouse = (logCmdMouse || config.Mouse) && !logCmdQuiet && !m.accessible
+		// Text typed in line mode can't be edited in place
+		m.review = (logCmdReview || config.Review) && !logCmdQuiet && !m.accessible
+		if m.showRecent = logCmdRecent; m.showRecent > 0 {
+			if m.recent, err = todaysSessions(m.logFile); err != nil {
+				fmt.Fprintf(os.Stderr, tr("Warning: not showing recent sessions: %v\n"), err)
+			}
+		}
+		if m.dailyGoal > 0 || m.weeklyGoal > 0 {
+			if m.trackedToday, m.trackedWeek, err = trackedSoFar(m.logFile); err != nil {
+				fmt.Fprintf(os.Stderr, tr("Warning: not showing goals: %v\n"), err)
+				m.dailyGoal, m.weeklyGoal = 0, 0
+			}
+		}
+		if takeOver != nil {
+			m = m.takeOver(*takeOver)
+		}
+		if m.pomodoro != nil {
+			m.workEnd = m.elapsed + m.pomodoro.Work
+			today, err := todaysSessions(m.logFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, tr("Warning: numbering pomodoros from 1: %v\n"), err)
+			}
+			m.pomodoros = lastPomodoro(today)
+		}
+		if config.Idle.Threshold != "" && !logCmdQuiet {
+			m.idleAfter, err = time.ParseDuration(config.Idle.Threshold)
+			if err != nil || m.idleAfter <= 0 {
+				fmt.Fprintf(os.Stderr, tr("Error: invalid idle threshold %q in config\n"), config.Idle.Threshold)
+				os.Exit(1)
+			}
+			if _, err := systemIdleTime(); err != nil {
+				fmt.Fprintf(os.Stderr, tr("Warning: not detecting idle time: %v\n"), err)
+				m.idleAfter = 0
+			}
+		}
+		if config.Idle.PauseOnLock {
+			if m.lockEvents, err = watchScreenLock(); err != nil {
+				fmt.Fprintf(os.Stderr, tr("Warning: not pausing on screen lock: %v\n"), err)
+			}
+		}
+		if takeOver == nil {
+			sendWebhooks(EventStart, m.record())
+			runHook(EventStart, m.record())
+		}
+		m.writeState()
 
-		// Create program without AltScreen
-		p := tea.NewProgram(m)
-		if _, err := p.Run(); err != nil {
-			fmt.Printf("Error: %v\n", err)
+		// Create program without AltScreen. Signals are handled here so
+		// the session is saved before exiting.
+		options := []tea.ProgramOption{tea.WithoutSignalHandler()}
+		if logCmdQuiet {
+			// No keys to answer prompts with, the screen is unlocked
+			// by resuming
+			m.promptUnlock = false
+			options = append(options, tea.WithoutRenderer(), tea.WithInput(nil))
+		} else if m.accessible {
+			// The terminal stays in line mode, keys are sent with Enter
+			m.nextStatus = m.elapsed + accessibleStatusEvery
+			options = append(options, tea.WithoutRenderer())
+		} else if m.mouse {
+			// Clicks are mapped to the lines of the view, which needs
+			// the view at the top of the screen
+			options = append(options, tea.WithAltScreen(), tea.WithMouseCellMotion())
+		}
+		p := tea.NewProgram(m, options...)
+		stopSignals := forwardSignals(p)
+		final, err := p.Run()
+		stopSignals()
+		clearSessionState()
+		if err != nil {
+			fmt.Printf(tr("Error: %v\n"), err)
 			os.Exit(1)
 		}
+		if m, ok := final.(model); ok {
+			if logCmdQuiet {
+				fmt.Print(m.quietResult())
+			} else if m.accessible || m.mouse {
+				// The view was not left on the terminal
+				fmt.Print(m.View())
+			}
+			if m.saved {
+				sendWebhooks(EventStop, m.record())
+				runHook(EventStop, m.record())
+			} else if m.discarded {
+				sendWebhooks(EventCancel, m.record())
+			}
+			if !m.strict && !logCmdOverlap {
+				warnOverlaps(m.logFile, m.logged)
+			}
+			appendToDailyNotes(m.logged)
+		}
 	},
 }
 
 func init() {
-	logCmd.Flags().StringVarP(&logCmdLogFile, "file", "f", "./talogo.csv", "Log file to write")
+	logCmd.Flags().StringVarP(&logCmdLogFile, "file", "f", defaultLogFile(), "Log file to write")
+	logCmd.Flags().StringArrayVarP(&logCmdTags, "tag", "t", nil, "Tag to attach to the session (can be repeated)")
+	logCmd.Flags().StringVarP(&logCmdNote, "note", "n", "", "Note to attach to the session")
+	logCmd.Flags().BoolVar(&logCmdFromGit, "from-git", false, "Use the repository name and current branch of the working directory as the first titles")
+	logCmd.Flags().BoolVar(&logCmdForce, "force", false, "Start tracking even if today is marked as vacation")
+	logCmd.Flags().BoolVarP(&logCmdQuiet, "quiet", "q", false, "Track without the interactive view, stopping on SIGINT or SIGTERM and printing a single line")
+	logCmd.Flags().BoolVar(&logCmdAccess, "accessible", false, "Print plain status lines instead of redrawing the timer, for screen readers (keys are typed followed by Enter)")
+	logCmd.Flags().BoolVar(&logCmdMouse, "mouse", false, "Show the timer actions as buttons to click, taking the whole terminal")
+	logCmd.Flags().IntVar(&logCmdRecent, "recent", 5, "Number of sessions of the day shown under the timer, 0 to hide them")
+	logCmd.Flags().DurationVar(&logCmdFor, "for", 0, "Count down from this time tracked (e.g. 25m), alerting and running the on_expire hook when it is over")
+	logCmd.Flags().BoolVar(&logCmdPomodoro, "pomodoro", false, "Alternate pomodoros of tracked time with breaks, as set in the pomodoro table of the config")
+	logCmd.Flags().DurationVar(&logCmdWork, "work", 0, "Length of the pomodoros, instead of the configured one (default 25m)")
+	logCmd.Flags().DurationVar(&logCmdShortBreak, "short-break", 0, "Length of the short pomodoro breaks, instead of the configured one (default 5m)")
+	logCmd.Flags().DurationVar(&logCmdLongBreak, "long-break", 0, "Length of the long pomodoro breaks, instead of the configured one (default 15m)")
+	logCmd.Flags().IntVar(&logCmdLongEvery, "long-break-every", 0, "Pomodoros before each long break, instead of the configured number (default 4)")
+	logCmd.Flags().BoolVar(&logCmdReview, "review", false, "Review the titles and notes of the session when stopping, before it is written")
+	logCmd.Flags().StringVar(&logCmdAt, "at", "", "Start the session in the past, e.g. 9:30, yesterday 14:00 or 20m ago")
+	logCmd.Flags().BoolVar(&logCmdSimilar, "check-similar", false, "Ask whether to use an existing task instead of titles that look like a typo or variant of it")
+	logCmd.Flags().BoolVar(&logCmdOverlap, "allow-overlap", false, "Track the session alongside others, tagged parallel, e.g. on-call underneath regular work")
+	logCmd.Flags().BoolVar(&logCmdStrict, "strict", false, "Refuse to write sessions overlapping records of the log, saving them as pending instead of warning")
+	logCmd.Flags().StringVar(&logCmdRunning, "running", "ask", "What to do if other sessions are running: ask, stop them, take-over (continue the latest here, ignoring the titles) or run both")
 	rootCmd.AddCommand(logCmd)
 }
 
+// gitTitles returns the name of the git repository of the working
+// directory and its current branch, or the short commit hash if HEAD is
+// detached
+func gitTitles() (string, string, error) {
+	top, err := gitOutput("rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", "", fmt.Errorf("not in a git repository: %v", err)
+	}
+	branch, err := gitOutput("rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read the current branch: %v", err)
+	}
+	if branch == "HEAD" {
+		if branch, err = gitOutput("rev-parse", "--short", "HEAD"); err != nil {
+			return "", "", fmt.Errorf("failed to read the current commit: %v", err)
+		}
+	}
+	return filepath.Base(top), branch, nil
+}
+
+// gitOutput runs git with args and returns its trimmed output
+func gitOutput(args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s", msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// handleRunningSession deals with the sessions already running in other
+// processes as action says: ask, stop them, take the latest over or run
+// both. It returns the session to take over, if any.
+func handleRunningSession(action string) (*sessionState, error) {
+	if action != "ask" && action != "stop" && action != "take-over" && action != "both" {
+		return nil, fmt.Errorf("invalid --running value %q (expected ask, stop, take-over or both)", action)
+	}
+	var others []sessionState
+	for _, state := range readSessionStates() {
+		if state.PID != os.Getpid() {
+			others = append(others, state)
+		}
+	}
+	if len(others) == 0 {
+		return nil, nil
+	}
+	state := others[len(others)-1]
+
+	if action == "ask" {
+		if len(others) == 1 {
+			fmt.Printf(tr("A session is already running: %s since %s (process %d)\n"),
+				strings.Join(state.Titles, " / "), state.Start.Format(clockLayout), state.PID)
+			fmt.Print(tr("[s]top it, [t]ake it over, run [b]oth or [c]ancel? "))
+		} else {
+			fmt.Printf(tr("%d sessions are already running:\n"), len(others))
+			for _, other := range others {
+				fmt.Printf(tr("  %s since %s (process %d)\n"),
+					strings.Join(other.Titles, " / "), other.Start.Format(clockLayout), other.PID)
+			}
+			fmt.Print(tr("[s]top them, [t]ake the latest over, run [b]oth or [c]ancel? "))
+		}
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "s", "stop":
+			action = "stop"
+		case "t", "take-over":
+			action = "take-over"
+		case "b", "both":
+			action = "both"
+		default:
+			return nil, fmt.Errorf("another session is running (use --running to choose without asking)")
+		}
+	}
+
+	switch action {
+	case "stop":
+		for _, other := range others {
+			if err := requestSessionAction(other, controlStop); err != nil {
+				return nil, err
+			}
+			fmt.Printf(tr("Stopped %s\n"), strings.Join(other.Titles, " / "))
+		}
+	case "take-over":
+		if err := requestSessionAction(state, controlTakeOver); err != nil {
+			return nil, err
+		}
+		return &state, nil
+	}
+	return nil, nil
+}
+
+// checkVacation asks for confirmation when t falls in a configured vacation.
+// It returns an error if tracking should not start.
+func checkVacation(t time.Time) error {
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	vacation, err := config.isVacation(t)
+	if err != nil || !vacation {
+		return err
+	}
+
+	fmt.Printf(tr("%s is marked as vacation. Start tracking anyway? [y/N] "), t.Format("2006-01-02"))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer != "y" && answer != "yes" {
+		return fmt.Errorf("not tracking on a vacation day (use --force to skip this check)")
+	}
+	return nil
+}
+
 func (m model) Init() tea.Cmd {
-	return tickCmd()
+	cmds := []tea.Cmd{tickCmd()}
+	if m.accessible {
+		cmds = append(cmds, printCmd(m.statusLine()+"\n"+fmt.Sprintf(tr("Type %s and Enter to pause or resume, %s to stop."), keyName(m.keys.pause), keyName(m.keys.stop))+"\n"))
+	}
+	if m.lockEvents != nil {
+		cmds = append(cmds, waitForLock(m.lockEvents))
+	}
+	if m.idleAfter > 0 {
+		cmds = append(cmds, idleCheckCmd())
+	}
+	return tea.Batch(cmds...)
 }
 
-func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if msg.Type == tea.KeyCtrlC {
-			m.running = false
-			m.quitting = true
-			// Save to CSV immediately on Ctrl+C
-			if err := m.logToCSV(); err != nil {
-				fmt.Printf("Error writing to CSV: %v\n", err)
+			return m.requestStop()
+		}
+		if m.reviewing {
+			return m.typeReview(msg)
+		}
+		if m.reassigning || m.switching {
+			return m.typeTask(msg)
+		}
+		if m.asking {
+			switch msg.String() {
+			case "d":
+				m = m.resume(time.Now())
+			case "k":
+				m = m.keepPause()
+			case "a":
+				m.reassigning = true
+				return m, nil
+			case "s":
+				return m.requestStop()
+			default:
+				return m, nil
+			}
+			// The session goes on past the limit
+			m.autoStopped = false
+			m.asking = false
+			m.writeState()
+			return m, nil
+		}
+		if msg.String() == m.keys.stop {
+			return m.requestStop()
+		}
+		if msg.String() == m.keys.switchTask {
+			m.switching = true
+			return m, nil
+		}
+		if msg.String() == m.keys.pause && m.onBreak {
+			m = m.endBreak(time.Now())
+			return m, nil
+		}
+		if msg.String() == m.keys.pause {
+			var cmd tea.Cmd
+			if m.paused {
+				now := time.Now()
+				if m.away == "" {
+					m = m.logBreak(m.breaks.pause, now)
+				}
+				m = m.resume(now)
+			} else {
+				m = m.pause(time.Now())
+				cmd = hookCmd(EventPause, m.record())
+			}
+			m.away = ""
+			m.writeState()
+			return m, cmd
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case tea.MouseMsg:
+		if action, ok := m.clickedAction(msg); ok {
+			return m.update(action.key)
+		}
+	case signalMsg:
+		return m.stop()
+	case hookMsg:
+		if msg.err != nil {
+			m.err = msg.err
+		}
+	case lockMsg:
+		locked := bool(msg)
+		if locked && !m.paused {
+			m = m.pause(time.Now())
+			m.away = "screen locked"
+			m.writeState()
+			return m, tea.Batch(waitForLock(m.lockEvents), hookCmd(EventPause, m.record()))
+		} else if !locked && m.away == "screen locked" && !m.asking {
+			if m.promptUnlock {
+				m.asking = true
+			} else {
+				m = m.resume(time.Now())
+				m.writeState()
+			}
+		}
+		return m, waitForLock(m.lockEvents)
+	case idleMsg:
+		if msg.err != nil {
+			return m, idleCheckCmd()
+		}
+		if msg.idle >= m.idleAfter && !m.paused {
+			// The user left when the input stopped, not when the
+			// threshold was reached
+			left := time.Now().Add(-msg.idle)
+			if left.Before(m.spanStart) {
+				left = m.spanStart
 			}
-			return m, tea.Quit
+			m = m.pause(left)
+			m.away = "idle"
+			m.writeState()
+			return m, tea.Batch(idleCheckCmd(), hookCmd(EventPause, m.record()))
+		} else if msg.idle < m.idleAfter && m.away == "idle" && !m.asking {
+			m.asking = true
+			return m, tea.Batch(idleCheckCmd(), notifyCmd("talogo",
+				fmt.Sprintf(tr("You were idle for %s. Keep, discard or reassign that time in talogo."), time.Since(m.pausedAt).Round(time.Minute))))
 		}
+		return m, idleCheckCmd()
 	case tickMsg:
+		if action, ok := readSessionControl(); ok {
+			switch action {
+			case controlStop:
+				return m.stop()
+			case controlTakeOver:
+				m.running = false
+				m.takenOver = true
+				return m, tea.Quit
+			}
+		}
 		if m.running {
-			m.elapsed = time.Since(m.startTime)
+			if m.pomodoro != nil {
+				if !m.paused {
+					m.elapsed = m.activeTime(time.Now())
+				}
+				var cmd tea.Cmd
+				if m, cmd = m.pomodoroTick(time.Now()); cmd != nil {
+					return m, tea.Batch(tickCmd(), cmd)
+				}
+			}
+			if m.paused {
+				return m, tickCmd()
+			}
+			m.elapsed = m.activeTime(time.Now())
+			if m.maxSession > 0 && m.elapsed >= m.maxSession {
+				return m.reachMaxSession(time.Now())
+			}
+			if m.countdown > 0 && !m.expired && m.elapsed >= m.countdown {
+				m.expired = true
+				return m, tea.Batch(tickCmd(), hookCmd(EventExpire, m.record()), notifyCmd("talogo",
+					fmt.Sprintf(tr("Time is up: %s tracked on %s"), m.countdown, strings.Join(m.titles, " / "))))
+			}
+			if m.remindEvery > 0 && m.elapsed >= m.nextReminder {
+				m.nextReminder += m.remindEvery
+				return m, tea.Batch(tickCmd(), notifyCmd("talogo",
+					fmt.Sprintf(tr("Still tracking %s after %s?"), strings.Join(m.titles, " / "), m.elapsed.Round(time.Minute))))
+			}
 			return m, tickCmd()
 		}
 	}
 	return m, nil
 }
 
+// quietResult is the single line printed when a session tracked with
+// --quiet ends
+func (m model) quietResult() string {
+	switch {
+	case m.takenOver:
+		return tr("Session taken over by another terminal\n")
+	case m.discarded:
+		return fmt.Sprintf(tr("Discarded %s (%s), shorter than min_session\n"), strings.Join(m.titles, " / "), m.elapsed.Round(time.Second))
+	case !m.saved:
+		return fmt.Sprintf(tr("Error writing to %s: %v\n"), m.logFile, m.err)
+	}
+	return fmt.Sprintf(tr("Logged %s (%s) to %s\n"), strings.Join(m.titles, " / "), m.elapsed.Round(time.Second), m.logFile)
+}
+
 func (m model) View() string {
+	if m.takenOver {
+		return tr("Session taken over by another terminal\n")
+	}
 	if m.quitting {
-		return "Timer stopped. Data saved to " + m.logFile + "\n"
+		if m.discarded {
+			return tr("Timer stopped. Session shorter than min_session, not saved\n")
+		}
+		if !m.saved {
+			return fmt.Sprintf(tr("Timer stopped. Error writing to %s: %v\n"), m.logFile, m.err)
+		}
+		if m.autoStopped {
+			return fmt.Sprintf(tr("Timer stopped at the maximum session length, after %s. Data saved to %s, the last record tagged %s\n"),
+				m.elapsed.Round(time.Second), m.logFile, autoStoppedTag)
+		}
+		return tr("Timer stopped. Data saved to ") + m.logFile + "\n"
 	}
 	if !m.running {
-		return "Timer stopped.\n"
+		return tr("Timer stopped.\n")
+	}
+	view := m.timerView()
+	if m.mouse {
+		view += "\n" + m.actionBar() + "\n"
+	}
+	return view
+}
+
+// timerView returns the titles, the timer, the notice below it and the
+// recent sessions of a running session, fitted to the terminal: long
+// lines are cut or wrapped and, when it is too short, fewer recent
+// sessions are shown and the titles take a single line, so the timer
+// stays in sight
+func (m model) timerView() string {
+	if m.height <= 0 {
+		return m.layoutTimer(false, m.showRecent)
 	}
+	// The action bar and the blank line before it
+	reserved := 0
+	if m.mouse {
+		reserved = 2
+	}
+	for recent := m.showRecent; recent >= 0; recent-- {
+		if view := m.layoutTimer(false, recent); strings.Count(view, "\n")+reserved < m.height {
+			return view
+		}
+	}
+	return m.layoutTimer(true, 0)
+}
+
+// layoutTimer returns the timer view with up to recent sessions of the
+// day and, if compact, the titles as a single path
+func (m model) layoutTimer(compact bool, recent int) string {
 	hours := int(m.elapsed.Hours())
 	minutes := int(m.elapsed.Minutes()) % 60
 	seconds := int(m.elapsed.Seconds()) % 60
 
 	// Build title display with hierarchical numbering
 	var titleLines []string
-	for i, title := range m.titles {
-		titleLines = append(titleLines, fmt.Sprintf("Title %d: %s", i+1, title))
+	if compact {
+		titleLines = append(titleLines, strings.Join(m.titles, " / "))
+	} else {
+		for i, title := range m.titles {
+			titleLines = append(titleLines, fmt.Sprintf(tr("Title %d: %s"), i+1, title))
+		}
 	}
-	return fmt.Sprintf("%s\nTimer: %02d:%02d:%02d\n", strings.Join(titleLines, "\n"), hours, minutes, seconds)
+	if len(m.tags) > 0 {
+		titleLines = append(titleLines, tr("Tags: ")+strings.Join(m.tags, ", "))
+	}
+	var view string
+	for _, line := range titleLines {
+		view += cutLine(line, m.width) + "\n"
+	}
+	view += fmt.Sprintf(tr("Timer: %02d:%02d:%02d"), hours, minutes, seconds) + "\n"
+	if m.countdown > 0 && !m.expired {
+		view += fmt.Sprintf(tr("Remaining: %s"), stopwatchTime(m.countdown-m.elapsed)) + "\n"
+	}
+	view += m.pomodoroView()
+	view += m.goalsView()
+	return view + wrapText(m.notice(), m.width) + m.recentView(recent)
 }
 
-func tickCmd() tea.Cmd {
-	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
-		return tickMsg(t)
+// goalsView returns the progress towards the daily and weekly goals, if
+// any, counting the time of this timer
+func (m model) goalsView() string {
+	tracked := m.elapsed
+	for _, record := range m.logged {
+		tracked += record.Duration()
+	}
+	var view string
+	if m.dailyGoal > 0 {
+		view += fmt.Sprintf(tr("Today: %s of %s"), formatClock(m.trackedToday+tracked), formatClock(m.dailyGoal)) + "\n"
+	}
+	if m.weeklyGoal > 0 {
+		view += fmt.Sprintf(tr("This week: %s of %s"), formatClock(m.trackedWeek+tracked), formatClock(m.weeklyGoal)) + "\n"
+	}
+	return view
+}
+
+// recentView returns the last count sessions of the day, including those
+// logged by this timer, one per line with their times and duration
+func (m model) recentView(count int) string {
+	sessions := append(append([]Record{}, m.recent...), m.logged...)
+	if count <= 0 || len(sessions) == 0 {
+		return ""
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Start.Before(sessions[j].Start)
 	})
+	sessions = sessions[max(len(sessions)-count, 0):]
+
+	view := "\n" + cutLine(tr("Earlier today:"), m.width) + "\n"
+	for _, session := range sessions {
+		line := fmt.Sprintf("  %s-%s  %5s  %s",
+			session.Start.Local().Format(clockLayout),
+			session.End.Local().Format(clockLayout),
+			formatClock(session.Duration()),
+			strings.Join(sanitizedTitles(session.Titles), " / "),
+		)
+		view += cutLine(line, m.width) + "\n"
+	}
+	return view
 }
 
-func (m model) logToCSV() error {
-	startTime := m.startTime
-	endTime := m.startTime.Add(m.elapsed)
+// todaysSessions returns the sessions of the log file started today, with
+// the configured day start, oldest first. A log not created yet has none.
+func todaysSessions(logFile string) ([]Record, error) {
+	if _, err := os.Stat(logPath(logFile)); os.IsNotExist(err) {
+		return nil, nil
+	}
+	dayStart, err := configuredDayStart()
+	if err != nil {
+		return nil, err
+	}
+	today := talogo.DayOf(time.Now(), dayStart)
+	records, err := queryDays(logFile, today, today, dayStart)
+	if err != nil {
+		return nil, err
+	}
+	var sessions []Record
+	for _, record := range records {
+		if talogo.DayOf(record.Start, dayStart) == today {
+			sessions = append(sessions, record)
+		}
+	}
+	return sessions, nil
+}
 
-	// Ensure file is created with proper permissions
-	file, err := os.OpenFile(m.logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+// trackedSoFar returns the time logged today and this week, with the
+// configured day and week start. A log not created yet has none.
+func trackedSoFar(logFile string) (today, week time.Duration, err error) {
+	if _, err := os.Stat(logPath(logFile)); os.IsNotExist(err) {
+		return 0, 0, nil
+	}
+	config, err := loadConfig()
+	if err != nil {
+		return 0, 0, err
+	}
+	dayStart, err := config.dayStart()
+	if err != nil {
+		return 0, 0, err
+	}
+	weekStart, err := config.weekStart()
 	if err != nil {
-		return fmt.Errorf("failed to open/create CSV file: %v", err)
+		return 0, 0, err
 	}
-	defer file.Close()
+	date := talogo.DayOf(time.Now(), dayStart)
+	day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return 0, 0, err
+	}
+	first := talogo.WeekStartOf(day, weekStart).Format("2006-01-02")
+	records, err := queryDays(logFile, first, date, dayStart)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, record := range records {
+		switch recordDate := talogo.DayOf(record.Start, dayStart); {
+		case recordDate == date:
+			today += record.Duration()
+			week += record.Duration()
+		case recordDate >= first && recordDate < date:
+			week += record.Duration()
+		}
+	}
+	return today, week, nil
+}
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+// notice returns the lines shown below the timer: prompts, the pause and
+// the last error
+func (m model) notice() string {
+	var notice string
+	switch {
+	case m.reviewing:
+		cursors := []string{"  ", "  "}
+		cursors[m.reviewField] = "> "
+		notice = tr("Review the session before saving (Tab to change field, Enter to save, Esc to go back):") + "\n" +
+			cursors[0] + tr("Task (titles separated by /): ") + m.reviewTask + "\n" +
+			cursors[1] + tr("Notes: ") + m.reviewNotes + "\n"
+	case m.reassigning:
+		notice = tr("Log the time away to task (titles separated by /, Esc to go back): ") + m.input + "\n"
+	case m.switching:
+		notice = tr("Switch to task (titles separated by /, Esc to go back): ") + m.input + "\n"
+	case m.asking:
+		away := time.Since(m.pausedAt).Round(time.Minute)
+		notice = fmt.Sprintf(tr("Away for %s (%s): [k] keep it, [d] discard it, [a] assign it to another task, [s] stop\n"), away, tr(m.away))
+	case m.onBreak:
+		notice = m.breakNotice()
+	case m.away != "":
+		notice = fmt.Sprintf(tr("Paused (%s since %s)\n"), tr(m.away), m.pausedAt.Format(clockLayout))
+	case m.paused:
+		notice = fmt.Sprintf(tr("Paused, press %s to resume\n"), keyName(m.keys.pause))
+	}
+	if m.expired {
+		notice += fmt.Sprintf(tr("Time is up (%s), press %s to stop\n"), m.countdown, keyName(m.keys.stop))
+	}
+	if m.err != nil {
+		notice += fmt.Sprintf(tr("Error: %v\n"), m.err)
+	}
+	return notice
+}
 
-	// Check if file is empty to add header
-	fileInfo, err := file.Stat()
-	if err != nil {
-		return fmt.Errorf("failed to stat file: %v", err)
+// typeTask handles the keys typed while entering the task the time away
+// is logged to, or the task to switch to
+func (m model) typeTask(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		titles := strings.Split(strings.Trim(strings.TrimSpace(m.input), "/"), "/")
+		if titles[0] == "" {
+			return m, nil
+		}
+		titles, err := expandTitles(titles)
+		if err == nil {
+			titles, err = checkTitles(titles)
+		}
+		if m.err = err; err != nil {
+			return m, nil
+		}
+		if m.switching {
+			return m.switchTask(titles)
+		}
+		now := time.Now()
+		records, err := m.logAway(titles, now)
+		if m.err = err; err != nil {
+			return m, nil
+		}
+		m.logged = append(m.logged, records...)
+		m = m.resume(now)
+		m.autoStopped = false
+		m.asking = false
+		m.reassigning = false
+		m.input = ""
+		m.writeState()
+	case tea.KeyEsc:
+		m.reassigning = false
+		m.switching = false
+		m.input = ""
+	case tea.KeyBackspace:
+		if runes := []rune(m.input); len(runes) > 0 {
+			m.input = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.input += string(msg.Runes)
 	}
+	return m, nil
+}
 
-	// Read existing CSV to determine max number of titles
-	maxTitles := len(m.titles)
-	if fileInfo.Size() > 0 {
-		// Open file for reading to check existing headers
-		readFile, err := os.Open(m.logFile)
-		if err != nil {
-			return fmt.Errorf("failed to read CSV file: %v", err)
+// switchTask writes the session so far to the log and starts tracking
+// titles, with the same tags, from now on
+func (m model) switchTask(titles []string) (model, tea.Cmd) {
+	now := time.Now()
+	if !m.paused {
+		m = m.pause(now)
+	}
+	m.elapsed = m.activeTime(m.pausedAt)
+	write, tag, err := checkMinSession(m.elapsed)
+	if m.err = err; err != nil {
+		return m.resume(now), nil
+	}
+	// The pomodoro goes on with the new task, which ends a break
+	workLeft := m.workEnd - m.elapsed
+	if m.onBreak && m.pomodoro != nil {
+		workLeft = m.pomodoro.Work
+	}
+
+	var cmds []tea.Cmd
+	if write {
+		records, err := m.logToCSV(tag)
+		if m.err = err; err != nil {
+			return m.resume(now), nil
 		}
-		defer readFile.Close()
+		m.logged = append(m.logged, records...)
+		cmds = append(cmds, webhookCmd(EventStop, m.record()), hookCmd(EventStop, m.record()))
+	} else {
+		cmds = append(cmds, webhookCmd(EventCancel, m.record()))
+	}
 
-		reader := csv.NewReader(readFile)
-		headers, err := reader.Read()
-		if err != nil {
-			return fmt.Errorf("failed to read CSV headers: %v", err)
+	m.titles = titles
+	m.notes = ""
+	m.startTime = now
+	m.spanStart = now
+	m.spans = nil
+	m.paused = false
+	m.pausedFor = 0
+	m.elapsed = 0
+	m.away = ""
+	m.nextReminder = m.remindEvery
+	m.workEnd = workLeft
+	m.onBreak = false
+	m.switching = false
+	m.input = ""
+	m.writeState()
+	cmds = append(cmds, webhookCmd(EventStart, m.record()), hookCmd(EventStart, m.record()))
+	return m, tea.Batch(cmds...)
+}
+
+// timerAction is an action of the timer, shown as a button when using the
+// mouse
+type timerAction struct {
+	key   tea.KeyMsg // Key doing the action
+	label string
+}
+
+// actions returns the actions available in the current state of the timer
+func (m model) actions() []timerAction {
+	runes := func(key string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+	switch {
+	case m.reviewing:
+		return []timerAction{
+			{tea.KeyMsg{Type: tea.KeyEnter}, tr("save")},
+			{tea.KeyMsg{Type: tea.KeyEsc}, tr("back")},
 		}
-		// Count title columns (headers after end_time)
-		titleCount := len(headers) - 2 // start_time, end_time
-		if titleCount > maxTitles {
-			maxTitles = titleCount
+	case m.reassigning || m.switching:
+		return []timerAction{{tea.KeyMsg{Type: tea.KeyEsc}, tr("cancel")}}
+	case m.asking:
+		return []timerAction{
+			{runes("k"), tr("keep")},
+			{runes("d"), tr("discard")},
+			{runes("a"), tr("assign")},
+			{runes("s"), tr("stop")},
 		}
 	}
+	pause := timerAction{runes(m.keys.pause), tr("pause")}
+	if m.paused {
+		pause.label = tr("resume")
+	}
+	stop := timerAction{tea.KeyMsg{Type: tea.KeyCtrlC}, tr("stop")}
+	if m.keys.stop != defaultTimerKeys.stop {
+		stop.key = runes(m.keys.stop)
+	}
+	return []timerAction{
+		pause,
+		{runes(m.keys.switchTask), tr("switch task")},
+		stop,
+	}
+}
+
+// timerKeys are the keys of the timer actions, as given by the String
+// method of tea.KeyMsg
+type timerKeys struct {
+	pause      string
+	switchTask string
+	stop       string // Besides ctrl+c, which always stops
+}
+
+// defaultTimerKeys are the keys of the timer unless the keybindings of
+// the config replace them
+var defaultTimerKeys = timerKeys{pause: "p", switchTask: "w", stop: "ctrl+c"}
 
-	// Write header if file is empty
-	if fileInfo.Size() == 0 {
-		header := []string{"start_time", "end_time"}
-		for i := 1; i <= maxTitles; i++ {
-			header = append(header, fmt.Sprintf("title%d", i))
+// keyName returns the name of key shown to the user, e.g. space for " "
+func keyName(key string) string {
+	if key == " " {
+		return "space"
+	}
+	return key
+}
+
+// button returns the action as a button, e.g. "[ pause (p) ]", or only
+// with its key if short, e.g. "[ p ]"
+func (a timerAction) button(short bool) string {
+	if short {
+		return "[ " + keyName(a.key.String()) + " ]"
+	}
+	return "[ " + a.label + " (" + keyName(a.key.String()) + ") ]"
+}
+
+// buttons returns the buttons of the actions, only with their keys when
+// the labels don't fit the terminal width
+func (m model) buttons() []string {
+	for _, short := range []bool{false, true} {
+		var buttons []string
+		width := 0
+		for _, action := range m.actions() {
+			buttons = append(buttons, action.button(short))
+			width += len([]rune(action.button(short))) + 2
 		}
-		if err := writer.Write(header); err != nil {
-			return fmt.Errorf("failed to write CSV header: %v", err)
+		if m.width <= 0 || width-2 <= m.width || short {
+			return buttons
 		}
 	}
+	return nil
+}
+
+// actionBar returns the actions as a line of buttons
+func (m model) actionBar() string {
+	return strings.Join(m.buttons(), "  ")
+}
+
+// clickedAction returns the action whose button was clicked in msg, if
+// any
+func (m model) clickedAction(msg tea.MouseMsg) (timerAction, bool) {
+	if !m.mouse || msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return timerAction{}, false
+	}
+	// The bar follows the timer and a blank line
+	if msg.Y != strings.Count(m.timerView(), "\n")+1 {
+		return timerAction{}, false
+	}
+	x := 0
+	buttons := m.buttons()
+	for i, action := range m.actions() {
+		width := len([]rune(buttons[i]))
+		if msg.X >= x && msg.X < x+width {
+			return action, true
+		}
+		x += width + 2
+	}
+	return timerAction{}, false
+}
 
-	// Split into daily records if spanning multiple days
-	currentStart := startTime
-	for {
-		year, month, day := currentStart.Date()
-		nextDay := time.Date(year, month, day+1, 0, 0, 0, 0, currentStart.Location())
-		endOfDay := nextDay.Add(-time.Nanosecond)
+// logAway writes the time since the timer paused as a session of the
+// task titles, and returns the records written
+func (m model) logAway(titles []string, now time.Time) ([]Record, error) {
+	dayStart, err := configuredDayStart()
+	if err != nil {
+		return nil, err
+	}
+	record := withOrigin(Record{
+		Start:   m.pausedAt,
+		End:     now,
+		Titles:  titles,
+		Source:  talogo.SourceInteractive,
+		Elapsed: now.Sub(m.pausedAt),
+	})
+	return m.save(talogo.SplitByDay(record, dayStart))
+}
 
-		currentEnd := endOfDay
-		if endOfDay.After(endTime) {
-			currentEnd = endTime
+// save writes records of the session to the log, rounded as configured,
+// and returns the records written. With --strict, records overlapping
+// others of the log are saved as pending instead, to be replayed once the
+// log is fixed.
+func (m model) save(records []Record) ([]Record, error) {
+	records, err := roundSession(records)
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+	if m.strict {
+		overlaps, err := findOverlapsWith(m.logFile, records)
+		if err != nil {
+			return nil, savePending(m.logFile, records, fmt.Errorf("failed to check for overlapping records: %v", err), "once the log is readable")
 		}
+		if len(overlaps) > 0 {
+			return nil, savePending(m.logFile, records, overlapError(overlaps), "once the log is fixed")
+		}
+	}
+	return records, appendOrSavePending(m.logFile, records)
+}
+
+// requestStop stops the timer as asked with the keyboard: with review
+// enabled, it pauses the timer to edit the titles and notes first, and
+// stops for good if asked again while reviewing
+func (m model) requestStop() (tea.Model, tea.Cmd) {
+	if !m.review || m.reviewing {
+		return m.stop()
+	}
+	m.reviewing = true
+	m.reviewPaused = !m.paused
+	if !m.paused {
+		m = m.pause(time.Now())
+	}
+	m.err = nil
+	m.reviewField = 0
+	m.reviewTask = strings.Join(m.titles, "/")
+	m.reviewNotes = m.notes
+	return m, nil
+}
 
-		// Create record
-		record := []string{
-			currentStart.Format(time.RFC3339),
-			currentEnd.Format(time.RFC3339),
+// typeReview handles the keys typed while reviewing the session before
+// stopping
+func (m model) typeReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	field := &m.reviewTask
+	if m.reviewField == 1 {
+		field = &m.reviewNotes
+	}
+	switch msg.Type {
+	case tea.KeyEnter:
+		titles := strings.Split(strings.Trim(strings.TrimSpace(m.reviewTask), "/"), "/")
+		if titles[0] == "" {
+			m.err = fmt.Errorf("the task needs at least one title")
+			return m, nil
+		}
+		titles, err := expandTitles(titles)
+		if err == nil {
+			titles, err = checkTitles(titles)
+		}
+		if m.err = err; err != nil {
+			return m, nil
 		}
-		// Add titles, padding with empty strings if fewer than maxTitles
-		record = append(record, m.titles...)
-		for len(record) < 2+maxTitles {
-			record = append(record, "")
+		m.titles = titles
+		m.notes = strings.TrimSpace(m.reviewNotes)
+		return m.stop()
+	case tea.KeyEsc:
+		m.reviewing = false
+		m.err = nil
+		if m.reviewPaused {
+			// Going back to work, the review is not a pause
+			m = m.keepPause()
 		}
+	case tea.KeyTab, tea.KeyShiftTab, tea.KeyUp, tea.KeyDown:
+		m.reviewField = 1 - m.reviewField
+	case tea.KeyBackspace:
+		if runes := []rune(*field); len(runes) > 0 {
+			*field = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		*field = ""
+	case tea.KeyRunes, tea.KeySpace:
+		*field += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// stop finishes the session and writes it to the log
+func (m model) stop() (tea.Model, tea.Cmd) {
+	if m.quitting {
+		return m, tea.Quit
+	}
+	m.running = false
+	m.quitting = true
+	if !m.paused {
+		m = m.pause(time.Now())
+	}
+	m.elapsed = m.activeTime(m.pausedAt)
+	write, tag, err := checkMinSession(m.elapsed)
+	if err != nil {
+		m.err = err
+		return m, tea.Quit
+	}
+	if !write {
+		m.discarded = true
+		return m, tea.Quit
+	}
+	// Save to CSV immediately on Ctrl+C
+	if records, err := m.logToCSV(tag); err != nil {
+		m.err = err
+	} else {
+		m.saved = true
+		m.logged = append(m.logged, records...)
+	}
+	return m, tea.Quit
+}
+
+// reachMaxSession pauses the session where its tracked time reached the
+// maximum, as the timer may have been left running with the computer
+// suspended, and stops it or asks what to do with the time since
+func (m model) reachMaxSession(now time.Time) (tea.Model, tea.Cmd) {
+	m = m.pause(now.Add(m.maxSession - m.elapsed))
+	m.autoStopped = true
+	m.writeState()
+	notify := notifyCmd("talogo", fmt.Sprintf(tr("%s reached the maximum session length of %s"), strings.Join(m.titles, " / "), m.maxSession))
+	if !m.promptMax {
+		stopped, cmd := m.stop()
+		return stopped, tea.Batch(cmd, notify)
+	}
+	// Asked once, keeping the time goes on with no limit
+	m.maxSession = 0
+	m.away = "session limit reached"
+	m.asking = true
+	return m, tea.Batch(tickCmd(), notify, hookCmd(EventPause, m.record()))
+}
 
-		if err := writer.Write(record); err != nil {
-			return fmt.Errorf("failed to write CSV record: %v", err)
+// pause stops counting time, finishing the current active span
+func (m model) pause(now time.Time) model {
+	span := timeSpan{Start: m.spanStart, End: now}
+	if m.pomodoro != nil {
+		span.Pomodoro = m.pomodoros + 1
+	}
+	m.spans = append(m.spans, span)
+	m.paused = true
+	m.pausedAt = now
+	m.elapsed = m.activeTime(now)
+	return m
+}
+
+// resume starts a new active span, leaving the pause out of the session
+func (m model) resume(now time.Time) model {
+	m.pausedFor += now.Sub(m.pausedAt)
+	m.spanStart = now
+	m.paused = false
+	m.away = ""
+	return m
+}
+
+// takeOver continues the session of state, tracked until now by another
+// process. States without spans, written by the HTTP API, are taken as a
+// single active span.
+func (m model) takeOver(state sessionState) model {
+	m.logFile = state.LogFile
+	m.titles = state.Titles
+	m.tags = state.Tags
+	m.notes = state.Notes
+	m.startTime = state.Start
+	m.spans = state.Spans
+	m.spanStart = state.SpanStart
+	if m.spanStart.IsZero() {
+		m.spanStart = state.Start
+	}
+	m.paused = state.Paused
+	m.pausedAt = state.PausedAt
+	m.pausedFor = state.PausedFor
+	m.elapsed = m.activeTime(time.Now())
+	for m.remindEvery > 0 && m.nextReminder <= m.elapsed {
+		m.nextReminder += m.remindEvery
+	}
+	return m
+}
+
+// keepPause resumes counting the time since the pause started, as if the
+// timer had not been paused
+func (m model) keepPause() model {
+	last := m.spans[len(m.spans)-1]
+	m.spans = m.spans[:len(m.spans)-1]
+	m.spanStart = last.Start
+	m.paused = false
+	m.away = ""
+	return m
+}
+
+// activeTime returns the time tracked up to now, leaving out the pauses.
+// Durations use the monotonic clock, so they are not affected by clock
+// adjustments or daylight saving changes.
+func (m model) activeTime(now time.Time) time.Duration {
+	if m.paused {
+		now = m.pausedAt
+	}
+	return now.Sub(m.startTime) - m.pausedFor
+}
+
+// idleCheckCmd checks the system idle time after idleCheckInterval
+func idleCheckCmd() tea.Cmd {
+	return tea.Tick(idleCheckInterval, func(time.Time) tea.Msg {
+		idle, err := systemIdleTime()
+		return idleMsg{idle, err}
+	})
+}
+
+// waitForLock waits for the next screen lock change
+func waitForLock(events <-chan bool) tea.Cmd {
+	return func() tea.Msg {
+		locked, ok := <-events
+		if !ok {
+			return nil
 		}
+		return lockMsg(locked)
+	}
+}
 
-		if currentEnd.Equal(endTime) {
-			break
+// forwardSignals sends the signals ending the process to p as signalMsg,
+// until the returned function is called. Windows reports closing the
+// console as SIGTERM.
+func forwardSignals(p *tea.Program) func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				p.Send(signalMsg{sig})
+			case <-done:
+				return
+			}
 		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
 
-		// Move to next day
-		currentStart = endOfDay.Add(time.Nanosecond)
+// notifyCmd shows a desktop notification without blocking the UI. Errors
+// are ignored, notifications are best effort.
+func notifyCmd(title, body string) tea.Cmd {
+	return func() tea.Msg {
+		notify(title, body)
+		return nil
 	}
+}
+
+func tickCmd() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+// logToCSV writes the active spans of the session to the log, with tag
+// added to them if not empty, and returns the records written
+func (m model) logToCSV(tag string) ([]Record, error) {
+	dayStart, err := configuredDayStart()
+	if err != nil {
+		return nil, err
+	}
+	var records []Record
+	for i, span := range m.spans {
+		// Spans shorter than a second, such as one left by resuming right
+		// before stopping, are not worth a record of their own
+		if span.End.Sub(span.Start) < time.Second && len(m.spans) > 1 {
+			continue
+		}
+		record := m.record()
+		record.Start = span.Start
+		record.End = span.End
+		record.Elapsed = span.End.Sub(span.Start)
+		if tag != "" {
+			record.Tags = withTag(record.Tags, tag)
+		}
+		if m.autoStopped && i == len(m.spans)-1 {
+			record.Tags = withTag(record.Tags, autoStoppedTag)
+		}
+		if span.Pomodoro > 0 {
+			record.Tags = pomodoroTags(record.Tags, span.Pomodoro, span.Pomodoro <= m.pomodoros)
+		}
+		records = append(records, talogo.SplitByDay(record, dayStart)...)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return m.save(records)
+}
 
-	// Ensure all data is written to disk
-	if err := file.Sync(); err != nil {
-		return fmt.Errorf("failed to sync file: %v", err)
+// writeState records the session as running for commands such as status.
+// Failing to do so only prints a warning.
+func (m model) writeState() {
+	err := writeSessionState(sessionState{
+		Titles:    m.titles,
+		Tags:      m.tags,
+		Notes:     m.notes,
+		Start:     m.startTime,
+		LogFile:   m.logFile,
+		PID:       os.Getpid(),
+		Paused:    m.paused,
+		PausedAt:  m.pausedAt,
+		PausedFor: m.pausedFor,
+		Spans:     m.spans,
+		SpanStart: m.spanStart,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("Warning: %v\n"), err)
 	}
+}
 
-	return nil
+// record returns the tracked session as a record. Its duration leaves out
+// the pauses, which the records written to the log are split at.
+func (m model) record() Record {
+	// Timestamps are written with their UTC offset, so the end time shows
+	// the offset in effect when the session finished
+	return withOrigin(Record{
+		Start:   m.startTime,
+		End:     m.startTime.Add(m.elapsed + m.pausedFor),
+		Titles:  m.titles,
+		Tags:    m.tags,
+		Notes:   m.notes,
+		Source:  talogo.SourceInteractive,
+		Elapsed: m.elapsed,
+	})
 }
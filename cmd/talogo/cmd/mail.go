@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mailAttachment is a file attached to an email
+type mailAttachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// sendMail emails a message with a plain text and an HTML version of its
+// body, and the given attachments, through the configured SMTP server
+func sendMail(config SMTPConfig, to []string, subject, text, html string, attachments []mailAttachment) error {
+	if config.Host == "" || config.From == "" {
+		return fmt.Errorf("smtp.host and smtp.from must be set in the config file")
+	}
+	if len(to) == 0 {
+		return fmt.Errorf("no recipients, set smtp.to in the config file")
+	}
+
+	message, err := buildMail(config.From, to, subject, text, html, attachments)
+	if err != nil {
+		return err
+	}
+
+	port := config.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(config.Host, strconv.Itoa(port))
+
+	var client *smtp.Client
+	if port == 465 {
+		conn, err := tls.Dial("tcp", addr, &tls.Config{ServerName: config.Host})
+		if err != nil {
+			return fmt.Errorf("failed to connect to %s: %v", addr, err)
+		}
+		client, err = smtp.NewClient(conn, config.Host)
+		if err != nil {
+			return fmt.Errorf("failed to connect to %s: %v", addr, err)
+		}
+	} else {
+		client, err = smtp.Dial(addr)
+		if err != nil {
+			return fmt.Errorf("failed to connect to %s: %v", addr, err)
+		}
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: config.Host}); err != nil {
+				return fmt.Errorf("failed to start TLS: %v", err)
+			}
+		}
+	}
+	defer client.Close()
+
+	if config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", config.Username, config.Password, config.Host)); err != nil {
+			return fmt.Errorf("failed to authenticate: %v", err)
+		}
+	}
+	if err := client.Mail(config.From); err != nil {
+		return fmt.Errorf("failed to send mail: %v", err)
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("failed to send mail to %s: %v", recipient, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send mail: %v", err)
+	}
+	if _, err := w.Write(message); err != nil {
+		return fmt.Errorf("failed to send mail: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send mail: %v", err)
+	}
+	return client.Quit()
+}
+
+// buildMail returns a MIME message with text and html as alternative
+// bodies, followed by the attachments
+func buildMail(from string, to []string, subject, text, html string, attachments []mailAttachment) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	mixed := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mixed.Boundary())
+
+	var body bytes.Buffer
+	alternative := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	} {
+		w, err := alternative.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		writeBase64Lines(w, []byte(part.content))
+	}
+	alternative.Close()
+
+	w, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + alternative.Boundary()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	w.Write(body.Bytes())
+
+	for _, attachment := range attachments {
+		w, err := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachment.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		writeBase64Lines(w, attachment.Data)
+	}
+	mixed.Close()
+	return buf.Bytes(), nil
+}
+
+// writeBase64Lines writes data base64 encoded in lines of 76 characters,
+// as required by MIME
+func writeBase64Lines(w io.Writer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		w.Write([]byte(encoded[:76] + "\r\n"))
+		encoded = encoded[76:]
+	}
+	w.Write([]byte(encoded + "\r\n"))
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	mergeCmdLogFile string
+	mergeCmdPrefer  string
+)
+
+// mergeCmd defines the merge subcommand
+var mergeCmd = &cobra.Command{
+	Use:   "merge OTHER",
+	Short: "Merge the records of another log file into the log",
+	Long: `Merge the records of another log file (e.g. copied from another machine)
+into the log. Records are matched by id; records with the same id but
+different times, titles, tags or notes are conflicts, resolved interactively
+unless --prefer is given. Records deleted from the log, found in its
+trash, are not merged back.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := mergeLogFiles(mergeCmdLogFile, args[0], mergeCmdPrefer); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error merging logs: %v\n"), err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	mergeCmd.Flags().StringVarP(&mergeCmdLogFile, "file", "f", defaultLogFile(), "Log file to merge into")
+	mergeCmd.Flags().StringVar(&mergeCmdPrefer, "prefer", "", "Resolve conflicting edits without asking, keeping the local or other version")
+	rootCmd.AddCommand(mergeCmd)
+}
+
+// mergeLogFiles merges the records of other into logFile
+func mergeLogFiles(logFile, other, prefer string) error {
+	local, err := readRecordsStrict(logFile)
+	if err != nil {
+		return err
+	}
+	otherRecords, err := readRecordsStrict(other)
+	if err != nil {
+		return err
+	}
+	deleted, err := deletedKeys(logFile, local)
+	if err != nil {
+		return err
+	}
+
+	result := mergeRecords(local, otherRecords, deleted)
+	merged, tookOther, err := resolveConflicts(result.Merged, result.Conflicts, prefer)
+	if err != nil {
+		return err
+	}
+
+	if result.Added+tookOther > 0 {
+		if err := rewriteRecords(logFile, merged); err != nil {
+			return err
+		}
+	}
+	printInfo("Added %d records, updated %d records\n", result.Added, tookOther)
+	if result.Deleted > 0 {
+		printInfo("Skipped %d records deleted from the log\n", result.Deleted)
+	}
+	return nil
+}
+
+// recordConflict is a record edited differently in two copies of the log
+type recordConflict struct {
+	Index int // Position of the local version in the merged records
+	Local Record
+	Other Record
+}
+
+// mergeResult is the outcome of merging two copies of the log
+type mergeResult struct {
+	Merged    []Record
+	Added     int // Records only found in the other copy
+	Deleted   int // Records of the other copy deleted from base
+	Conflicts []recordConflict
+}
+
+// recordKey identifies a record across copies of the log: its id, or its
+// times and titles for records logged before ids existed
+func recordKey(record Record) string {
+	if record.ID != "" {
+		return record.ID
+	}
+	return record.Start.Format(time.RFC3339) + "|" + record.End.Format(time.RFC3339) + "|" + strings.Join(record.Titles, "/")
+}
+
+// sameContent reports whether two versions of a record hold the same data
+func sameContent(a, b Record) bool {
+	return a.Start.Equal(b.Start) &&
+		a.End.Equal(b.End) &&
+		reflect.DeepEqual(a.Titles, b.Titles) &&
+		reflect.DeepEqual(a.Tags, b.Tags) &&
+		a.Notes == b.Notes
+}
+
+// mergeRecords returns base followed by the records of other missing from
+// base, but for those whose keys are in deleted. Records present in both
+// with different content are reported as conflicts, keeping the base
+// version in the merged records.
+func mergeRecords(base, other []Record, deleted map[string]bool) mergeResult {
+	positions := make(map[string]int, len(base))
+	for i, record := range base {
+		positions[recordKey(record)] = i
+	}
+
+	result := mergeResult{Merged: append([]Record{}, base...)}
+	for _, record := range other {
+		key := recordKey(record)
+		if i, ok := positions[key]; ok {
+			if !sameContent(result.Merged[i], record) {
+				result.Conflicts = append(result.Conflicts, recordConflict{Index: i, Local: result.Merged[i], Other: record})
+			}
+			continue
+		}
+		if deleted[key] {
+			result.Deleted++
+			continue
+		}
+		positions[key] = len(result.Merged)
+		result.Merged = append(result.Merged, record)
+		result.Added++
+	}
+	return result
+}
+
+// deletedKeys returns the keys of the records removed from logFile, as
+// recorded in its trash, that are not back among its records. Merging
+// them from another copy of the log would bring deleted sessions back.
+// Records dropped from the trash with 'talogo trash empty' are forgotten.
+func deletedKeys(logFile string, records []Record) (map[string]bool, error) {
+	entries, err := readTrash(logFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the trash: %v", err)
+	}
+	present := make(map[string]bool, len(records))
+	for _, record := range records {
+		present[recordKey(record)] = true
+	}
+
+	deleted := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.Reason != trashRemoved {
+			continue
+		}
+		record, err := entry.Record.Record()
+		if err != nil {
+			continue
+		}
+		if key := recordKey(record); !present[key] {
+			deleted[key] = true
+		}
+	}
+	return deleted, nil
+}
+
+// resolveConflicts picks a version for each conflict, as prefer ("local"
+// or "other") says or asking the user when prefer is empty. It returns the
+// merged records with the chosen versions and how many conflicts were
+// resolved with the other version.
+func resolveConflicts(merged []Record, conflicts []recordConflict, prefer string) ([]Record, int, error) {
+	if prefer != "" && prefer != "local" && prefer != "other" {
+		return nil, 0, fmt.Errorf("invalid --prefer value %q (expected local or other)", prefer)
+	}
+
+	stdin := bufio.NewReader(os.Stdin)
+	tookOther := 0
+	for _, c := range conflicts {
+		choice := prefer
+		for choice == "" {
+			fmt.Printf(tr("Conflicting versions of record %s:\n"), c.Local.ID)
+			fmt.Printf(tr("  [l]ocal: %s\n"), formatRecord(c.Local))
+			fmt.Printf(tr("  [o]ther: %s\n"), formatRecord(c.Other))
+			fmt.Print(tr("Keep which version? [l/o] "))
+
+			answer, err := stdin.ReadString('\n')
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "l", "local":
+				choice = "local"
+			case "o", "other":
+				choice = "other"
+			}
+			if choice == "" && err != nil {
+				return nil, 0, fmt.Errorf("conflicts left unresolved, use --prefer to resolve them without asking")
+			}
+		}
+
+		if choice == "other" {
+			merged[c.Index] = c.Other
+			tookOther++
+		}
+	}
+	return merged, tookOther, nil
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// handleMetrics writes metrics in the Prometheus text exposition format:
+// the state of the running session, and the tracked time and last
+// activity of each top level task in the log
+func (s *apiServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	tracked := make(map[string]time.Duration)
+	lastActivity := make(map[string]time.Time)
+	err := scanRecords(s.logFile, func(record Record) error {
+		task := "(untitled)"
+		if len(record.Titles) > 0 {
+			task = record.Titles[0]
+		}
+		tracked[task] += record.Duration()
+		if record.End.After(lastActivity[task]) {
+			lastActivity[task] = record.End
+		}
+		return nil
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.mu.Lock()
+	status := s.status()
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	running := 0
+	if status.Running {
+		running = 1
+	}
+	writeMetricHeader(w, "talogo_session_running", "gauge", "Whether a session is running")
+	fmt.Fprintf(w, "talogo_session_running %d\n", running)
+	writeMetricHeader(w, "talogo_session_elapsed_seconds", "gauge", "Elapsed time of the running session")
+	fmt.Fprintf(w, "talogo_session_elapsed_seconds %g\n", status.ElapsedSeconds)
+	if status.Running {
+		writeMetricHeader(w, "talogo_session_start_timestamp_seconds", "gauge", "Start time of the running session")
+		fmt.Fprintf(w, "talogo_session_start_timestamp_seconds{task=\"%s\"} %d\n",
+			escapeLabel(strings.Join(status.Session.Titles, "/")), status.Session.Start.Unix())
+	}
+
+	var tasks []string
+	for task := range tracked {
+		tasks = append(tasks, task)
+	}
+	sort.Strings(tasks)
+
+	writeMetricHeader(w, "talogo_tracked_seconds_total", "counter", "Time logged per top level task")
+	for _, task := range tasks {
+		fmt.Fprintf(w, "talogo_tracked_seconds_total{task=\"%s\"} %g\n", escapeLabel(task), tracked[task].Seconds())
+	}
+	writeMetricHeader(w, "talogo_last_activity_timestamp_seconds", "gauge", "End time of the last logged session per top level task")
+	for _, task := range tasks {
+		fmt.Fprintf(w, "talogo_last_activity_timestamp_seconds{task=\"%s\"} %d\n", escapeLabel(task), lastActivity[task].Unix())
+	}
+}
+
+// writeMetricHeader writes the HELP and TYPE lines of a metric
+func writeMetricHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// escapeLabel escapes a Prometheus label value
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
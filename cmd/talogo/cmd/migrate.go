@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateCmdLogFile string
+)
+
+// migrateCmd defines the migrate subcommand
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the log file to the current schema version",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := migrateLog(migrateCmdLogFile); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error migrating log: %v\n"), err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	migrateCmd.Flags().StringVarP(&migrateCmdLogFile, "file", "f", defaultLogFile(), "Log file to migrate")
+	rootCmd.AddCommand(migrateCmd)
+}
+
+// migrateLog rewrites a CSV log with the current schema, adding the
+// columns older versions lack. JSON-Lines and SQLite logs are
+// self-describing, they only get ids assigned to the records missing one.
+func migrateLog(logFile string) error {
+	backend, path := parseLogLocation(logFile)
+	if backend != "csv" {
+		return migrateRecordIDs(logFile)
+	}
+
+	version, schema, err := readCSVLayout(path)
+	if err != nil {
+		return err
+	}
+	if version == talogo.CSVSchemaVersion && len(schema.MissingColumnNames()) == 0 {
+		fmt.Printf(tr("%s is already at schema v%d\n"), logFile, talogo.CSVSchemaVersion)
+		return nil
+	}
+	if version > talogo.CSVSchemaVersion {
+		return fmt.Errorf("%s uses schema v%d, newer than this talogo supports (v%d)", logFile, version, talogo.CSVSchemaVersion)
+	}
+
+	records, err := readRecordsStrict(logFile)
+	if err != nil {
+		return err
+	}
+	for i := range records {
+		if records[i].ID == "" {
+			records[i].ID = talogo.NewID()
+		}
+	}
+
+	// Keep the delimiter of the file
+	canonical := talogo.CanonicalSchema(records)
+	canonical.Comma = schema.Comma
+	canonical.Precision = logPrecision()
+	err = rewriteLog(logFile, records, func() error {
+		return replaceLogFile(path, func(w io.Writer) error {
+			return talogo.WriteCSVLog(w, talogo.CSVSchemaVersion, canonical, records)
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf(tr("Migrated %s from schema v%d to v%d (previous version kept in %s.bak)\n"),
+		logFile, version, talogo.CSVSchemaVersion, path)
+	return nil
+}
+
+// migrateRecordIDs assigns ids to the records of a self-describing log
+// that lack one
+func migrateRecordIDs(logFile string) error {
+	records, err := readRecordsStrict(logFile)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for i := range records {
+		if records[i].ID == "" {
+			records[i].ID = talogo.NewID()
+			count++
+		}
+	}
+	if count == 0 {
+		fmt.Printf(tr("%s needs no migration\n"), logFile)
+		return nil
+	}
+
+	if err := rewriteRecords(logFile, records); err != nil {
+		return err
+	}
+	fmt.Printf(tr("Assigned ids to %d records of %s (previous version kept in %s.bak)\n"), count, logFile, logPath(logFile))
+	return nil
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// windowsToastScript shows a toast notification with the title and body
+// passed in environment variables, avoiding any quoting of user text
+const windowsToastScript = `
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $template.GetElementsByTagName('text')
+$text.Item(0).AppendChild($template.CreateTextNode($env:TALOGO_NOTIFY_TITLE)) > $null
+$text.Item(1).AppendChild($template.CreateTextNode($env:TALOGO_NOTIFY_BODY)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('talogo').Show([Windows.UI.Notifications.ToastNotification]::new($template))
+`
+
+// notify shows a desktop notification using notify-send on Linux and
+// BSD, osascript on macOS and a PowerShell toast on Windows. Nothing is
+// shown if notifications are disabled in the config file.
+func notify(title, body string) error {
+	if config, err := loadConfig(); err == nil && config.Notifications.Disabled {
+		return nil
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript",
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, body)
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsToastScript)
+		cmd.Env = append(os.Environ(), "TALOGO_NOTIFY_TITLE="+title, "TALOGO_NOTIFY_BODY="+body)
+	default:
+		cmd = exec.Command("notify-send", "--app-name=talogo", title, body)
+	}
+	return cmd.Run()
+}
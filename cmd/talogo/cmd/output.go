@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit codes shell scripts can branch on
+const (
+	exitOK          = 0 // Success
+	exitError       = 1 // Any error, such as an invalid flag or an unreadable log
+	exitNotRunning  = 2 // status: no session is running
+	exitCheckFailed = 3 // doctor, verify: some check found problems
+	exitDifferent   = 4 // diff: the logs hold different sessions
+)
+
+// quiet is the value of the global --quiet flag, leaving out decoration
+// and confirmation messages
+var quiet bool
+
+// outputFormat is the value of the global --output flag: text, or json
+// for the machine readable output of the outputCommands. Commands with
+// more formats, like summary, define their own --output.
+var outputFormat string
+
+// outputCommands are the paths of the commands honoring the global
+// --output flag
+var outputCommands = []string{"status", "list", "grep", "diff", "stats", "estimate report", "doctor"}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", "text", "Output format of "+joinList(outputCommands)+" (text, json)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Print only what scripts need, without decoration or confirmations")
+}
+
+// printInfo prints a confirmation message, translated, unless --quiet is
+// given
+func printInfo(format string, args ...interface{}) {
+	if !quiet {
+		fmt.Printf(tr(format), args...)
+	}
+}
+
+// checkOutputFormat fails if the global --output flag, unless cmd defines
+// its own, has an unknown format, or is given on the command line of a
+// command not honoring it. Defaults from the environment or the config
+// apply to the commands honoring it only.
+func checkOutputFormat(cmd *cobra.Command) error {
+	if cmd.Flags().Lookup("output") != cmd.Root().PersistentFlags().Lookup("output") {
+		return nil
+	}
+	if outputFormat != "text" && outputFormat != "json" {
+		return fmt.Errorf("unknown output format %q (expected text or json)", outputFormat)
+	}
+	if cmd.Flags().Changed("output") && !slices.Contains(outputCommands, runningCommand) {
+		return fmt.Errorf("%s does not support --output, only %s do", cmd.CommandPath(), joinList(outputCommands))
+	}
+	return nil
+}
+
+// joinList joins items as an English list, e.g. "a, b and c"
+func joinList(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+// jsonOutput reports whether the global --output flag asks for JSON
+func jsonOutput() bool {
+	return outputFormat == "json"
+}
+
+// printJSON prints v as indented JSON to stdout. Field names are
+// snake_case and, like those of the HTTP API, kept stable for scripts.
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
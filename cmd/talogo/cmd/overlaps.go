@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	overlapsCmdLogFile string
+)
+
+// Overlap is a pair of records whose time ranges intersect
+type Overlap struct {
+	First  Record
+	Second Record
+}
+
+// Duration returns how long both records overlap
+func (o Overlap) Duration() time.Duration {
+	start := o.Second.Start
+	if o.First.Start.After(start) {
+		start = o.First.Start
+	}
+	end := o.First.End
+	if o.Second.End.Before(end) {
+		end = o.Second.End
+	}
+	return end.Sub(start)
+}
+
+// overlapsCmd defines the overlaps subcommand
+var overlapsCmd = &cobra.Command{
+	Use:   "overlaps",
+	Short: "List records whose time ranges overlap",
+	Run: func(cmd *cobra.Command, args []string) {
+		records, err := readRecords(overlapsCmdLogFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error reading log: %v\n"), err)
+			os.Exit(1)
+		}
+
+		overlaps := findOverlaps(records)
+		if len(overlaps) == 0 {
+			fmt.Println(tr("No overlapping records"))
+			return
+		}
+		for _, o := range overlaps {
+			printOverlap(o)
+		}
+		fmt.Printf(tr("%d overlapping pairs found\n"), len(overlaps))
+	},
+}
+
+func init() {
+	overlapsCmd.Flags().StringVarP(&overlapsCmdLogFile, "file", "f", defaultLogFile(), "Log file to read")
+	rootCmd.AddCommand(overlapsCmd)
+}
+
+// findOverlaps returns every pair of records whose time ranges intersect,
+// ordered by the start time of the first record of the pair. Overlaps with
+// parallel records are left out.
+func findOverlaps(records []Record) []Overlap {
+	sorted := make([]Record, len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	var overlaps []Overlap
+	for i := range sorted {
+		for j := i + 1; j < len(sorted) && sorted[j].Start.Before(sorted[i].End); j++ {
+			overlaps = append(overlaps, Overlap{First: sorted[i], Second: sorted[j]})
+		}
+	}
+	return conflicts(overlaps)
+}
+
+// printOverlap prints an overlapping pair in a human readable way
+func printOverlap(o Overlap) {
+	fmt.Printf(tr("Overlap of %s:\n"), o.Duration().Round(time.Second))
+	for _, r := range []Record{o.First, o.Second} {
+		fmt.Printf(tr("  line %d: %s - %s %s\n"),
+			r.Line,
+			r.Start.Format(time.RFC3339),
+			r.End.Format(time.RFC3339),
+			strings.Join(r.Titles, " / "),
+		)
+	}
+}
+
+// findOverlapsWith returns the pairs of a record of the log file, first,
+// and one of records, second, whose time ranges intersect. Log records
+// that are one of records, already written, are left out. Records logged
+// by talogo are split by day, so only those starting up to a day before
+// the earliest of records are read. Overlaps with parallel records are
+// left out.
+func findOverlapsWith(logFile string, records []Record) ([]Overlap, error) {
+	if len(records) == 0 {
+		return nil, nil
+	}
+	if _, err := os.Stat(logPath(logFile)); err != nil {
+		return nil, nil
+	}
+
+	from, to := records[0].Start, records[0].End
+	for _, record := range records {
+		if record.Start.Before(from) {
+			from = record.Start
+		}
+		if record.End.After(to) {
+			to = record.End
+		}
+	}
+
+	var overlaps []Overlap
+	err := openStore(logFile).Query(from.Add(-24*time.Hour), to, func(logged Record) error {
+		for _, record := range records {
+			if sameRecord(logged, record) {
+				return nil
+			}
+		}
+		for _, record := range records {
+			if logged.Start.Before(record.End) && record.Start.Before(logged.End) {
+				overlaps = append(overlaps, Overlap{First: logged, Second: record})
+			}
+		}
+		return nil
+	})
+	return conflicts(overlaps), err
+}
+
+// sameRecord reports whether logged, read from the log, was written from
+// record. Logs without an id column are matched by times, to the second,
+// and titles.
+func sameRecord(logged, record Record) bool {
+	if logged.ID != "" && record.ID != "" {
+		return logged.ID == record.ID
+	}
+	return logged.Start.Truncate(time.Second).Equal(record.Start.Truncate(time.Second)) &&
+		logged.End.Truncate(time.Second).Equal(record.End.Truncate(time.Second)) &&
+		strings.Join(logged.Titles, "/") == strings.Join(record.Titles, "/")
+}
+
+// overlapError describes the records of the log a session overlaps
+func overlapError(overlaps []Overlap) error {
+	lines := make(map[int]bool)
+	for _, o := range overlaps {
+		lines[o.First.Line] = true
+	}
+	if len(lines) == 1 {
+		return fmt.Errorf("the session overlaps line %d of the log", overlaps[0].First.Line)
+	}
+	return fmt.Errorf("the session overlaps %d records of the log, starting at line %d", len(lines), overlaps[0].First.Line)
+}
+
+// warnOverlaps prints a warning for each record of the log file that
+// overlaps one of records, the session just written to it
+func warnOverlaps(logFile string, records []Record) {
+	overlaps, err := findOverlapsWith(logFile, records)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("Warning: failed to check for overlapping records: %v\n"), err)
+		return
+	}
+	for _, o := range overlaps {
+		fmt.Fprintf(os.Stderr, tr("Warning: the session overlaps line %d (%s - %s %s) by %s\n"),
+			o.First.Line,
+			o.First.Start.Format(time.RFC3339),
+			o.First.End.Format(time.RFC3339),
+			strings.Join(o.First.Titles, " / "),
+			o.Duration().Round(time.Second),
+		)
+	}
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"sort"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+)
+
+// parallelTag marks the records of sessions tracked alongside others on
+// purpose, e.g. on-call underneath regular work. Overlaps with them are
+// not problems, and the time they share with other records counts for
+// their tasks but only once towards the totals of their day.
+const parallelTag = "parallel"
+
+// isParallel reports whether record was tracked in parallel with others
+func isParallel(record Record) bool {
+	for _, tag := range record.Tags {
+		if tag == parallelTag {
+			return true
+		}
+	}
+	return false
+}
+
+// conflicts returns the overlaps that are not with a parallel record
+func conflicts(overlaps []Overlap) []Overlap {
+	var found []Overlap
+	for _, o := range overlaps {
+		if !isParallel(o.First) && !isParallel(o.Second) {
+			found = append(found, o)
+		}
+	}
+	return found
+}
+
+// overlapTime returns how much of record others overlap, counting the
+// time overlapped by several of them once
+func overlapTime(record Record, others []Record) time.Duration {
+	type span struct{ start, end time.Time }
+	var spans []span
+	for _, other := range others {
+		start, end := record.Start, record.End
+		if other.Start.After(start) {
+			start = other.Start
+		}
+		if other.End.Before(end) {
+			end = other.End
+		}
+		if start.Before(end) {
+			spans = append(spans, span{start, end})
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].start.Before(spans[j].start)
+	})
+
+	var total time.Duration
+	var covered time.Time
+	for _, s := range spans {
+		if s.start.Before(covered) {
+			s.start = covered
+		}
+		if s.end.After(s.start) {
+			total += s.end.Sub(s.start)
+			covered = s.end
+		}
+	}
+	// Paused sessions tracked less than their time range
+	return min(total, record.Duration())
+}
+
+// parallelOverlaps returns, by day, the time of the parallel records of
+// records that other records, not parallel, overlap, and so is counted
+// twice when adding up their durations
+func parallelOverlaps(records []Record, dayStart time.Duration) map[string]time.Duration {
+	var others []Record
+	for _, record := range records {
+		if !isParallel(record) {
+			others = append(others, record)
+		}
+	}
+	overlaps := make(map[string]time.Duration)
+	for _, record := range records {
+		if !isParallel(record) {
+			continue
+		}
+		if d := overlapTime(record, others); d > 0 {
+			overlaps[talogo.DayOf(record.Start, dayStart)] += d
+		}
+	}
+	return overlaps
+}
+
+// loggedParallelOverlaps is parallelOverlaps for the parallel records,
+// read from the log file, and the records of the log matching match
+func loggedParallelOverlaps(logFile string, parallel []Record, match func(Record) bool, dayStart time.Duration) (map[string]time.Duration, error) {
+	if len(parallel) == 0 {
+		return nil, nil
+	}
+	from, to := parallel[0].Start, parallel[0].End
+	for _, record := range parallel {
+		if record.Start.Before(from) {
+			from = record.Start
+		}
+		if record.End.After(to) {
+			to = record.End
+		}
+	}
+
+	records := append([]Record{}, parallel...)
+	err := openStore(logFile).Query(from.Add(-24*time.Hour), to, func(logged Record) error {
+		if !isParallel(logged) && match(logged) {
+			records = append(records, logged)
+		}
+		return nil
+	})
+	return parallelOverlaps(records, dayStart), err
+}
+
+// discountOverlaps takes the time of parallel records counted twice,
+// by day, off the totals of days and of total, if any
+func discountOverlaps(days map[string]*TaskNode, total *TaskNode, overlaps map[string]time.Duration) {
+	for date, d := range overlaps {
+		day, exists := days[date]
+		if !exists {
+			continue
+		}
+		day.TotalTime -= d
+		if total != nil {
+			total.TotalTime -= d
+		}
+	}
+}
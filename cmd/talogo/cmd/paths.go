@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// dataDir returns the directory where talogo keeps its data by default:
+// $XDG_DATA_HOME/talogo on Unix, ~/Library/Application Support/talogo on
+// macOS and %LocalAppData%\talogo on Windows
+func dataDir() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "talogo")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "."
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return filepath.Join(home, "Library", "Application Support", "talogo")
+	case "windows":
+		if dir := os.Getenv("LocalAppData"); dir != "" {
+			return filepath.Join(dir, "talogo")
+		}
+		return filepath.Join(home, "AppData", "Local", "talogo")
+	default:
+		return filepath.Join(home, ".local", "share", "talogo")
+	}
+}
+
+// defaultLogFile returns the log file used when neither the --file flag,
+// TALOGO_FILE nor the config file set one
+func defaultLogFile() string {
+	return filepath.Join(dataDir(), "talogo.csv")
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+	"github.com/spf13/cobra"
+)
+
+// pendingBatch holds records that could not be written to their log,
+// waiting to be replayed into it
+type pendingBatch struct {
+	LogFile string              `json:"log_file"`
+	Error   string              `json:"error"` // Why writing to the log failed
+	Records []talogo.JSONRecord `json:"records"`
+}
+
+// pendingCmd defines the pending subcommand
+var pendingCmd = &cobra.Command{
+	Use:   "pending",
+	Short: "List sessions that could not be written to their log",
+	Long: `List the sessions that could not be written to their log, because the
+disk was full or the file was not writable. They are kept in the state
+directory, or the temporary directory if that fails too, until written to
+the log with 'talogo pending replay'.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		files, err := pendingFiles()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error listing pending sessions: %v\n"), err)
+			os.Exit(1)
+		}
+		if len(files) == 0 {
+			fmt.Println(tr("No pending sessions"))
+			return
+		}
+		for _, file := range files {
+			batch, err := readPendingBatch(file)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, tr("Skipping %s: %v\n"), file, err)
+				continue
+			}
+			fmt.Printf(tr("%s: %d records for %s (%s)\n"), file, len(batch.Records), batch.LogFile, batch.Error)
+		}
+	},
+}
+
+var pendingReplayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Write the pending sessions to their log",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		files, err := pendingFiles()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error replaying pending sessions: %v\n"), err)
+			os.Exit(1)
+		}
+		failed := false
+		for _, file := range files {
+			count, logFile, err := replayPendingBatch(file)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error replaying %s: %v\n"), file, err)
+				failed = true
+				continue
+			}
+			printInfo("Wrote %d records to %s\n", count, logFile)
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	pendingCmd.AddCommand(pendingReplayCmd)
+	rootCmd.AddCommand(pendingCmd)
+}
+
+// pendingDirs returns the directories pending sessions are saved to, in
+// order of preference: the talogo directory of XDG_STATE_HOME (by default
+// ~/.local/state on Linux, the data directory elsewhere) and a talogo
+// directory in the temporary directory
+func pendingDirs() []string {
+	state := filepath.Join(dataDir(), "pending")
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		state = filepath.Join(dir, "talogo", "pending")
+	} else if home, err := os.UserHomeDir(); err == nil && runtime.GOOS != "darwin" && runtime.GOOS != "windows" {
+		state = filepath.Join(home, ".local", "state", "talogo", "pending")
+	}
+	return []string{state, filepath.Join(os.TempDir(), "talogo-pending")}
+}
+
+// appendOrSavePending appends records to the log file, saving them as a
+// pending batch if that fails. The error then says where they were saved.
+func appendOrSavePending(logFile string, records []Record) error {
+	if err := appendRecords(logFile, records); err != nil {
+		return savePending(logFile, records, err, "once the log is writable")
+	}
+	return nil
+}
+
+// savePending saves records that were not written to the log file
+// because of cause as a pending batch, and returns cause with where they
+// were saved and when to replay them
+func savePending(logFile string, records []Record, cause error, when string) error {
+	path, err := savePendingBatch(logFile, records, cause)
+	if err != nil {
+		return fmt.Errorf("%v (saving the session elsewhere failed too: %v)", cause, err)
+	}
+	return fmt.Errorf("%v; the session was saved to %s, run 'talogo pending replay' %s", cause, path, when)
+}
+
+// savePendingBatch writes records that could not be appended to logFile
+// to the first pending directory that accepts them, and returns the path
+// of the file written
+func savePendingBatch(logFile string, records []Record, cause error) (string, error) {
+	// Flushed from whatever directory the next command runs in
+	location, err := absLogLocation(logFile)
+	if err != nil {
+		return "", err
+	}
+	batch := pendingBatch{LogFile: location, Error: cause.Error()}
+	for _, record := range records {
+		batch.Records = append(batch.Records, talogo.NewJSONRecord(record, logPrecision()))
+	}
+	data, err := json.MarshalIndent(batch, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode pending session: %v", err)
+	}
+
+	name := fmt.Sprintf("%s-%d.json", time.Now().Format("20060102T150405.000000000"), os.Getpid())
+	for _, dir := range pendingDirs() {
+		if err = os.MkdirAll(dir, 0700); err != nil {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if err = os.WriteFile(path, data, 0600); err == nil {
+			return path, nil
+		}
+		os.Remove(path)
+	}
+	return "", err
+}
+
+// pendingFiles returns the pending batches of every pending directory,
+// oldest first
+func pendingFiles() ([]string, error) {
+	var files []string
+	for _, dir := range pendingDirs() {
+		matches, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	// Names start with the time they were saved at
+	sort.Slice(files, func(i, j int) bool {
+		return filepath.Base(files[i]) < filepath.Base(files[j])
+	})
+	return files, nil
+}
+
+// readPendingBatch reads the pending batch in file
+func readPendingBatch(file string) (pendingBatch, error) {
+	var batch pendingBatch
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return batch, err
+	}
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return batch, fmt.Errorf("invalid pending session: %v", err)
+	}
+	return batch, nil
+}
+
+// replayPendingBatch appends the records of the pending batch in file to
+// its log and removes the file. Records whose id is already in the log,
+// written before the failure, are skipped. It returns the number of
+// records written and the log they were written to.
+func replayPendingBatch(file string) (int, string, error) {
+	batch, err := readPendingBatch(file)
+	if err != nil {
+		return 0, "", err
+	}
+
+	existing := make(map[string]bool)
+	if _, err := os.Stat(logPath(batch.LogFile)); err == nil {
+		err := scanRecords(batch.LogFile, func(record Record) error {
+			existing[record.ID] = true
+			return nil
+		})
+		if err != nil {
+			return 0, batch.LogFile, err
+		}
+	}
+
+	var records []Record
+	for _, j := range batch.Records {
+		record, err := j.Record()
+		if err != nil {
+			return 0, batch.LogFile, fmt.Errorf("invalid pending record: %v", err)
+		}
+		if record.ID != "" && existing[record.ID] {
+			continue
+		}
+		records = append(records, record)
+	}
+	if len(records) > 0 {
+		if err := appendRecords(batch.LogFile, records); err != nil {
+			return 0, batch.LogFile, err
+		}
+	}
+	if err := os.Remove(file); err != nil {
+		return len(records), batch.LogFile, fmt.Errorf("failed to remove %s: %v", file, err)
+	}
+	return len(records), batch.LogFile, nil
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// orgHeading is a task of the org-mode export, with the sessions logged
+// directly on it and its subtasks
+type orgHeading struct {
+	records  []Record
+	children map[string]*orgHeading
+}
+
+// writeRecordsOrg writes records as an org-mode outline with one heading
+// per task and its sessions as CLOCK lines in a LOGBOOK drawer, so org
+// clocktables can report on them
+func writeRecordsOrg(w io.Writer, records []Record) error {
+	root := &orgHeading{children: make(map[string]*orgHeading)}
+	for _, record := range records {
+		heading := root
+		for _, title := range record.Titles {
+			child, exists := heading.children[title]
+			if !exists {
+				child = &orgHeading{children: make(map[string]*orgHeading)}
+				heading.children[title] = child
+			}
+			heading = child
+		}
+		heading.records = append(heading.records, record)
+	}
+	return writeOrgHeadings(w, root.children, 1)
+}
+
+// writeOrgHeadings writes headings at the given level, sorted by title
+func writeOrgHeadings(w io.Writer, headings map[string]*orgHeading, level int) error {
+	var titles []string
+	for title := range headings {
+		titles = append(titles, title)
+	}
+	sort.Strings(titles)
+
+	for _, title := range titles {
+		heading := headings[title]
+		if _, err := fmt.Fprintf(w, "%s %s\n", strings.Repeat("*", level), title); err != nil {
+			return fmt.Errorf("failed to write org: %v", err)
+		}
+
+		if len(heading.records) > 0 {
+			// Org lists the most recent clock first
+			sort.SliceStable(heading.records, func(i, j int) bool {
+				return heading.records[i].Start.After(heading.records[j].Start)
+			})
+			fmt.Fprintln(w, ":LOGBOOK:")
+			for _, record := range heading.records {
+				minutes := int(record.Duration().Round(time.Minute).Minutes())
+				fmt.Fprintf(w, "CLOCK: %s--%s => %2d:%02d\n",
+					orgTimestamp(record.Start), orgTimestamp(record.End), minutes/60, minutes%60)
+			}
+			if _, err := fmt.Fprintln(w, ":END:"); err != nil {
+				return fmt.Errorf("failed to write org: %v", err)
+			}
+		}
+
+		if err := writeOrgHeadings(w, heading.children, level+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// orgTimestamp formats t as an inactive org-mode timestamp
+func orgTimestamp(t time.Time) string {
+	return t.Format("[2006-01-02 Mon 15:04]")
+}
+
+// writeRecordsTimeclock writes records in the timeclock format read by
+// ledger and hledger, in chronological order, with the task path as the
+// account name (e.g. work:coding)
+func writeRecordsTimeclock(w io.Writer, records []Record) error {
+	sorted := append([]Record{}, records...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	for _, record := range sorted {
+		// Colons separate account name components, so they can't appear
+		// inside a title
+		var parts []string
+		for _, title := range record.Titles {
+			parts = append(parts, strings.ReplaceAll(title, ":", "-"))
+		}
+		account := strings.Join(parts, ":")
+		if account == "" {
+			account = "(untitled)"
+		}
+
+		description := strings.Join(strings.Fields(record.Notes), " ")
+		line := fmt.Sprintf("i %s %s", record.Start.Format("2006/01/02 15:04:05"), account)
+		if description != "" {
+			// Two spaces end the account name
+			line += "  " + description
+		}
+		_, err := fmt.Fprintf(w, "%s\no %s\n", line, record.End.Format("2006/01/02 15:04:05"))
+		if err != nil {
+			return fmt.Errorf("failed to write timeclock: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+	"github.com/spf13/cobra"
+)
+
+// pluginPrefix starts the name of the executables run as plugins, e.g.
+// talogo-toggl runs as 'talogo toggl'
+const pluginPrefix = "talogo-"
+
+// pluginProtocolVersion is the version of the stdin document of plugins,
+// raised on incompatible changes
+const pluginProtocolVersion = 1
+
+// pluginInput is the JSON document plugins receive on stdin
+type pluginInput struct {
+	Version  int                 `json:"version"`
+	LogFile  string              `json:"log_file"`
+	Session  *sessionState       `json:"session"`  // Latest running session, null if none
+	Sessions []sessionState      `json:"sessions"` // Every running session, oldest first
+	Records  []talogo.JSONRecord `json:"records"`  // Records of the log, in log order
+}
+
+// addPluginCommands adds a subcommand for each plugin found on PATH.
+// Built-in commands take precedence over plugins with the same name, and
+// earlier PATH entries over later ones.
+func addPluginCommands() {
+	for name, path := range findPlugins() {
+		if cmd, _, err := rootCmd.Find([]string{name}); err == nil && cmd != rootCmd {
+			continue
+		}
+		rootCmd.AddCommand(newPluginCommand(name, path))
+	}
+}
+
+// findPlugins returns the path of the executable of each plugin on PATH,
+// keyed by plugin name
+func findPlugins() map[string]string {
+	plugins := make(map[string]string)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(entry)
+			if !ok {
+				continue
+			}
+			if _, found := plugins[name]; !found {
+				plugins[name] = filepath.Join(dir, entry.Name())
+			}
+		}
+	}
+	return plugins
+}
+
+// pluginName returns the plugin name of a PATH entry, if it is a plugin
+// executable
+func pluginName(entry os.DirEntry) (string, bool) {
+	file := entry.Name()
+	if !strings.HasPrefix(file, pluginPrefix) || entry.IsDir() {
+		return "", false
+	}
+	if runtime.GOOS == "windows" {
+		if !strings.EqualFold(filepath.Ext(file), ".exe") {
+			return "", false
+		}
+		file = strings.TrimSuffix(file, filepath.Ext(file))
+	} else if info, err := entry.Info(); err != nil || info.Mode().Perm()&0111 == 0 {
+		return "", false
+	}
+	name := strings.TrimPrefix(file, pluginPrefix)
+	return name, name != ""
+}
+
+// newPluginCommand returns the subcommand running the plugin executable
+// at path. Its arguments and flags are passed to the plugin untouched.
+func newPluginCommand(name, path string) *cobra.Command {
+	var logFile string
+	cmd := &cobra.Command{
+		Use:   name,
+		Short: fmt.Sprintf("Plugin %s", path),
+		Long: fmt.Sprintf(`Run the plugin %s with the given arguments, untouched.
+
+The plugin reads a JSON document on stdin with the log file, the running
+session (null if none) and the records of the log. The log is the one of
+TALOGO_FILE, the active project or the config file, and is passed to the
+plugin in TALOGO_FILE as well.`, path),
+		DisableFlagParsing: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runPlugin(path, logFile, args); err != nil {
+				var exitErr *exec.ExitError
+				if errors.As(err, &exitErr) {
+					os.Exit(exitErr.ExitCode())
+				}
+				fmt.Fprintf(os.Stderr, tr("Error running plugin %s: %v\n"), name, err)
+				os.Exit(1)
+			}
+		},
+	}
+	// Not parsed, but set like any --file flag from the environment, the
+	// active project or the config file
+	cmd.Flags().StringVarP(&logFile, "file", "f", defaultLogFile(), "Log file passed to the plugin")
+	return cmd
+}
+
+// runPlugin runs the plugin executable at path with args, writing the log
+// and the running session to its stdin as JSON. The plugin gets the log
+// file in TALOGO_FILE too, so the talogo commands it runs use the same log.
+func runPlugin(path, logFile string, args []string) error {
+	input := pluginInput{
+		Version: pluginProtocolVersion,
+		LogFile: logFile,
+		Records: []talogo.JSONRecord{},
+	}
+	input.Sessions = readSessionStates()
+	if len(input.Sessions) > 0 {
+		input.Session = &input.Sessions[len(input.Sessions)-1]
+	} else {
+		input.Sessions = []sessionState{}
+	}
+	if _, err := os.Stat(logPath(logFile)); err == nil {
+		records, err := readRecords(logFile)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			input.Records = append(input.Records, talogo.NewJSONRecord(record, logPrecision()))
+		}
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to encode plugin input: %v", err)
+	}
+
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), envVarName("file")+"="+logFile)
+	return cmd.Run()
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pomodoroTag marks the records of pomodoro work. Those of completed
+// pomodoros also have the number of the pomodoro of the day, e.g.
+// pomodoro:3.
+const pomodoroTag = "pomodoro"
+
+// pomodoroTags returns tags with the pomodoro tags of the work of
+// pomodoro number
+func pomodoroTags(tags []string, number int, completed bool) []string {
+	tags = withTag(tags, pomodoroTag)
+	if completed {
+		tags = withTag(tags, pomodoroTag+":"+strconv.Itoa(number))
+	}
+	return tags
+}
+
+// pomodoroNumber returns the number of the completed pomodoro of a record
+// with tags, 0 if it is not the work of one
+func pomodoroNumber(tags []string) int {
+	for _, tag := range tags {
+		if n, ok := strings.CutPrefix(tag, pomodoroTag+":"); ok {
+			if number, err := strconv.Atoi(n); err == nil && number > 0 {
+				return number
+			}
+		}
+	}
+	return 0
+}
+
+// lastPomodoro returns the number of the last completed pomodoro of
+// records, 0 if none
+func lastPomodoro(records []Record) int {
+	last := 0
+	for _, record := range records {
+		last = max(last, pomodoroNumber(record.Tags))
+	}
+	return last
+}
+
+// pomodoroCycle holds the lengths of the intervals of 'log --pomodoro':
+// pomodoros of tracked work, each followed by a break the timer is paused
+// for, long every LongBreakEvery pomodoros
+type pomodoroCycle struct {
+	Work           time.Duration
+	ShortBreak     time.Duration
+	LongBreak      time.Duration
+	LongBreakEvery int
+}
+
+// longBreak reports whether the break after pomodoro n, counted from 1,
+// is a long one
+func (c pomodoroCycle) longBreak(n int) bool {
+	return c.LongBreakEvery > 0 && n%c.LongBreakEvery == 0
+}
+
+// breakAfter returns the length of the break after pomodoro n
+func (c pomodoroCycle) breakAfter(n int) time.Duration {
+	if c.longBreak(n) {
+		return c.LongBreak
+	}
+	return c.ShortBreak
+}
+
+// logPomodoroCycle returns the pomodoro cycle of the config with the
+// lengths given to the log subcommand instead
+func logPomodoroCycle(config *Config) (*pomodoroCycle, error) {
+	cycle, err := config.pomodoroCycle()
+	if err != nil {
+		return nil, err
+	}
+	if logCmdWork > 0 {
+		cycle.Work = logCmdWork
+	}
+	if logCmdShortBreak > 0 {
+		cycle.ShortBreak = logCmdShortBreak
+	}
+	if logCmdLongBreak > 0 {
+		cycle.LongBreak = logCmdLongBreak
+	}
+	if logCmdLongEvery > 0 {
+		cycle.LongBreakEvery = logCmdLongEvery
+	}
+	return &cycle, nil
+}
+
+// pomodoroTick moves the pomodoro cycle on at now: the pomodoro ends with
+// a break once its time is tracked, and the break with the next pomodoro
+// once it is over
+func (m model) pomodoroTick(now time.Time) (model, tea.Cmd) {
+	switch {
+	case m.onBreak && !now.Before(m.breakEnd):
+		m = m.endBreak(now)
+		return m, notifyCmd("talogo", fmt.Sprintf(tr("Break over, back to %s"), strings.Join(m.titles, " / ")))
+	case !m.onBreak && !m.paused && m.elapsed >= m.workEnd:
+		// The span ending the pomodoro is part of it
+		m = m.pause(now)
+		m.pomodoros++
+		length := m.pomodoro.breakAfter(m.pomodoros)
+		m.onBreak = true
+		m.breakEnd = now.Add(length)
+		m.writeState()
+		return m, tea.Batch(hookCmd(EventPause, m.record()), notifyCmd("talogo",
+			fmt.Sprintf(tr("Pomodoro %d done, take a %s break"), m.pomodoros, length)))
+	}
+	return m, nil
+}
+
+// endBreak resumes tracking after a pomodoro break, starting the next
+// pomodoro
+func (m model) endBreak(now time.Time) model {
+	task := m.breaks.short
+	if m.pomodoro.longBreak(m.pomodoros) {
+		task = m.breaks.long
+	}
+	m = m.logBreak(task, now)
+	m = m.resume(now)
+	m.onBreak = false
+	m.workEnd = m.elapsed + m.pomodoro.Work
+	m.writeState()
+	return m
+}
+
+// pomodoroView returns the line below the timer telling the pomodoro and
+// the time left of it, empty outside pomodoro mode and on breaks
+func (m model) pomodoroView() string {
+	if m.pomodoro == nil || m.onBreak {
+		return ""
+	}
+	return fmt.Sprintf(tr("Pomodoro %d, %s left"), m.pomodoros+1, stopwatchTime(max(m.workEnd-m.elapsed, 0))) + "\n"
+}
+
+// breakNotice returns the notice shown during a pomodoro break
+func (m model) breakNotice() string {
+	format := tr("Short break, %s left, press p to skip it\n")
+	if m.pomodoro.longBreak(m.pomodoros) {
+		format = tr("Long break, %s left, press p to skip it\n")
+	}
+	return fmt.Sprintf(format, stopwatchTime(max(time.Until(m.breakEnd), 0)))
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	projectAddCmdDir string
+	useCmdClear      bool
+)
+
+// projectCmd defines the project subcommand
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Manage named projects, each with its own log file",
+}
+
+var projectAddCmd = &cobra.Command{
+	Use:   "add NAME FILE",
+	Short: "Register a project and its log file",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, file := args[0], expandHome(args[1])
+		if strings.ContainsAny(name, ". ") {
+			fmt.Fprintf(os.Stderr, tr("Invalid project name %q: names cannot contain dots or spaces\n"), name)
+			os.Exit(1)
+		}
+
+		project := map[string]interface{}{"file": file}
+		if projectAddCmdDir != "" {
+			dir, err := filepath.Abs(expandHome(projectAddCmdDir))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error adding project: %v\n"), err)
+				os.Exit(1)
+			}
+			project["dir"] = dir
+		}
+
+		if err := updateConfigTable([]string{"projects", name}, project); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error adding project: %v\n"), err)
+			os.Exit(1)
+		}
+		printInfo("Added project %s logging to %s\n", name, file)
+	},
+}
+
+var projectRemoveCmd = &cobra.Command{
+	Use:   "remove NAME",
+	Short: "Unregister a project, leaving its log file in place",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := updateConfigTable([]string{"projects", args[0]}, nil); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error removing project: %v\n"), err)
+			os.Exit(1)
+		}
+	},
+}
+
+var projectListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the registered projects, marking the active one",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := loadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error listing projects: %v\n"), err)
+			os.Exit(1)
+		}
+		active, _, err := activeProject(config)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error listing projects: %v\n"), err)
+			os.Exit(1)
+		}
+
+		var names []string
+		for name := range config.Projects {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			project := config.Projects[name]
+			marker := " "
+			if name == active {
+				marker = "*"
+			}
+			line := fmt.Sprintf("%s %s\t%s", marker, name, project.File)
+			if project.Dir != "" {
+				line += fmt.Sprintf("\t(in %s)", project.Dir)
+			}
+			fmt.Println(line)
+		}
+	},
+}
+
+// useCmd defines the use subcommand
+var useCmd = &cobra.Command{
+	Use:   "use [NAME]",
+	Short: "Make a project the active one, or print the active project",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := loadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+
+		if useCmdClear {
+			if err := os.Remove(activeProjectFile()); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, tr("Error clearing active project: %v\n"), err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if len(args) == 0 {
+			name, project, err := activeProject(config)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+				os.Exit(1)
+			}
+			if name == "" {
+				fmt.Println(tr("No active project"))
+				return
+			}
+			fmt.Printf("%s\t%s\n", name, project.File)
+			return
+		}
+
+		if _, ok := config.Projects[args[0]]; !ok {
+			fmt.Fprintf(os.Stderr, tr("Unknown project %q (register it with 'talogo project add')\n"), args[0])
+			os.Exit(1)
+		}
+		if err := os.MkdirAll(dataDir(), 0755); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error setting active project: %v\n"), err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(activeProjectFile(), []byte(args[0]+"\n"), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error setting active project: %v\n"), err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	projectAddCmd.Flags().StringVar(&projectAddCmdDir, "dir", "", "Directory in which the project becomes active automatically")
+	projectCmd.AddCommand(projectAddCmd)
+	projectCmd.AddCommand(projectRemoveCmd)
+	projectCmd.AddCommand(projectListCmd)
+	rootCmd.AddCommand(projectCmd)
+
+	useCmd.Flags().BoolVar(&useCmdClear, "clear", false, "Go back to the default log file")
+	rootCmd.AddCommand(useCmd)
+}
+
+// activeProjectFile returns the file storing the project selected with
+// 'talogo use'
+func activeProjectFile() string {
+	return filepath.Join(dataDir(), "active_project")
+}
+
+// activeProject returns the project whose log file commands use by
+// default: the project whose directory contains the working directory
+// (the innermost one if nested), otherwise the one selected with
+// 'talogo use'. The name is empty if there is no active project.
+func activeProject(config *Config) (string, ProjectConfig, error) {
+	if wd, err := os.Getwd(); err == nil {
+		var best string
+		for name, project := range config.Projects {
+			if project.Dir == "" || !isWithin(wd, expandHome(project.Dir)) {
+				continue
+			}
+			if best == "" || len(project.Dir) > len(config.Projects[best].Dir) {
+				best = name
+			}
+		}
+		if best != "" {
+			return best, config.Projects[best], nil
+		}
+	}
+
+	data, err := os.ReadFile(activeProjectFile())
+	if os.IsNotExist(err) {
+		return "", ProjectConfig{}, nil
+	}
+	if err != nil {
+		return "", ProjectConfig{}, fmt.Errorf("failed to read active project: %v", err)
+	}
+	name := strings.TrimSpace(string(data))
+	project, ok := config.Projects[name]
+	if !ok {
+		return "", ProjectConfig{}, fmt.Errorf("active project %q is not registered (run 'talogo use --clear')", name)
+	}
+	return name, project, nil
+}
+
+// isWithin reports whether path is dir or inside it
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	promptCmdIcon string
+	promptCmdFull bool
+)
+
+// promptCmd defines the prompt subcommand
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print a compact running session segment for tmux or shell prompts",
+	Long: `Print a compact segment such as "▶ project-x 1:23" for each running
+session, or nothing when idle. It only reads the session state files, so it
+is fast enough for tmux status-right or a starship custom module.`,
+	Args: cobra.NoArgs,
+	// Skip reading the config file, flags don't need defaults here
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var segments []string
+		for _, state := range readSessionStates() {
+			if len(state.Titles) == 0 {
+				continue
+			}
+			task := state.Titles[0]
+			if promptCmdFull {
+				task = strings.Join(state.Titles, "/")
+			}
+			segments = append(segments, fmt.Sprintf("%s %s %s", promptCmdIcon, task, formatClock(state.elapsed())))
+		}
+		if len(segments) > 0 {
+			fmt.Println(strings.Join(segments, "  "))
+		}
+	},
+}
+
+func init() {
+	promptCmd.Flags().StringVar(&promptCmdIcon, "icon", "▶", "Symbol printed before the task")
+	promptCmd.Flags().BoolVar(&promptCmdFull, "full", false, "Print the whole task path instead of the first title")
+	rootCmd.AddCommand(promptCmd)
+}
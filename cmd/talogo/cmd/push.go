@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+)
+
+// pushService describes an external time tracker sessions are pushed to
+type pushService struct {
+	// name identifies the service in messages and in the file recording
+	// the synced records, e.g. "jira"
+	name string
+	// target returns where record goes in the service, e.g. an issue key,
+	// or an empty string if the record is not pushed
+	target func(record Record) string
+	// post creates the entry of record in target and returns its id
+	post func(record Record, target string) (string, error)
+}
+
+// syncedRecordsFile returns the file recording the entry created in the
+// named service for each synced record of logFile
+func syncedRecordsFile(logFile, service string) string {
+	return logPath(logFile) + "." + service + ".json"
+}
+
+// syncDateRange validates the --from and --to dates of the sync commands,
+// both defaulting to today
+func syncDateRange(from, to string) (string, string, error) {
+	today := time.Now().Format("2006-01-02")
+	if from == "" {
+		from = today
+	}
+	if to == "" {
+		to = today
+	}
+	for _, date := range []string{from, to} {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return "", "", fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", date)
+		}
+	}
+	return from, to, nil
+}
+
+// pushRecords creates an entry in the service for each record between
+// the from and to dates (inclusive) that has a target and was not synced
+// before, and marks the records pushed as synced
+func pushRecords(logFile string, service pushService, from, to string, dryRun bool) error {
+	dayStart, err := configuredDayStart()
+	if err != nil {
+		return err
+	}
+	records, err := queryDays(logFile, from, to, dayStart)
+	if err != nil {
+		return err
+	}
+	syncedFile := syncedRecordsFile(logFile, service.name)
+	synced, err := loadSyncedRecords(syncedFile)
+	if err != nil {
+		return err
+	}
+
+	posted, noID := 0, 0
+	pushed := make(map[string]bool)
+	// saveSynced records what was pushed so far, in the service file and
+	// in the log
+	saveSynced := func() error {
+		if err := saveSyncedRecords(syncedFile, synced); err != nil {
+			return err
+		}
+		if _, err := markRecords(logFile, syncedTag, pushed, false); err != nil {
+			return fmt.Errorf("failed to mark the pushed sessions as synced: %v", err)
+		}
+		return nil
+	}
+	for _, record := range records {
+		date := talogo.DayOf(record.Start, dayStart)
+		if date < from || date > to {
+			continue
+		}
+		if _, done := synced[record.ID]; done && record.ID != "" {
+			continue
+		}
+		target := service.target(record)
+		if target == "" {
+			continue
+		}
+		if record.ID == "" {
+			noID++
+			continue
+		}
+
+		if dryRun {
+			fmt.Printf(tr("Would push to %s: %s\n"), target, formatRecord(record))
+			continue
+		}
+		id, err := service.post(record, target)
+		if err != nil {
+			// Keep what was synced so far
+			if saveErr := saveSynced(); saveErr != nil {
+				return saveErr
+			}
+			return fmt.Errorf("failed to push %s to %s: %v", formatRecord(record), target, err)
+		}
+		synced[record.ID] = id
+		pushed[recordKey(record)] = true
+		posted++
+		fmt.Printf(tr("Pushed to %s: %s\n"), target, formatRecord(record))
+	}
+
+	if noID > 0 {
+		fmt.Fprintf(os.Stderr, tr("Warning: skipped %d sessions without an id (run 'talogo migrate' to assign them)\n"), noID)
+	}
+	if dryRun {
+		return nil
+	}
+	fmt.Printf(tr("Created %d entries in %s\n"), posted, service.name)
+	return saveSynced()
+}
+
+// longestPathMatch returns the value mapped to the most specific task
+// path of titles (titles joined by "/")
+func longestPathMatch[T any](mapping map[string]T, titles []string) (T, bool) {
+	for i := len(titles); i > 0; i-- {
+		if value, ok := mapping[strings.Join(titles[:i], "/")]; ok {
+			return value, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// loadSyncedRecords reads a file mapping the ids of records synced to an
+// external service to the id they got there
+func loadSyncedRecords(path string) (map[string]string, error) {
+	synced := make(map[string]string)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return synced, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read synced records: %v", err)
+	}
+	if err := json.Unmarshal(data, &synced); err != nil {
+		return nil, fmt.Errorf("failed to parse synced records: %v", err)
+	}
+	return synced, nil
+}
+
+// saveSyncedRecords persists the synced records of loadSyncedRecords
+func saveSyncedRecords(path string, synced map[string]string) error {
+	data, err := json.MarshalIndent(synced, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode synced records: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write synced records: %v", err)
+	}
+	return nil
+}
+
+// postJSON posts body as JSON to url with the given headers, checks for a
+// 2xx status and decodes the response body into result
+func postJSON(url string, body interface{}, headers map[string]string, result interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return fmt.Errorf("failed to parse response: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,455 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+)
+
+// Record represents a single logged session
+type Record = talogo.Record
+
+// logPrecision returns the precision of the times and durations written
+// to logs, sub-second if the config asks for it
+func logPrecision() talogo.Precision {
+	if config, err := loadConfig(); err == nil && config.PreciseTimestamps {
+		return talogo.NanosecondPrecision
+	}
+	return talogo.SecondPrecision
+}
+
+// formatTimestamp formats t as stored in logs, RFC3339 with nanoseconds if
+// precise timestamps are enabled. Parsing with time.RFC3339 accepts both.
+func formatTimestamp(t time.Time) string {
+	return logPrecision().FormatTime(t)
+}
+
+// durationSeconds returns d in seconds as stored in logs, truncated to
+// whole seconds unless precise timestamps are enabled
+func durationSeconds(d time.Duration) float64 {
+	return logPrecision().Seconds(d)
+}
+
+// readRecords reads all valid records from the log file. Malformed
+// records are reported to stderr and skipped.
+func readRecords(logFile string) ([]Record, error) {
+	var records []Record
+	err := scanRecords(logFile, func(record Record) error {
+		records = append(records, record)
+		return nil
+	})
+	return records, err
+}
+
+// readRecordsStrict reads all records from the log file, failing on the
+// first malformed one. Commands rewriting the log use it so they never
+// drop lines they could not parse.
+func readRecordsStrict(logFile string) ([]Record, error) {
+	var records []Record
+	strict := func(line int, reason string) error {
+		return fmt.Errorf("invalid record on line %d: %s, fix it before rewriting the log", line, reason)
+	}
+	err := scanLog(logFile, strict, func(record Record) error {
+		records = append(records, record)
+		return nil
+	})
+	return records, err
+}
+
+// parseLogData parses data holding a log in the same backend as the log
+// location like, failing on malformed records
+func parseLogData(data []byte, like string) ([]Record, error) {
+	backend, path := parseLogLocation(like)
+	tmp, err := os.CreateTemp("", "talogo-*"+filepath.Ext(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if _, err := tmp.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to write temporary file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write temporary file: %v", err)
+	}
+	return readRecordsStrict(backend + ":" + tmp.Name())
+}
+
+// skipBadRecord reports a malformed record to stderr and skips it
+func skipBadRecord(line int, reason string) error {
+	fmt.Fprintf(os.Stderr, tr("Skipping record on line %d: %s\n"), line, reason)
+	return nil
+}
+
+// scanRecords calls fn for each valid record of the log file, in file
+// order, without loading the whole file in memory. Malformed records are
+// reported to stderr and skipped. Scanning stops at the first error
+// returned by fn.
+func scanRecords(logFile string, fn func(Record) error) error {
+	return scanLog(logFile, skipBadRecord, fn)
+}
+
+// scanLog calls fn for each valid record of the log file and onBad for
+// each malformed one
+func scanLog(logFile string, onBad talogo.BadRecordFunc, fn func(Record) error) error {
+	return openStore(logFile).List(onBad, fn)
+}
+
+// queryRecords returns the valid records of the log file starting in
+// [from, to). Malformed records are reported to stderr and skipped.
+func queryRecords(logFile string, from, to time.Time) ([]Record, error) {
+	var records []Record
+	err := openStore(logFile).Query(from, to, func(record Record) error {
+		records = append(records, record)
+		return nil
+	})
+	return records, err
+}
+
+// appendRecords appends records to the log file, creating it if it does
+// not exist, and keeps its aggregate index up to date
+func appendRecords(logFile string, records []Record) error {
+	// Keep the index up to date only if it covers the whole file
+	index, fresh := loadIndex(logFile)
+	if info, err := os.Stat(logPath(logFile)); err != nil || info.Size() == 0 {
+		dayStart, err := configuredDayStart()
+		if err != nil {
+			return err
+		}
+		index, fresh = newLogIndex(dayStart), true
+	}
+
+	if err := os.MkdirAll(filepath.Dir(logPath(logFile)), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %v", err)
+	}
+
+	if err := normalizeTitles(records); err != nil {
+		return err
+	}
+
+	// Every record written gets an id, including each part of a record
+	// split by day
+	for i := range records {
+		if records[i].ID == "" {
+			records[i].ID = talogo.NewID()
+		}
+	}
+
+	if err := openStore(logFile).Append(records); err != nil {
+		return err
+	}
+
+	if fresh {
+		for _, record := range records {
+			index.add(record)
+		}
+		saveIndex(logFile, index)
+	} else {
+		invalidateIndex(logFile)
+	}
+
+	// The records are written, failing here must not save them again
+	if checksumsEnabled() {
+		if err := appendChecksums(logFile, records); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Warning: failed to update the checksums of the log: %v\n"), err)
+		}
+	}
+	return nil
+}
+
+// rewriteRecords replaces the content of the log file with records. File
+// backends write the new content to a temporary file in the same
+// directory, synced and renamed over the log, so a crash can never leave a
+// truncated log. The previous version is kept in a .bak file next to the
+// log. The records it drops or replaces with another version are moved
+// to the trash of the log first. With checksums, a log that does not
+// match them is not rewritten, so edits made outside talogo can't be
+// hidden by rewriting it.
+func rewriteRecords(logFile string, records []Record) error {
+	return rewriteLog(logFile, records, func() error {
+		return openStore(logFile).Rewrite(records)
+	})
+}
+
+// rewriteLog replaces the content of the log file with records, written
+// by write, with the checks, trash and checksums of rewriteRecords. It
+// is for rewrites that change more than the records, like the schema of
+// the log.
+func rewriteLog(logFile string, records []Record, write func() error) error {
+	checksums := checksumsEnabled()
+	if checksums {
+		if _, exists, _ := readChecksums(logFile); exists {
+			problem, err := verifyChecksums(logFile)
+			if err != nil {
+				return err
+			}
+			if problem != "" {
+				return fmt.Errorf("the log does not match its checksums (%s), check it with 'talogo verify'", problem)
+			}
+		}
+	}
+
+	previous, err := readRecordsStrict(logFile)
+	if err != nil {
+		return err
+	}
+	if err := trashReplaced(logFile, previous, records); err != nil {
+		return fmt.Errorf("failed to move the replaced records to the trash: %v", err)
+	}
+	if err := write(); err != nil {
+		return err
+	}
+	if checksums {
+		if err := writeChecksums(logFile, records); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Warning: failed to update the checksums of the log: %v\n"), err)
+		}
+	}
+	return nil
+}
+
+// replaceLogFile atomically replaces the content of the log file with the
+// output of write, keeping the previous version in a .bak file
+func replaceLogFile(logFile string, write func(io.Writer) error) error {
+	info, err := os.Stat(logFile)
+	if err != nil {
+		return fmt.Errorf("failed to stat log file: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(logFile), "."+filepath.Base(logFile)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+	defer tmp.Close()
+
+	if err := write(tmp); err != nil {
+		return err
+	}
+
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to set permissions: %v", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to sync temporary file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %v", err)
+	}
+
+	if err := backupBeforeRewrite(logFile); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), logFile); err != nil {
+		return fmt.Errorf("failed to replace log file: %v", err)
+	}
+
+	invalidateIndex(logFile)
+	return nil
+}
+
+// backupBeforeRewrite copies the log file to its .bak file, and to the
+// backups directory if automatic backups are enabled
+func backupBeforeRewrite(logFile string) error {
+	if err := copyFile(logFile, logFile+".bak"); err != nil {
+		return fmt.Errorf("failed to back up log file: %v", err)
+	}
+	if config, err := loadConfig(); err == nil && config.Backup.Auto {
+		if _, err := backupLog(logFile, config.Backup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyFile copies src to dst, syncing dst to disk
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// withOrigin returns record with the host and user it is tracked by, as
+// enabled in the config
+func withOrigin(record Record) Record {
+	config, err := loadConfig()
+	if err != nil {
+		return record
+	}
+	if config.RecordHost && record.Host == "" {
+		record.Host, _ = os.Hostname()
+	}
+	if config.RecordUser && record.User == "" {
+		if u, err := user.Current(); err == nil {
+			record.User = u.Username
+		}
+	}
+	return record
+}
+
+// checkMinSession returns whether a session lasting elapsed is written to
+// the log, by min_session in the config, and the tag to add to its
+// records when it is short and short_session_tag is set
+func checkMinSession(elapsed time.Duration) (bool, string, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return false, "", err
+	}
+	shortest, err := config.minSession()
+	if err != nil {
+		return false, "", err
+	}
+	if elapsed >= shortest {
+		return true, "", nil
+	}
+	if config.ShortSessionTag != "" {
+		return true, config.ShortSessionTag, nil
+	}
+	return false, "", nil
+}
+
+// roundSession returns the records of a session, in order, with their
+// times rounded to the blocks of rounding in the config: starts down and
+// ends up. Records left overlapping the previous one, like pauses shorter
+// than a block, start where it ends, and those left empty are dropped.
+func roundSession(records []Record) ([]Record, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	block, err := config.rounding()
+	if err != nil || block == 0 {
+		return records, err
+	}
+
+	var rounded []Record
+	for _, record := range records {
+		record.Start = roundDown(record.Start, block)
+		if end := roundDown(record.End, block); end.Before(record.End) {
+			record.End = end.Add(block)
+		}
+		if n := len(rounded); n > 0 && record.Start.Before(rounded[n-1].End) {
+			record.Start = rounded[n-1].End
+		}
+		if !record.End.After(record.Start) {
+			continue
+		}
+		record.Elapsed = record.End.Sub(record.Start)
+		rounded = append(rounded, record)
+	}
+	return rounded, nil
+}
+
+// roundDown returns the start of the block of t, for blocks of the given
+// length counted from midnight on the wall clock
+func roundDown(t time.Time, block time.Duration) time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return midnight.Add(t.Sub(midnight).Truncate(block))
+}
+
+// withTag returns tags with tag added, unless already in them. tags is
+// not modified.
+func withTag(tags []string, tag string) []string {
+	for _, t := range tags {
+		if t == tag {
+			return tags
+		}
+	}
+	return append(append([]string{}, tags...), tag)
+}
+
+// withoutTag returns tags without tag. tags is not modified.
+func withoutTag(tags []string, tag string) []string {
+	var kept []string
+	for _, t := range tags {
+		if t != tag {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// recordsBetween returns the records whose day is between the from and to
+// dates (YYYY-MM-DD, inclusive). An empty date leaves that end open.
+func recordsBetween(records []Record, from, to string) ([]Record, error) {
+	if from == "" && to == "" {
+		return records, nil
+	}
+	for _, date := range []string{from, to} {
+		if _, err := time.Parse("2006-01-02", date); date != "" && err != nil {
+			return nil, fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", date)
+		}
+	}
+	dayStart, err := configuredDayStart()
+	if err != nil {
+		return nil, err
+	}
+
+	var between []Record
+	for _, record := range records {
+		day := talogo.DayOf(record.Start, dayStart)
+		if (from != "" && day < from) || (to != "" && day > to) {
+			continue
+		}
+		between = append(between, record)
+	}
+	return between, nil
+}
+
+// findRecord returns the index of the record with the given id, or of the
+// only record whose id starts with it, or -1 if there is none
+func findRecord(records []Record, id string) (int, error) {
+	found := -1
+	for i, record := range records {
+		if record.ID == id {
+			return i, nil
+		}
+		if id != "" && strings.HasPrefix(record.ID, id) {
+			if found >= 0 {
+				return -1, fmt.Errorf("id %s is ambiguous", id)
+			}
+			found = i
+		}
+	}
+	if found < 0 {
+		return -1, fmt.Errorf("no record with id %s", id)
+	}
+	return found, nil
+}
+
+// recordsIn returns a copy of records with their timestamps converted to
+// loc. A nil loc returns records unchanged.
+func recordsIn(records []Record, loc *time.Location) []Record {
+	if loc == nil {
+		return records
+	}
+	converted := make([]Record, len(records))
+	for i, record := range records {
+		record.Start = record.Start.In(loc)
+		record.End = record.End.In(loc)
+		converted[i] = record
+	}
+	return converted
+}
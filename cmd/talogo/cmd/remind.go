@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	remindCmdWorkHours string
+	remindCmdEvery     time.Duration
+	remindCmdWeekends  bool
+)
+
+// remindCmd defines the remind subcommand
+var remindCmd = &cobra.Command{
+	Use:   "remind",
+	Short: "Keep running, notifying when nothing is tracked during working hours",
+	Long: `Keep running in the background, sending a desktop notification every
+--every while no session is running during the working hours, so gaps
+are noticed while they are still easy to log. Paused sessions count as
+tracked, and weekends and the vacations of the config are left alone.
+
+Start it with the desktop session, e.g. from a systemd user unit or with:
+
+  talogo remind --workhours 09:00-18:00 --every 20m &`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		from, to, err := parseWorkHours(remindCmdWorkHours)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		if remindCmdEvery <= 0 {
+			fmt.Fprintf(os.Stderr, tr("Error: invalid --every %s (expected a positive duration such as 20m)\n"), remindCmdEvery)
+			os.Exit(1)
+		}
+		config, err := loadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+
+		for now := range time.Tick(remindCmdEvery) {
+			if !shouldRemind(config, now, from, to, remindCmdWeekends) {
+				continue
+			}
+			if err := notify("talogo", tr("Nothing is being tracked. Start a session with talogo log.")); err != nil {
+				fmt.Fprintf(os.Stderr, tr("Warning: failed to send notification: %v\n"), err)
+			}
+		}
+	},
+}
+
+func init() {
+	remindCmd.Flags().StringVar(&remindCmdWorkHours, "workhours", "09:00-18:00", "Working hours to remind in, e.g. 08:30-17:00")
+	remindCmd.Flags().DurationVar(&remindCmdEvery, "every", 20*time.Minute, "Time between reminders while nothing is tracked")
+	remindCmd.Flags().BoolVar(&remindCmdWeekends, "weekends", false, "Remind on Saturdays and Sundays too")
+	rootCmd.AddCommand(remindCmd)
+}
+
+// parseWorkHours parses working hours such as 09:00-18:00 into their
+// start and end as times of the day. The end may be past midnight, as in
+// 22:00-06:00.
+func parseWorkHours(value string) (time.Duration, time.Duration, error) {
+	start, end, ok := strings.Cut(value, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid working hours %q (expected e.g. 09:00-18:00)", value)
+	}
+	var clocks [2]time.Duration
+	for i, clock := range []string{start, end} {
+		parsed := false
+		for _, layout := range clockLayouts {
+			if t, err := time.Parse(layout, strings.ToLower(strings.ReplaceAll(clock, " ", ""))); err == nil {
+				clocks[i] = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+				parsed = true
+				break
+			}
+		}
+		if !parsed {
+			return 0, 0, fmt.Errorf("invalid working hours %q (expected e.g. 09:00-18:00)", value)
+		}
+	}
+	if clocks[0] == clocks[1] {
+		return 0, 0, fmt.Errorf("invalid working hours %q: they start and end at the same time", value)
+	}
+	return clocks[0], clocks[1], nil
+}
+
+// shouldRemind reports whether nothing is tracked at now, in the working
+// hours from to to of a working day
+func shouldRemind(config *Config, now time.Time, from, to time.Duration, weekends bool) bool {
+	clock := now.Sub(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
+	working := clock >= from && clock < to
+	if from > to {
+		working = clock >= from || clock < to
+	}
+	if !working {
+		return false
+	}
+	if weekday := now.Weekday(); !weekends && (weekday == time.Saturday || weekday == time.Sunday) {
+		return false
+	}
+	if vacation, err := config.isVacation(now); err != nil || vacation {
+		return false
+	}
+	_, running := readSessionState()
+	return !running
+}
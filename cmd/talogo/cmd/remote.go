@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// remoteStore is a remote copy of the log file
+type remoteStore interface {
+	// Get returns the content of the remote log and when it was last
+	// modified. exists is false if there is no remote log yet.
+	Get() (data []byte, modTime time.Time, exists bool, err error)
+	// Put replaces the content of the remote log
+	Put(data []byte) error
+}
+
+// newRemoteStore returns the remote store configured in config
+func newRemoteStore(config RemoteConfig) (remoteStore, error) {
+	u, err := url.Parse(config.URL)
+	if err != nil || config.URL == "" {
+		return nil, fmt.Errorf("invalid or missing remote.url in config: %q", config.URL)
+	}
+
+	switch u.Scheme {
+	case "s3":
+		accessKey := config.AccessKeyID
+		if accessKey == "" {
+			accessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+		}
+		secretKey := config.SecretAccessKey
+		if secretKey == "" {
+			secretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		}
+		region := config.Region
+		if region == "" {
+			region = "us-east-1"
+		}
+
+		// Path style for custom endpoints, virtual hosted style for AWS
+		key := strings.TrimPrefix(u.Path, "/")
+		objectURL := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", u.Host, region, key)
+		if config.Endpoint != "" {
+			objectURL = fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(config.Endpoint, "/"), u.Host, key)
+		}
+		return &s3Store{
+			url:       objectURL,
+			region:    region,
+			accessKey: accessKey,
+			secretKey: secretKey,
+		}, nil
+	case "http", "https":
+		return &webDAVStore{
+			url:      config.URL,
+			username: config.Username,
+			password: config.Password,
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported remote scheme %q (expected s3, http or https)", u.Scheme)
+}
+
+// webDAVStore keeps the log in a WebDAV server
+type webDAVStore struct {
+	url      string
+	username string
+	password string
+}
+
+func (s *webDAVStore) request(method string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, s.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if s.username != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+	return http.DefaultClient.Do(req)
+}
+
+func (s *webDAVStore) Get() ([]byte, time.Time, bool, error) {
+	resp, err := s.request(http.MethodGet, nil)
+	if err != nil {
+		return nil, time.Time{}, false, fmt.Errorf("failed to download remote log: %v", err)
+	}
+	return readRemoteResponse(resp)
+}
+
+func (s *webDAVStore) Put(data []byte) error {
+	resp, err := s.request(http.MethodPut, data)
+	if err != nil {
+		return fmt.Errorf("failed to upload remote log: %v", err)
+	}
+	return checkUploadResponse(resp)
+}
+
+// s3Store keeps the log as an S3 object
+type s3Store struct {
+	url       string
+	region    string
+	accessKey string
+	secretKey string
+}
+
+func (s *s3Store) request(method string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, s.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, body, time.Now().UTC())
+	return http.DefaultClient.Do(req)
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to req
+func (s *s3Store) sign(req *http.Request, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+func (s *s3Store) Get() ([]byte, time.Time, bool, error) {
+	resp, err := s.request(http.MethodGet, nil)
+	if err != nil {
+		return nil, time.Time{}, false, fmt.Errorf("failed to download remote log: %v", err)
+	}
+	return readRemoteResponse(resp)
+}
+
+func (s *s3Store) Put(data []byte) error {
+	resp, err := s.request(http.MethodPut, data)
+	if err != nil {
+		return fmt.Errorf("failed to upload remote log: %v", err)
+	}
+	return checkUploadResponse(resp)
+}
+
+// readRemoteResponse returns the body and modification time of a download
+// response. A 404 response means the remote log does not exist yet.
+func readRemoteResponse(resp *http.Response) ([]byte, time.Time, bool, error) {
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, time.Time{}, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, time.Time{}, false, fmt.Errorf("failed to download remote log: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, time.Time{}, false, fmt.Errorf("failed to download remote log: %v", err)
+	}
+	modTime, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return data, modTime, true, nil
+}
+
+// checkUploadResponse returns an error if an upload was not accepted
+func checkUploadResponse(resp *http.Response) error {
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("failed to upload remote log: %s %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	renameCmdLogFile string
+)
+
+// renameCmd defines the rename subcommand
+var renameCmd = &cobra.Command{
+	Use:   "rename OLD NEW",
+	Short: "Rename a task and its subtasks in every record, e.g. 'rename work/emials work/emails'",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		count, err := renameTask(renameCmdLogFile, args[0], args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error renaming task: %v\n"), err)
+			os.Exit(1)
+		}
+		printInfo("Renamed %d records\n", count)
+	},
+}
+
+func init() {
+	renameCmd.Flags().StringVarP(&renameCmdLogFile, "file", "f", defaultLogFile(), "Log file to rewrite")
+	rootCmd.AddCommand(renameCmd)
+}
+
+// renameTask replaces the task path from (titles joined by "/") by to in
+// every record logged under it, and returns the number of records changed
+func renameTask(logFile, from, to string) (int, error) {
+	fromTitles := strings.Split(from, "/")
+	toTitles, err := checkTitles(strings.Split(to, "/"))
+	if err != nil {
+		return 0, err
+	}
+
+	records, err := readRecordsStrict(logFile)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i, record := range records {
+		if !record.MatchesTask([]string{from}) {
+			continue
+		}
+		titles := append([]string{}, toTitles...)
+		records[i].Titles = append(titles, record.Titles[len(fromTitles):]...)
+		count++
+	}
+
+	if count == 0 {
+		return 0, nil
+	}
+	return count, rewriteRecords(logFile, records)
+}
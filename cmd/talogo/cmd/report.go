@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportCmdLogFile  string
+	reportCmdFrom     string
+	reportCmdTo       string
+	reportCmdFormat   string
+	reportCmdEmail    bool
+	reportCmdMailTo   []string
+	reportCmdTmpl     string
+	reportCmdCoalesce time.Duration
+	reportCmdMatrix   string
+	reportCmdDepth    int
+	reportCmdLocale   string
+	reportCmdOverlaps bool
+	reportCmdUnbilled bool
+	reportCmdMarkBill bool
+)
+
+// reportCmd defines the report subcommand
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Print or email a summary of a period, by default the current week",
+	Long: `Print a summary of the time tracked between --from and --to, as Markdown
+or HTML. By default the period is the current week, from its first day
+(week_start in the config, Monday by default) to today.
+
+With --email the report is sent through the SMTP server of the config file
+to the smtp.to addresses, or those given with --mail-to, with the sessions
+of the period attached as CSV. For example, to email the weekly report every
+Friday evening from cron:
+
+  0 18 * * 5 talogo report --email
+
+With --template the report is rendered with a Go text/template file
+instead, for layouts such as a corporate timesheet. The template gets the
+report with .From, .To, .Days (date -> day), .Total and .Records; days and
+tasks have .Name, .TotalTime, .Duration and .Children. Besides the
+built-in functions it can use dates, tasks, hours, join and json:
+
+  {{range dates .Days}}{{.}};{{printf "%.2f" (hours (index $.Days .).TotalTime)}}
+  {{end}}
+
+With --matrix month the report is a table of the month of --from, by
+default the current one, with tasks as rows and its weeks as columns, the
+layout of many timesheet forms. Weeks begin on the configured week_start
+and are labeled with their ISO week number. --matrix-depth sets how many
+levels of titles the rows keep, e.g. 2 for work/mail.
+
+With --locale, or locale in the config, day headers are long dates in
+that language and hours use its decimal separator, e.g. "Lunes 6 de mayo:
+7,50 hs" for es. Templates can use the same formatting with
+{{$.Locale.Date .}} and {{$.Locale.Hours .TotalTime}}.
+
+Sessions logged with 'log --allow-overlap' count for their tasks, but
+the time they share with other sessions counts once in the day totals
+and the total of the period. --count-overlaps counts it twice instead.
+
+To invoice the same hours once, --unbilled leaves out the sessions marked
+as billed and --mark-billed marks those of the period as billed once the
+report is printed or sent, see 'talogo mark'.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		from, to, err := reportDateRange(reportCmdFrom, reportCmdTo)
+		if reportCmdMatrix != "" {
+			from, to, err = matrixDateRange(reportCmdMatrix, reportCmdFrom, reportCmdTo)
+		}
+		if err == nil && reportCmdMatrix != "" && reportCmdTmpl != "" {
+			err = fmt.Errorf("--template cannot be combined with --matrix")
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error generating report: %v\n"), err)
+			os.Exit(1)
+		}
+		report, err := buildReport(reportCmdLogFile, from, to, reportCmdCoalesce, reportCmdOverlaps, reportCmdUnbilled)
+		if err == nil {
+			report.Locale, err = reportLocale(reportCmdLocale)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error generating report: %v\n"), err)
+			os.Exit(1)
+		}
+
+		if reportCmdTmpl != "" {
+			if reportCmdEmail {
+				fmt.Fprintln(os.Stderr, tr("Error generating report: --template cannot be combined with --email"))
+				os.Exit(1)
+			}
+			if err := renderReportTemplate(os.Stdout, reportCmdTmpl, report); err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error generating report: %v\n"), err)
+				os.Exit(1)
+			}
+			markReportBilled(from, to)
+			return
+		}
+
+		markdown, html := report.Markdown, report.HTML
+		if reportCmdMatrix != "" {
+			weekStart, err := configuredWeekStart()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error generating report: %v\n"), err)
+				os.Exit(1)
+			}
+			matrix := report.Matrix(reportCmdDepth, weekStart)
+			markdown = func() string { return matrix.Markdown(report.Title()) }
+			html = func() string { return matrix.HTML(report.Title()) }
+		}
+
+		if !reportCmdEmail {
+			switch reportCmdFormat {
+			case "markdown":
+				fmt.Print(markdown())
+			case "html":
+				fmt.Print(html())
+			default:
+				fmt.Fprintf(os.Stderr, tr("Error generating report: unknown format %q (expected markdown or html)\n"), reportCmdFormat)
+				os.Exit(1)
+			}
+			markReportBilled(from, to)
+			return
+		}
+
+		config, err := loadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error sending report: %v\n"), err)
+			os.Exit(1)
+		}
+		recipients := config.SMTP.To
+		if len(reportCmdMailTo) > 0 {
+			recipients = reportCmdMailTo
+		}
+		if err := emailReport(config.SMTP, recipients, report, markdown(), html()); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error sending report: %v\n"), err)
+			os.Exit(1)
+		}
+		fmt.Printf(tr("Report sent to %s\n"), strings.Join(recipients, ", "))
+		markReportBilled(from, to)
+	},
+}
+
+func init() {
+	reportCmd.Flags().StringVarP(&reportCmdLogFile, "file", "f", defaultLogFile(), "Log file to read")
+	reportCmd.Flags().StringVar(&reportCmdFrom, "from", "", "First day of the report (YYYY-MM-DD, default first day of this week)")
+	reportCmd.Flags().StringVar(&reportCmdTo, "to", "", "Last day of the report (YYYY-MM-DD, default today)")
+	reportCmd.Flags().StringVar(&reportCmdFormat, "format", "markdown", "Output format (markdown, html)")
+	reportCmd.Flags().BoolVar(&reportCmdEmail, "email", false, "Email the report instead of printing it")
+	reportCmd.Flags().StringSliceVar(&reportCmdMailTo, "mail-to", nil, "Recipients of the email, instead of smtp.to")
+	reportCmd.Flags().StringVar(&reportCmdTmpl, "template", "", "Render the report with this Go text/template file instead of --format")
+	reportCmd.Flags().DurationVar(&reportCmdCoalesce, "coalesce", 0, "Merge back-to-back sessions of the same task separated by less than this (e.g. 5m)")
+	reportCmd.Flags().StringVar(&reportCmdMatrix, "matrix", "", "Print a table of tasks by week for a period (month)")
+	reportCmd.Flags().IntVar(&reportCmdDepth, "matrix-depth", 1, "Levels of titles of the rows of --matrix")
+	reportCmd.Flags().BoolVar(&reportCmdOverlaps, "count-overlaps", false, "Count the time parallel sessions share with others twice in day totals")
+	reportCmd.Flags().BoolVar(&reportCmdUnbilled, "unbilled", false, "Leave out the sessions marked as billed")
+	reportCmd.Flags().BoolVar(&reportCmdMarkBill, "mark-billed", false, "Mark the sessions of the period as billed after printing or sending the report")
+	reportCmd.Flags().StringVar(&reportCmdLocale, "locale", "", "Language of dates and decimal separator (e.g. es, pt_BR), instead of locale in the config")
+	rootCmd.AddCommand(reportCmd)
+}
+
+// reportDateRange validates the from and to dates of a report, which
+// default to the first day of the current week and today
+func reportDateRange(from, to string) (string, string, error) {
+	if from == "" {
+		weekStart, err := configuredWeekStart()
+		if err != nil {
+			return "", "", err
+		}
+		from = talogo.WeekStartOf(time.Now(), weekStart).Format("2006-01-02")
+	}
+	return syncDateRange(from, to)
+}
+
+// matrixDateRange returns the first and last days of the matrix report of
+// the given period containing the day from, by default today
+func matrixDateRange(period, from, to string) (string, string, error) {
+	if period != "month" {
+		return "", "", fmt.Errorf("unknown matrix %q (expected month)", period)
+	}
+	if to != "" {
+		return "", "", fmt.Errorf("--to cannot be combined with --matrix, the report covers the month of --from")
+	}
+	day := time.Now()
+	if from != "" {
+		var err error
+		if day, err = time.Parse("2006-01-02", from); err != nil {
+			return "", "", fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", from)
+		}
+	}
+	first := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1)
+	return first.Format("2006-01-02"), last.Format("2006-01-02"), nil
+}
+
+// markReportBilled marks the sessions of the days between from and to as
+// billed if the report was asked to. The report is out by then, so
+// failing to mark them is a warning.
+func markReportBilled(from, to string) {
+	if !reportCmdMarkBill {
+		return
+	}
+	selected, err := selectMarkedRecords(reportCmdLogFile, nil, from, to, nil)
+	var count int
+	if err == nil {
+		count, err = markRecords(reportCmdLogFile, billedTag, selected, false)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("Warning: failed to mark the sessions of the report as billed: %v\n"), err)
+		return
+	}
+	// The report may be going to stdout
+	if !quiet {
+		fmt.Fprintf(os.Stderr, tr("Marked %d sessions as %s\n"), count, billedTag)
+	}
+}
+
+// buildReport aggregates the records of the log file between the from and
+// to dates (inclusive), merging sessions separated by less than coalesce.
+// The time parallel records share with others counts once in day totals,
+// unless countOverlaps. With unbilled, records marked as billed are left
+// out.
+func buildReport(logFile, from, to string, coalesce time.Duration, countOverlaps, unbilled bool) (*talogo.Report, error) {
+	dayStart, err := configuredDayStart()
+	if err != nil {
+		return nil, err
+	}
+	records, err := queryDays(logFile, from, to, dayStart)
+	if err != nil {
+		return nil, err
+	}
+	if unbilled {
+		records = unbilledRecords(records)
+	}
+	if records, err = coalesceRecords(records, coalesce); err != nil {
+		return nil, err
+	}
+	report := talogo.NewReport(records, from, to, talogo.SummaryOptions{DayStart: dayStart})
+	if !countOverlaps {
+		discountOverlaps(report.Days, report.Total, parallelOverlaps(report.Records, dayStart))
+	}
+	return report, nil
+}
+
+// emailReport sends the report to recipients, with the markdown and html
+// bodies, and the records of the period attached as CSV
+func emailReport(config SMTPConfig, recipients []string, report *talogo.Report, markdown, html string) error {
+	var csvData bytes.Buffer
+	if err := talogo.WriteCSV(&csvData, report.Records, ',', logPrecision()); err != nil {
+		return err
+	}
+	attachment := mailAttachment{
+		Name:        fmt.Sprintf("talogo-%s-%s.csv", report.From, report.To),
+		ContentType: "text/csv; charset=utf-8",
+		Data:        csvData.Bytes(),
+	}
+	return sendMail(config, recipients, report.Title(), markdown, html, []mailAttachment{attachment})
+}
+
+// reportTemplateFuncs are the functions available to report templates
+var reportTemplateFuncs = template.FuncMap{
+	// dates returns the dates of the days of the report, sorted
+	"dates": talogo.SortedDates,
+	// tasks returns the children of a node sorted by name
+	"tasks": func(tasks map[string]*TaskNode) []*TaskNode {
+		var sorted []*TaskNode
+		for _, name := range talogo.SortedTaskNames(tasks) {
+			sorted = append(sorted, tasks[name])
+		}
+		return sorted
+	},
+	// hours converts a duration to hours, e.g. {{printf "%.2f" (hours .TotalTime)}}
+	"hours": func(d time.Duration) float64 {
+		return d.Hours()
+	},
+	"join": strings.Join,
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// renderReportTemplate executes the template file at path with report
+// and writes the result to w
+func renderReportTemplate(w io.Writer, path string, report *talogo.Report) error {
+	tmpl, err := template.New(filepath.Base(path)).Funcs(reportTemplateFuncs).ParseFiles(expandHome(path))
+	if err != nil {
+		return fmt.Errorf("invalid template: %v", err)
+	}
+	if err := tmpl.Execute(w, report); err != nil {
+		return fmt.Errorf("failed to execute template: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+)
+
+// Reporter renders the summary of a period, the task tree of each day
+// keyed by date, in an output format. Adding a format only takes a
+// Reporter registered in reporters.
+type Reporter interface {
+	Report(w io.Writer, days map[string]*TaskNode, opts summaryOptions) error
+}
+
+// reporters maps the names accepted by --output to their reporter
+var reporters = map[string]Reporter{
+	"text":     textReporter{},
+	"json":     jsonReporter{},
+	"csv":      csvReporter{},
+	"markdown": markdownReporter{},
+}
+
+// reporterFor returns the reporter of the named output format
+func reporterFor(name string) (Reporter, error) {
+	reporter, ok := reporters[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format %q (expected %s)", name, strings.Join(reporterNames(), ", "))
+	}
+	return reporter, nil
+}
+
+// reporterNames returns the names of the output formats, sorted
+func reporterNames() []string {
+	var names []string
+	for name := range reporters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// textReporter prints each day with its tasks indented under it
+type textReporter struct{}
+
+func (textReporter) Report(w io.Writer, days map[string]*TaskNode, opts summaryOptions) error {
+	var periodHours, periodEarnings float64
+	for _, date := range talogo.SortedDates(days) {
+		day := days[date]
+		periodHours += day.TotalTime.Hours()
+		periodEarnings += day.Earnings
+
+		fmt.Fprintf(w, "Date: %s\n", date)
+		fmt.Fprintf(w, "Total: %s\n", formatAmount(day.TotalTime.Hours(), day.Earnings, opts))
+		if opts.DailyGoal > 0 {
+			fmt.Fprintf(w, tr("Goal: %.2f hs (%.0f%%)\n"), opts.DailyGoal.Hours(), 100*day.TotalTime.Hours()/opts.DailyGoal.Hours())
+		}
+		printSubtasks(w, day.Children, 2, opts)
+		fmt.Fprintln(w)
+	}
+
+	if opts.Earnings {
+		fmt.Fprintf(w, "Period total: %s\n", formatAmount(periodHours, periodEarnings, opts))
+	}
+	return nil
+}
+
+// printSubtasks recursively prints subtasks with indentation
+func printSubtasks(w io.Writer, tasks map[string]*TaskNode, indent int, opts summaryOptions) {
+	for _, taskName := range talogo.SortedTaskNames(tasks) {
+		task := tasks[taskName]
+		fmt.Fprintf(w, "%s%s: %s\n", strings.Repeat(" ", indent), taskName, formatAmount(task.TotalTime.Hours(), task.Earnings, opts))
+		printSubtasks(w, task.Children, indent+2, opts)
+	}
+}
+
+// formatAmount formats hours, followed by the earnings when requested
+func formatAmount(hours, earnings float64, opts summaryOptions) string {
+	if !opts.Earnings {
+		return fmt.Sprintf("%.2f hs", hours)
+	}
+	return fmt.Sprintf("%.2f hs (%s%.2f)", hours, opts.Config.Currency, earnings)
+}
+
+// jsonReporter prints the days as a JSON array, in the format of the
+// summary endpoint of the HTTP API
+type jsonReporter struct{}
+
+func (jsonReporter) Report(w io.Writer, days map[string]*TaskNode, opts summaryOptions) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(apiSummary(days, opts))
+}
+
+// csvReporter prints one row per day and task path, with the time of the
+// task including its subtasks
+type csvReporter struct{}
+
+func (csvReporter) Report(w io.Writer, days map[string]*TaskNode, opts summaryOptions) error {
+	writer := csv.NewWriter(w)
+	header := []string{"date", "task", "hours"}
+	if opts.Earnings {
+		header = append(header, "earnings")
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	var writeTasks func(date string, tasks map[string]*TaskNode, prefix string) error
+	writeTasks = func(date string, tasks map[string]*TaskNode, prefix string) error {
+		for _, name := range talogo.SortedTaskNames(tasks) {
+			task := tasks[name]
+			row := []string{date, prefix + name, strconv.FormatFloat(task.TotalTime.Hours(), 'f', 2, 64)}
+			if opts.Earnings {
+				row = append(row, strconv.FormatFloat(task.Earnings, 'f', 2, 64))
+			}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+			if err := writeTasks(date, task.Children, prefix+name+"/"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, date := range talogo.SortedDates(days) {
+		if err := writeTasks(date, days[date].Children, ""); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// markdownReporter prints a section per day with its tasks as a nested
+// list
+type markdownReporter struct{}
+
+func (markdownReporter) Report(w io.Writer, days map[string]*TaskNode, opts summaryOptions) error {
+	var writeTasks func(tasks map[string]*TaskNode, depth int)
+	writeTasks = func(tasks map[string]*TaskNode, depth int) {
+		for _, name := range talogo.SortedTaskNames(tasks) {
+			task := tasks[name]
+			fmt.Fprintf(w, "%s- %s: %s\n", strings.Repeat("  ", depth), name, formatAmount(task.TotalTime.Hours(), task.Earnings, opts))
+			writeTasks(task.Children, depth+1)
+		}
+	}
+
+	for i, date := range talogo.SortedDates(days) {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		day := days[date]
+		fmt.Fprintf(w, "## %s\n\n**Total: %s**\n\n", date, formatAmount(day.TotalTime.Hours(), day.Earnings, opts))
+		writeTasks(day.Children, 0)
+	}
+	return nil
+}
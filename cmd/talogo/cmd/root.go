@@ -3,18 +3,135 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "talogo",
 	Short: "talogo is a simple tasks time tracker utility and logger",
+	Long: `talogo is a simple tasks time tracker utility and logger.
+
+Exit codes:
+  0  success
+  1  error, such as an invalid flag or an unreadable log
+  2  talogo status: no session is running
+  3  talogo doctor, verify: some check found problems
+  4  talogo diff: the logs hold different sessions`,
+	// Flags not given on the command line take their value from the
+	// environment, a .talogo.toml file, the active project or the config
+	// file, if set there
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		runningCommand = strings.Join(strings.Fields(cmd.CommandPath())[1:], " ")
+		if err := applyFlagDefaults(cmd); err != nil {
+			return err
+		}
+		if err := applyTimeFormat(); err != nil {
+			return err
+		}
+		return checkOutputFormat(cmd)
+	},
 }
 
+// envPrefix prefixes the environment variables overriding flags, e.g.
+// TALOGO_FILE for --file or TALOGO_TZ for --tz
+const envPrefix = "TALOGO_"
+
 func Execute() {
+	addPluginCommands()
+	// A broken config is reported when the command applies its defaults
+	language := ""
+	if config, err := loadConfig(); err == nil {
+		language = config.Language
+	}
+	setLanguage(language)
+	translateCommands(rootCmd)
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
+
+// envVarName returns the environment variable overriding the named flag
+func envVarName(flag string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// applyFlagDefaults sets the flags of cmd that were not given on the
+// command line from, in order of precedence, TALOGO_<FLAG> environment
+// variables, the .talogo.toml file of the working directory, the log file
+// of the active project and the defaults found in the config file
+func applyFlagDefaults(cmd *cobra.Command) error {
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	_, project, err := activeProject(config)
+	if err != nil {
+		return err
+	}
+	local, err := loadLocalConfig()
+	if err != nil {
+		return err
+	}
+	if local.File != "" {
+		project.File = local.File
+	}
+
+	// Command path without the root command, e.g. ["config", "get"]
+	path := strings.Fields(cmd.CommandPath())[1:]
+
+	var applyErr error
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed || applyErr != nil {
+			return
+		}
+
+		if env, ok := os.LookupEnv(envVarName(f.Name)); ok {
+			if err := f.Value.Set(env); err != nil {
+				applyErr = fmt.Errorf("invalid value for %s: %v", envVarName(f.Name), err)
+			}
+			return
+		}
+
+		if f.Name == "file" && project.File != "" {
+			if err := f.Value.Set(expandHome(project.File)); err != nil {
+				applyErr = fmt.Errorf("invalid project log file: %v", err)
+			}
+			return
+		}
+
+		if f.Name == "tag" && len(local.Tags) > 0 {
+			for _, tag := range local.Tags {
+				if err := f.Value.Set(tag); err != nil {
+					applyErr = fmt.Errorf("invalid tag in %s: %v", localConfigName, err)
+					return
+				}
+			}
+			return
+		}
+
+		value, ok := config.flagDefault(path, f.Name)
+		if !ok {
+			return
+		}
+
+		values := []interface{}{value}
+		if list, isList := value.([]interface{}); isList {
+			values = list
+		}
+		for _, v := range values {
+			s := fmt.Sprint(v)
+			if str, isString := v.(string); isString {
+				s = expandHome(str)
+			}
+			if err := f.Value.Set(s); err != nil {
+				applyErr = fmt.Errorf("invalid config default for --%s: %v", f.Name, err)
+				return
+			}
+		}
+	})
+	return applyErr
+}
@@ -0,0 +1,425 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveCmdLogFile string
+	serveCmdAddr    string
+	serveCmdToken   string
+)
+
+// serveCmd defines the serve subcommand
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve an HTTP API to start and stop sessions and query the log",
+	Long: `Serve an HTTP API to start and stop sessions and query the log.
+
+Every request must carry the token in an 'Authorization: Bearer TOKEN'
+header. If no token is given with --token or TALOGO_TOKEN, a random one is
+generated and printed on startup.
+
+Endpoints:
+  POST /sessions/start   start a session, body {"titles": [...], "tags": [...], "notes": "..."}
+  POST /sessions/stop    stop the running session and log it, returning the records
+                         written (none if shorter than min_session)
+  POST /sessions/cancel  discard the running session
+  GET  /status           the running session, if any
+  GET  /entries          logged sessions (?date=YYYY-MM-DD&host=H&last=N)
+  GET  /summary          daily task totals (?by=task|tag&tz=ZONE&source=S&host=H&earnings=true)
+  GET  /metrics          Prometheus metrics of the running session and the log`,
+	Run: func(cmd *cobra.Command, args []string) {
+		token := serveCmdToken
+		if token == "" {
+			buf := make([]byte, 16)
+			if _, err := rand.Read(buf); err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error generating token: %v\n"), err)
+				os.Exit(1)
+			}
+			token = hex.EncodeToString(buf)
+			fmt.Printf(tr("Generated token: %s\n"), token)
+		}
+
+		server := &apiServer{logFile: serveCmdLogFile, token: token}
+		fmt.Printf(tr("Serving %s on http://%s\n"), serveCmdLogFile, serveCmdAddr)
+		if err := http.ListenAndServe(serveCmdAddr, server.handler()); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error serving API: %v\n"), err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVarP(&serveCmdLogFile, "file", "f", defaultLogFile(), "Log file to read and write")
+	serveCmd.Flags().StringVar(&serveCmdAddr, "addr", "127.0.0.1:8787", "Address to listen on")
+	serveCmd.Flags().StringVar(&serveCmdToken, "token", "", "Token clients must send as a bearer token (random if empty)")
+	rootCmd.AddCommand(serveCmd)
+}
+
+// apiServer serves the HTTP API of a log file. It holds at most one
+// running session, which is only written to the log when stopped.
+type apiServer struct {
+	logFile string
+	token   string
+
+	mu      sync.Mutex
+	session *apiSession
+}
+
+// apiSession is a session started through the API
+type apiSession struct {
+	Titles []string  `json:"titles"`
+	Tags   []string  `json:"tags,omitempty"`
+	Notes  string    `json:"notes,omitempty"`
+	Start  time.Time `json:"start_time"`
+}
+
+// apiStatus is the response of GET /status, and the output of status
+// with --output json
+type apiStatus struct {
+	Running        bool        `json:"running"`
+	Session        *apiSession `json:"session,omitempty"`
+	ElapsedSeconds float64     `json:"elapsed_seconds,omitempty"`
+	Paused         bool        `json:"paused,omitempty"`
+	LogFile        string      `json:"log_file,omitempty"`
+	Sessions       []apiStatus `json:"sessions,omitempty"` // Every running session, from status
+}
+
+// apiDay is a day of the response of GET /summary
+type apiDay struct {
+	Date     string     `json:"date"`
+	Hours    float64    `json:"hours"`
+	Earnings *float64   `json:"earnings,omitempty"`
+	Tasks    []*apiTask `json:"tasks"`
+}
+
+// apiTask is a task of a summary day, with its subtasks
+type apiTask struct {
+	Name     string     `json:"name"`
+	Hours    float64    `json:"hours"`
+	Earnings *float64   `json:"earnings,omitempty"`
+	Tasks    []*apiTask `json:"tasks,omitempty"`
+}
+
+// handler returns the routes of the API behind token authentication
+func (s *apiServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sessions/start", s.handleStart)
+	mux.HandleFunc("POST /sessions/stop", s.handleStop)
+	mux.HandleFunc("POST /sessions/cancel", s.handleCancel)
+	mux.HandleFunc("GET /status", s.handleStatus)
+	mux.HandleFunc("GET /entries", s.handleEntries)
+	mux.HandleFunc("GET /summary", s.handleSummary)
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeAPIError(w, http.StatusUnauthorized, "missing or invalid token")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (s *apiServer) handleStart(w http.ResponseWriter, r *http.Request) {
+	var session apiSession
+	if err := json.NewDecoder(r.Body).Decode(&session); err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+		return
+	}
+	if len(session.Titles) == 0 {
+		writeAPIError(w, http.StatusBadRequest, "at least one title is required")
+		return
+	}
+	titles, err := expandTitles(session.Titles)
+	if err == nil {
+		titles, err = checkTitles(titles)
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	session.Titles = titles
+	for _, tag := range session.Tags {
+		if strings.Contains(tag, talogo.TagSeparator) {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid tag %q: tags cannot contain %q", tag, talogo.TagSeparator))
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.session != nil {
+		writeAPIError(w, http.StatusConflict, "a session is already running")
+		return
+	}
+	session.Start = time.Now()
+	s.session = &session
+	s.writeState()
+	writeAPIJSON(w, http.StatusCreated, s.status())
+	go sendWebhooks(EventStart, session.record(0))
+	go runHook(EventStart, session.record(0))
+}
+
+func (s *apiServer) handleStop(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.session == nil {
+		writeAPIError(w, http.StatusConflict, "no session is running")
+		return
+	}
+
+	dayStart, err := configuredDayStart()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	record := s.session.record(time.Since(s.session.Start))
+	write, tag, err := checkMinSession(record.Elapsed)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !write {
+		// Too short to keep, like a cancelled session
+		s.session = nil
+		clearSessionState()
+		writeAPIJSON(w, http.StatusOK, []talogo.JSONRecord{})
+		go sendWebhooks(EventCancel, record)
+		return
+	}
+	if tag != "" {
+		record.Tags = withTag(record.Tags, tag)
+	}
+	records, err := roundSession(talogo.SplitByDay(record, dayStart))
+	if err == nil {
+		err = appendOrSavePending(s.logFile, records)
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.session = nil
+	clearSessionState()
+	appendToDailyNotes(records)
+
+	logged := make([]talogo.JSONRecord, 0, len(records))
+	for _, record := range records {
+		logged = append(logged, talogo.NewJSONRecord(record, logPrecision()))
+	}
+	writeAPIJSON(w, http.StatusOK, logged)
+	go sendWebhooks(EventStop, record)
+	go runHook(EventStop, record)
+}
+
+func (s *apiServer) handleCancel(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.session == nil {
+		writeAPIError(w, http.StatusConflict, "no session is running")
+		return
+	}
+	cancelled := s.session.record(time.Since(s.session.Start))
+	s.session = nil
+	clearSessionState()
+	writeAPIJSON(w, http.StatusOK, s.status())
+	go sendWebhooks(EventCancel, cancelled)
+}
+
+func (s *apiServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeAPIJSON(w, http.StatusOK, s.status())
+}
+
+// writeState records the running session for commands such as status.
+// The caller must hold the lock.
+func (s *apiServer) writeState() {
+	err := writeSessionState(sessionState{
+		Titles:  s.session.Titles,
+		Tags:    s.session.Tags,
+		Notes:   s.session.Notes,
+		Start:   s.session.Start,
+		LogFile: s.logFile,
+		PID:     os.Getpid(),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("Warning: %v\n"), err)
+	}
+}
+
+// record returns the session as a record lasting elapsed
+func (session *apiSession) record(elapsed time.Duration) Record {
+	return withOrigin(Record{
+		Start:   session.Start,
+		End:     session.Start.Add(elapsed),
+		Titles:  session.Titles,
+		Tags:    session.Tags,
+		Notes:   session.Notes,
+		Source:  talogo.SourceAPI,
+		Elapsed: elapsed,
+	})
+}
+
+// status returns the state of the running session. The caller must hold
+// the lock.
+func (s *apiServer) status() apiStatus {
+	if s.session == nil {
+		return apiStatus{}
+	}
+	return apiStatus{
+		Running:        true,
+		Session:        s.session,
+		ElapsedSeconds: time.Since(s.session.Start).Seconds(),
+	}
+}
+
+func (s *apiServer) handleEntries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	last := 0
+	if value := query.Get("last"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid last %q", value))
+			return
+		}
+		last = n
+	}
+
+	records, err := readRecords(s.logFile)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	entries := []talogo.JSONRecord{}
+	for _, record := range records {
+		if date := query.Get("date"); date != "" && record.Start.Format("2006-01-02") != date {
+			continue
+		}
+		if !record.MatchesHost(query["host"]) {
+			continue
+		}
+		entries = append(entries, talogo.NewJSONRecord(record, logPrecision()))
+	}
+	if last > 0 && len(entries) > last {
+		entries = entries[len(entries)-last:]
+	}
+	writeAPIJSON(w, http.StatusOK, entries)
+}
+
+func (s *apiServer) handleSummary(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	loc, err := reportLocation(query.Get("tz"))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	by := query.Get("by")
+	if by != "" && by != "task" && by != "tag" {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid by %q (expected task or tag)", by))
+		return
+	}
+	config, err := loadConfig()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	dayStart, err := config.dayStart()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	opts := summaryOptions{
+		Earnings: query.Get("earnings") == "true",
+		Config:   config,
+		Location: loc,
+		Sources:  query["source"],
+		Exclude:  query["exclude"],
+		ByTag:    by == "tag",
+		Hosts:    query["host"],
+		DayStart: dayStart,
+	}
+	days, _, _, err := buildSummary(s.logFile, opts)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeAPIJSON(w, http.StatusOK, apiSummary(days, opts))
+}
+
+// apiSummary converts the task tree of each day to their JSON
+// representation, sorted by date
+func apiSummary(days map[string]*TaskNode, opts summaryOptions) []apiDay {
+	summary := []apiDay{}
+	for _, date := range talogo.SortedDates(days) {
+		day := days[date]
+		summary = append(summary, apiDay{
+			Date:     date,
+			Hours:    day.TotalTime.Hours(),
+			Earnings: apiEarnings(day.Earnings, opts),
+			Tasks:    apiTasks(day.Children, opts),
+		})
+	}
+	return summary
+}
+
+// apiTasks converts summary task nodes to their JSON representation,
+// sorted by name
+func apiTasks(nodes map[string]*TaskNode, opts summaryOptions) []*apiTask {
+	var names []string
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var tasks []*apiTask
+	for _, name := range names {
+		node := nodes[name]
+		tasks = append(tasks, &apiTask{
+			Name:     name,
+			Hours:    node.TotalTime.Hours(),
+			Earnings: apiEarnings(node.Earnings, opts),
+			Tasks:    apiTasks(node.Children, opts),
+		})
+	}
+	return tasks
+}
+
+// apiEarnings returns the earnings to report, nil if not requested
+func apiEarnings(earnings float64, opts summaryOptions) *float64 {
+	if !opts.Earnings {
+		return nil
+	}
+	return &earnings
+}
+
+// writeAPIJSON writes v as the JSON response body with the given status
+func writeAPIJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeAPIError writes an error response as {"error": message}
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeAPIJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// similarTask is a task path that looks like a typo or a spelling
+// variant of a sibling task logged more often, its canonical path
+type similarTask struct {
+	Path           string
+	Count          int
+	Canonical      string
+	CanonicalCount int
+}
+
+// taskCounts returns the number of records logged under each task path
+// (titles joined by "/"), subtasks included
+func taskCounts(records []Record) map[string]int {
+	counts := make(map[string]int)
+	for _, record := range records {
+		for i := range record.Titles {
+			counts[strings.Join(record.Titles[:i+1], "/")]++
+		}
+	}
+	return counts
+}
+
+// findSimilarTasks returns the task paths of counts that look like a
+// sibling task with more records, sorted by path. Subtasks of a task are
+// only compared with each other, so work/mail and home/mail are fine.
+func findSimilarTasks(counts map[string]int) []similarTask {
+	siblings := make(map[string][]string)
+	for path := range counts {
+		parent := ""
+		if i := strings.LastIndex(path, "/"); i >= 0 {
+			parent = path[:i]
+		}
+		siblings[parent] = append(siblings[parent], path)
+	}
+
+	var similar []similarTask
+	for _, paths := range siblings {
+		for _, path := range paths {
+			best := similarTask{Path: path, Count: counts[path]}
+			for _, other := range paths {
+				if other == path || !similarNames(taskName(path), taskName(other)) {
+					continue
+				}
+				// The task logged more often is the canonical one, ties
+				// go to the first in order
+				if counts[other] < best.Count || (counts[other] == best.Count && other > path) {
+					continue
+				}
+				if counts[other] > best.CanonicalCount {
+					best.Canonical, best.CanonicalCount = other, counts[other]
+				}
+			}
+			if best.Canonical != "" {
+				similar = append(similar, best)
+			}
+		}
+	}
+	sort.Slice(similar, func(i, j int) bool {
+		return similar[i].Path < similar[j].Path
+	})
+	return similar
+}
+
+// taskName returns the last title of a task path
+func taskName(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// similarNames reports whether two task names look like the same task:
+// equal ignoring case, spaces and punctuation ("ProjectX", "project x"),
+// or a typo away ("Emials", "Emails"). Names with different numbers, such
+// as tickets, are never similar.
+func similarNames(a, b string) bool {
+	a, b = nameKey(a), nameKey(b)
+	if a == b {
+		return true
+	}
+	if digitsOf(a) != digitsOf(b) {
+		return false
+	}
+	shortest := len([]rune(a))
+	if n := len([]rune(b)); n < shortest {
+		shortest = n
+	}
+	switch {
+	case shortest < 4:
+		return false
+	case shortest < 10:
+		return editDistance(a, b) <= 1
+	default:
+		return editDistance(a, b) <= 2
+	}
+}
+
+// nameKey returns name in lower case with only its letters and digits
+func nameKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// digitsOf returns the digits of s
+func digitsOf(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// editDistance returns the number of insertions, deletions, substitutions
+// and transpositions of adjacent characters turning a into b
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(s)][len(t)]
+}
+
+// checkSimilarTasks reports task names that look like a typo or variant
+// of a sibling task logged more often
+func checkSimilarTasks(records []Record) []string {
+	var problems []string
+	for _, s := range findSimilarTasks(taskCounts(records)) {
+		problems = append(problems, fmt.Sprintf("%s (%d records) looks like %s (%d records), merge with: talogo rename %q %q",
+			s.Path, s.Count, s.Canonical, s.CanonicalCount, s.Path, s.Canonical))
+	}
+	return problems
+}
+
+// confirmSimilarTitles asks, for each title of a new session that is not
+// in the log file under its parent task, whether to use a similar task
+// of the log instead, and returns the titles to log
+func confirmSimilarTitles(logFile string, titles []string) ([]string, error) {
+	if _, err := os.Stat(logPath(logFile)); err != nil {
+		return titles, nil
+	}
+	records, err := readRecords(logFile)
+	if err != nil {
+		return nil, err
+	}
+	counts := taskCounts(records)
+
+	stdin := bufio.NewReader(os.Stdin)
+	titles = append([]string{}, titles...)
+	for i := range titles {
+		path := strings.Join(titles[:i+1], "/")
+		if counts[path] > 0 {
+			continue
+		}
+
+		canonical, canonicalCount := "", 0
+		for other, count := range counts {
+			parent, name := "", other
+			if j := strings.LastIndex(other, "/"); j >= 0 {
+				parent, name = other[:j], other[j+1:]
+			}
+			if parent != strings.Join(titles[:i], "/") || !similarNames(titles[i], name) {
+				continue
+			}
+			if count > canonicalCount || (count == canonicalCount && name < canonical) {
+				canonical, canonicalCount = name, count
+			}
+		}
+		if canonical == "" {
+			// Subtasks of a new task are new too
+			break
+		}
+
+		canonicalPath := strings.Join(append(append([]string{}, titles[:i]...), canonical), "/")
+		fmt.Printf(tr("%s is not in the log, but %s is (%d records). Use it instead? [Y/n] "), path, canonicalPath, canonicalCount)
+		// Without an answer, such as with stdin closed, keep the titles
+		answer, err := stdin.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if (err != nil && answer == "") || (answer != "" && answer != "y" && answer != "yes") {
+			fmt.Println()
+			break
+		}
+		titles[i] = canonical
+	}
+	return titles, nil
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchema creates the table of SQLite logs. Times are kept as RFC3339
+// text like in the other backends, along with the Unix start time used by
+// range queries. Titles and tags are JSON arrays.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS records (
+	seq              INTEGER PRIMARY KEY AUTOINCREMENT,
+	id               TEXT NOT NULL DEFAULT '',
+	start_time       TEXT NOT NULL,
+	end_time         TEXT NOT NULL,
+	start_unix       INTEGER NOT NULL,
+	duration_seconds REAL NOT NULL,
+	titles           TEXT NOT NULL,
+	source           TEXT NOT NULL DEFAULT '',
+	tags             TEXT NOT NULL DEFAULT '[]',
+	notes            TEXT NOT NULL DEFAULT '',
+	host             TEXT NOT NULL DEFAULT '',
+	user             TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS records_start ON records (start_unix);
+`
+
+// sqliteColumns are the columns of a record, in the order read and written
+const sqliteColumns = "id, start_time, end_time, duration_seconds, titles, source, tags, notes, host, user"
+
+// sqliteStore is the SQLite backend, a database file holding one row per
+// record in log order
+type sqliteStore struct {
+	path string
+}
+
+// open opens the database, creating it with the records table if create
+// is set. Reading a missing database fails like reading a missing file.
+func (s sqliteStore) open(create bool) (*sql.DB, error) {
+	if !create {
+		if _, err := os.Stat(s.path); err != nil {
+			return nil, fmt.Errorf("failed to open SQLite database: %v", err)
+		}
+	}
+	db, err := sql.Open("sqlite", s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %v", err)
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create SQLite schema: %v", err)
+	}
+	return db, nil
+}
+
+func (s sqliteStore) Append(records []Record) error {
+	db, err := s.open(true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback() // No-op once committed
+	if err := insertSQLiteRecords(tx, records); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit records: %v", err)
+	}
+	return nil
+}
+
+func (s sqliteStore) List(onBad talogo.BadRecordFunc, fn func(Record) error) error {
+	return s.query(onBad, fn, "")
+}
+
+// Query uses the index on the start time instead of reading every record
+func (s sqliteStore) Query(from, to time.Time, fn func(Record) error) error {
+	where, args := "", []interface{}{}
+	if !from.IsZero() {
+		where += " AND start_unix >= ?"
+		args = append(args, from.Unix())
+	}
+	if !to.IsZero() {
+		where += " AND start_unix < ?"
+		args = append(args, to.Unix())
+	}
+	// Unix times drop fractions of a second, filter them precisely here
+	return s.query(skipBadRecord, func(record Record) error {
+		if (!from.IsZero() && record.Start.Before(from)) || (!to.IsZero() && !record.Start.Before(to)) {
+			return nil
+		}
+		return fn(record)
+	}, where, args...)
+}
+
+// query calls fn for each valid record of the rows matching the where
+// conditions, and onBad for each malformed one. Rows are numbered as
+// lines by their sequence number, their position in the log since
+// Rewrite restarts the sequence.
+func (s sqliteStore) query(onBad talogo.BadRecordFunc, fn func(Record) error, where string, args ...interface{}) error {
+	db, err := s.open(false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Filtered directly on the table, so the conditions use its indexes
+	rows, err := db.Query("SELECT seq, "+sqliteColumns+" FROM records WHERE 1 = 1"+where+" ORDER BY seq", args...)
+	if err != nil {
+		return fmt.Errorf("failed to query records: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line int
+		var j talogo.JSONRecord
+		var titles, tags string
+		if err := rows.Scan(&line, &j.ID, &j.StartTime, &j.EndTime, &j.DurationSeconds, &titles, &j.Source, &tags, &j.Notes, &j.Host, &j.User); err != nil {
+			return fmt.Errorf("failed to read record: %v", err)
+		}
+
+		record, err := j.Record()
+		if err == nil {
+			err = json.Unmarshal([]byte(titles), &record.Titles)
+		}
+		if err == nil {
+			err = json.Unmarshal([]byte(tags), &record.Tags)
+		}
+		if err != nil {
+			if err := onBad(line, err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+		record.Line = line
+
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read records: %v", err)
+	}
+	return nil
+}
+
+// Rewrite replaces every row in a single transaction, after copying the
+// database to the .bak file, numbering them from 1
+func (s sqliteStore) Rewrite(records []Record) error {
+	if err := backupBeforeRewrite(s.path); err != nil {
+		return err
+	}
+
+	db, err := s.open(false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback() // No-op once committed
+	if _, err := tx.Exec("DELETE FROM records"); err != nil {
+		return fmt.Errorf("failed to delete records: %v", err)
+	}
+	// Number the records from 1 again, as lines
+	if _, err := tx.Exec("DELETE FROM sqlite_sequence WHERE name = 'records'"); err != nil {
+		return fmt.Errorf("failed to reset record numbers: %v", err)
+	}
+	if err := insertSQLiteRecords(tx, records); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit records: %v", err)
+	}
+
+	invalidateIndex(s.path)
+	return nil
+}
+
+// insertSQLiteRecords adds records at the end of the records table
+func insertSQLiteRecords(tx *sql.Tx, records []Record) error {
+	insert, err := tx.Prepare("INSERT INTO records (start_unix, " + sqliteColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert: %v", err)
+	}
+	defer insert.Close()
+
+	precision := logPrecision()
+	for _, record := range records {
+		j := talogo.NewJSONRecord(record, precision)
+		titles, err := json.Marshal(j.Titles)
+		if err != nil {
+			return fmt.Errorf("failed to encode titles: %v", err)
+		}
+		tags := []byte("[]")
+		if len(j.Tags) > 0 {
+			if tags, err = json.Marshal(j.Tags); err != nil {
+				return fmt.Errorf("failed to encode tags: %v", err)
+			}
+		}
+		_, err = insert.Exec(record.Start.Unix(), j.ID, j.StartTime, j.EndTime, j.DurationSeconds, string(titles),
+			j.Source, string(tags), j.Notes, j.Host, j.User)
+		if err != nil {
+			return fmt.Errorf("failed to insert record: %v", err)
+		}
+	}
+	return nil
+}
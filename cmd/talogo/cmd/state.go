@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// sessionState describes a running session, so other commands such as
+// status can report on it without talking to the tracking process. Each
+// process keeps its own, as several sessions can run at once.
+type sessionState struct {
+	Titles  []string  `json:"titles"`
+	Tags    []string  `json:"tags,omitempty"`
+	Notes   string    `json:"notes,omitempty"`
+	Start   time.Time `json:"start_time"`
+	LogFile string    `json:"log_file"`
+	PID     int       `json:"pid"` // Process tracking the session
+
+	Paused    bool          `json:"paused,omitempty"`
+	PausedAt  time.Time     `json:"paused_at,omitempty"`  // Start of the current pause
+	PausedFor time.Duration `json:"paused_for,omitempty"` // Total of the finished pauses
+
+	// Active spans, so another terminal can take the session over
+	Spans     []timeSpan `json:"spans,omitempty"`      // Finished active spans
+	SpanStart time.Time  `json:"span_start,omitempty"` // Start of the current active span
+}
+
+// Actions another process can ask of the running session
+const (
+	controlStop     = "stop"      // Stop and save the session
+	controlTakeOver = "take-over" // Quit without saving, the session continues elsewhere
+)
+
+// sessionControl asks the process with PID to act on its session
+type sessionControl struct {
+	PID    int    `json:"pid"`
+	Action string `json:"action"`
+}
+
+// controlFile returns the path of the file holding a sessionControl for
+// the process with pid
+func controlFile(pid int) string {
+	return filepath.Join(sessionsDir(), strconv.Itoa(pid)+".control")
+}
+
+// controlTimeout bounds the wait for the running session to act on a
+// sessionControl. Sessions check for one every second.
+const controlTimeout = 5 * time.Second
+
+// requestSessionAction asks the process tracking the session state to
+// perform action, and waits until it leaves the session
+func requestSessionAction(state sessionState, action string) error {
+	data, err := json.Marshal(sessionControl{PID: state.PID, Action: action})
+	if err != nil {
+		return fmt.Errorf("failed to encode session control: %v", err)
+	}
+	if err := os.WriteFile(controlFile(state.PID), data, 0644); err != nil {
+		return fmt.Errorf("failed to write session control: %v", err)
+	}
+	defer os.Remove(controlFile(state.PID))
+
+	for deadline := time.Now().Add(controlTimeout); time.Now().Before(deadline); time.Sleep(100 * time.Millisecond) {
+		if _, err := os.Stat(stateFile(state.PID)); os.IsNotExist(err) || !processAlive(state.PID) {
+			return nil
+		}
+	}
+	return fmt.Errorf("the session of process %d did not respond, stop it where it runs", state.PID)
+}
+
+// readSessionControl returns the action asked of this process, if any,
+// consuming the request
+func readSessionControl() (string, bool) {
+	data, err := os.ReadFile(controlFile(os.Getpid()))
+	if err != nil {
+		return "", false
+	}
+	var control sessionControl
+	if err := json.Unmarshal(data, &control); err != nil || control.PID != os.Getpid() {
+		return "", false
+	}
+	os.Remove(controlFile(os.Getpid()))
+	return control.Action, true
+}
+
+// elapsed returns the time tracked in the session, leaving out the pauses
+func (state sessionState) elapsed() time.Duration {
+	end := time.Now()
+	if state.Paused {
+		end = state.PausedAt
+	}
+	return end.Sub(state.Start) - state.PausedFor
+}
+
+// sessionsDir returns the directory holding the state of each running
+// session
+func sessionsDir() string {
+	return filepath.Join(dataDir(), "sessions")
+}
+
+// stateFile returns the path of the file holding the session of the
+// process with pid
+func stateFile(pid int) string {
+	return filepath.Join(sessionsDir(), strconv.Itoa(pid)+".json")
+}
+
+// writeSessionState records state as the running session of its process
+func writeSessionState(state sessionState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode session state: %v", err)
+	}
+	if err := os.MkdirAll(sessionsDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %v", err)
+	}
+	// Readers never see a partly written state
+	tmp := stateFile(state.PID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write session state: %v", err)
+	}
+	if err := os.Rename(tmp, stateFile(state.PID)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write session state: %v", err)
+	}
+	return nil
+}
+
+// clearSessionState removes the running session written by this process,
+// leaving those of other processes alone
+func clearSessionState() {
+	os.Remove(stateFile(os.Getpid()))
+}
+
+// readSessionStates returns the running sessions, oldest first. States
+// left behind by processes that no longer run are removed.
+func readSessionStates() []sessionState {
+	entries, err := os.ReadDir(sessionsDir())
+	if err != nil {
+		return nil
+	}
+	var states []sessionState
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok {
+			continue
+		}
+		pid, err := strconv.Atoi(name)
+		if err != nil {
+			continue
+		}
+		var state sessionState
+		data, err := os.ReadFile(stateFile(pid))
+		if err != nil || json.Unmarshal(data, &state) != nil {
+			continue
+		}
+		if !processAlive(state.PID) {
+			os.Remove(stateFile(pid))
+			continue
+		}
+		states = append(states, state)
+	}
+	sort.SliceStable(states, func(i, j int) bool {
+		return states[i].Start.Before(states[j].Start)
+	})
+	return states
+}
+
+// readSessionState returns the latest started running session, if any
+func readSessionState() (sessionState, bool) {
+	states := readSessionStates()
+	if len(states) == 0 {
+		return sessionState{}, false
+	}
+	return states[len(states)-1], true
+}
+
+// processAlive reports whether a process with the given pid is running
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// On Windows finding the process already checks it exists, and
+	// signals other than kill are not supported
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return process.Signal(syscall.Signal(0)) == nil
+}
@@ -0,0 +1,463 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsCmdLogFile  string
+	statsCmdSwitches bool
+	statsCmdHours    bool
+	statsCmdWeekdays bool
+	statsCmdPomodoro bool
+	statsCmdFrom     string
+	statsCmdTo       string
+	statsCmdTasks    []string
+	statsCmdTZ       string
+	statsCmdSources  []string
+)
+
+// statsCmd defines the stats subcommand
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show statistics about the logged sessions",
+	Long: `Show statistics about the logged sessions: overall totals, or with
+--switches the task switches of each day.
+
+With --hours or --weekdays, show how the tracked time distributes across
+the hours of the day or the days of the week, for example to see when
+deep work happens compared to meetings:
+
+  talogo stats --hours --task work/code --from 2026-01-01
+
+With --pomodoros, show the pomodoros completed with 'talogo log
+--pomodoro' each day, by task.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		modes := 0
+		for _, mode := range []bool{statsCmdSwitches, statsCmdHours, statsCmdWeekdays, statsCmdPomodoro} {
+			if mode {
+				modes++
+			}
+		}
+		if modes > 1 {
+			fmt.Fprintln(os.Stderr, tr("Error: --switches, --hours, --weekdays and --pomodoros cannot be combined"))
+			os.Exit(1)
+		}
+
+		records, err := readRecords(statsCmdLogFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error reading log: %v\n"), err)
+			os.Exit(1)
+		}
+
+		loc, err := reportLocation(statsCmdTZ)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		records = recordsIn(talogo.FilterBySource(records, statsCmdSources), loc)
+
+		dayStart, err := configuredDayStart()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+
+		records, err = recordsBetween(records, statsCmdFrom, statsCmdTo)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		if len(statsCmdTasks) > 0 {
+			var matching []Record
+			for _, record := range records {
+				if record.MatchesTask(statsCmdTasks) {
+					matching = append(matching, record)
+				}
+			}
+			records = matching
+		}
+
+		print := printGeneralStats
+		switch {
+		case statsCmdSwitches:
+			print = printSwitchStats
+		case statsCmdHours:
+			print = printHourStats
+		case statsCmdWeekdays:
+			print = printWeekdayStats
+		case statsCmdPomodoro:
+			print = printPomodoroStats
+		}
+		if err := print(records, dayStart); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	statsCmd.Flags().StringVarP(&statsCmdLogFile, "file", "f", defaultLogFile(), "Log file to read")
+	statsCmd.Flags().BoolVar(&statsCmdSwitches, "switches", false, "Report task switches and average block length per day")
+	statsCmd.Flags().BoolVar(&statsCmdHours, "hours", false, "Report the tracked time by hour of the day")
+	statsCmd.Flags().BoolVar(&statsCmdWeekdays, "weekdays", false, "Report the tracked time by day of the week")
+	statsCmd.Flags().BoolVar(&statsCmdPomodoro, "pomodoros", false, "Report the pomodoros completed per day and task")
+	statsCmd.Flags().StringVar(&statsCmdFrom, "from", "", "Only include days from this date (YYYY-MM-DD)")
+	statsCmd.Flags().StringVar(&statsCmdTo, "to", "", "Only include days up to this date (YYYY-MM-DD)")
+	statsCmd.Flags().StringArrayVar(&statsCmdTasks, "task", nil, "Only include a task and its subtasks (e.g. \"work\" or \"work/meetings\")")
+	statsCmd.Flags().StringSliceVar(&statsCmdSources, "source", nil, "Only include records created by these sources (interactive, add, import, auto, recovered, api, unknown)")
+	statsCmd.Flags().StringVar(&statsCmdTZ, "tz", "", "Time zone used to group records by day (e.g. Europe/Madrid)")
+	rootCmd.AddCommand(statsCmd)
+}
+
+// recordsByDay groups records by the day of their start time, with days
+// beginning dayStart after midnight, each day sorted by start time. It
+// also returns the sorted list of dates.
+func recordsByDay(records []Record, dayStart time.Duration) ([]string, map[string][]Record) {
+	days := make(map[string][]Record)
+	for _, record := range records {
+		date := talogo.DayOf(record.Start, dayStart)
+		days[date] = append(days[date], record)
+	}
+
+	var dates []string
+	for date, dayRecords := range days {
+		dates = append(dates, date)
+		sort.SliceStable(dayRecords, func(i, j int) bool {
+			return dayRecords[i].Start.Before(dayRecords[j].Start)
+		})
+	}
+	sort.Strings(dates)
+
+	return dates, days
+}
+
+// generalStats are the overall totals of the log
+type generalStats struct {
+	Sessions           int     `json:"sessions"`
+	Days               int     `json:"days"`
+	TotalHours         float64 `json:"total_hours"`
+	AverageHoursPerDay float64 `json:"average_hours_per_day"`
+}
+
+// switchStats are the task switches of each day of the log
+type switchStats struct {
+	Days                  []daySwitches `json:"days"`
+	AverageSwitchesPerDay float64       `json:"average_switches_per_day"`
+}
+
+// daySwitches are the task switches of a day and the average length of
+// the blocks between them
+type daySwitches struct {
+	Date                string  `json:"date"`
+	Switches            int     `json:"switches"`
+	AverageBlockMinutes float64 `json:"average_block_minutes"`
+}
+
+// computeGeneralStats returns the overall totals of records
+func computeGeneralStats(records []Record, dayStart time.Duration) generalStats {
+	dates, _ := recordsByDay(records, dayStart)
+
+	var total time.Duration
+	for _, record := range records {
+		total += record.Duration()
+	}
+
+	stats := generalStats{Sessions: len(records), Days: len(dates), TotalHours: total.Hours()}
+	if len(dates) > 0 {
+		stats.AverageHoursPerDay = total.Hours() / float64(len(dates))
+	}
+	return stats
+}
+
+// printGeneralStats prints overall totals of the log
+func printGeneralStats(records []Record, dayStart time.Duration) error {
+	stats := computeGeneralStats(records, dayStart)
+	if jsonOutput() {
+		return printJSON(stats)
+	}
+
+	rows := [][]string{
+		{tr("Sessions"), fmt.Sprint(stats.Sessions)},
+		{tr("Days"), fmt.Sprint(stats.Days)},
+		{tr("Total"), fmt.Sprintf("%.2f hs", stats.TotalHours)},
+	}
+	if stats.Days > 0 {
+		rows = append(rows, []string{tr("Average per day"), fmt.Sprintf("%.2f hs", stats.AverageHoursPerDay)})
+	}
+	printTable(nil, rows, 1)
+	return nil
+}
+
+// computeSwitchStats returns, for each day, how many times the tracked
+// task changed and the average length of the blocks between changes
+func computeSwitchStats(records []Record, dayStart time.Duration) switchStats {
+	dates, days := recordsByDay(records, dayStart)
+	stats := switchStats{Days: []daySwitches{}}
+
+	var totalSwitches int
+	for _, date := range dates {
+		dayRecords := days[date]
+
+		switches := 0
+		var tracked time.Duration
+		for i, record := range dayRecords {
+			tracked += record.Duration()
+			if i > 0 && strings.Join(record.Titles, "/") != strings.Join(dayRecords[i-1].Titles, "/") {
+				switches++
+			}
+		}
+		totalSwitches += switches
+
+		avgBlock := tracked / time.Duration(switches+1)
+		stats.Days = append(stats.Days, daySwitches{
+			Date:                date,
+			Switches:            switches,
+			AverageBlockMinutes: avgBlock.Round(time.Minute).Minutes(),
+		})
+	}
+	if len(dates) > 0 {
+		stats.AverageSwitchesPerDay = float64(totalSwitches) / float64(len(dates))
+	}
+	return stats
+}
+
+// printSwitchStats prints, for each day, how many times the tracked task
+// changed and the average length of the blocks between changes
+func printSwitchStats(records []Record, dayStart time.Duration) error {
+	stats := computeSwitchStats(records, dayStart)
+	if jsonOutput() {
+		return printJSON(stats)
+	}
+	if len(stats.Days) == 0 {
+		fmt.Println(tr("No data in CSV file (only header or empty)"))
+		return nil
+	}
+
+	rows := make([][]string, len(stats.Days))
+	for i, day := range stats.Days {
+		rows[i] = []string{
+			day.Date,
+			fmt.Sprint(day.Switches),
+			time.Duration(day.AverageBlockMinutes * float64(time.Minute)).String(),
+			strings.Repeat("#", day.Switches),
+		}
+	}
+	printTable([]string{tr("Date"), tr("Switches"), tr("Average block"), ""}, rows, 1, 2)
+
+	fmt.Printf(tr("\nAverage switches per day: %.1f\n"), stats.AverageSwitchesPerDay)
+	return nil
+}
+
+// hourShare is the time tracked in an hour of the day
+type hourShare struct {
+	Hour  int     `json:"hour"`
+	Hours float64 `json:"hours"`
+}
+
+// weekdayShare is the time tracked on a day of the week
+type weekdayShare struct {
+	Weekday string  `json:"weekday"`
+	Hours   float64 `json:"hours"`
+}
+
+// hourDistribution returns the time of records tracked in each hour of
+// the day, on the wall clock of the records. Records with pauses count
+// their tracked time spread evenly over the session.
+func hourDistribution(records []Record) [24]time.Duration {
+	var hours [24]time.Duration
+	for _, record := range records {
+		span := record.End.Sub(record.Start)
+		if span <= 0 {
+			continue
+		}
+		scale := float64(record.Duration()) / float64(span)
+		for t := record.Start; t.Before(record.End); {
+			next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			if next.After(record.End) {
+				next = record.End
+			}
+			hours[t.Hour()] += time.Duration(float64(next.Sub(t)) * scale)
+			t = next
+		}
+	}
+	return hours
+}
+
+// weekdayDistribution returns the time of records tracked on each day of
+// the week, weekStart first, with days beginning dayStart after midnight
+func weekdayDistribution(records []Record, dayStart time.Duration, weekStart time.Weekday) [7]time.Duration {
+	var weekdays [7]time.Duration
+	for _, record := range records {
+		for _, part := range talogo.SplitByDay(record, dayStart) {
+			date, _ := time.Parse("2006-01-02", talogo.DayOf(part.Start, dayStart))
+			weekdays[(int(date.Weekday())-int(weekStart)+7)%7] += part.Duration()
+		}
+	}
+	return weekdays
+}
+
+// printHourStats prints how the tracked time distributes across the
+// hours of the day
+func printHourStats(records []Record, dayStart time.Duration) error {
+	hours := hourDistribution(records)
+	if jsonOutput() {
+		shares := make([]hourShare, len(hours))
+		for hour, d := range hours {
+			shares[hour] = hourShare{Hour: hour, Hours: d.Hours()}
+		}
+		return printJSON(shares)
+	}
+
+	labels := make([]string, len(hours))
+	for hour := range hours {
+		labels[hour] = fmt.Sprintf("%02d:00", hour)
+	}
+	printDistribution(tr("Hour"), labels, hours[:])
+	return nil
+}
+
+// printWeekdayStats prints how the tracked time distributes across the
+// days of the week, from the configured first day
+func printWeekdayStats(records []Record, dayStart time.Duration) error {
+	weekStart, err := configuredWeekStart()
+	if err != nil {
+		return err
+	}
+	weekdays := weekdayDistribution(records, dayStart, weekStart)
+	labels := make([]string, len(weekdays))
+	for i := range weekdays {
+		labels[i] = time.Weekday((i + int(weekStart)) % 7).String()
+	}
+	if jsonOutput() {
+		shares := make([]weekdayShare, len(weekdays))
+		for i, d := range weekdays {
+			shares[i] = weekdayShare{Weekday: labels[i], Hours: d.Hours()}
+		}
+		return printJSON(shares)
+	}
+
+	for i := range labels {
+		labels[i] = tr(labels[i])
+	}
+	printDistribution(tr("Day"), labels, weekdays[:])
+	return nil
+}
+
+// printDistribution prints the time of each label, headed by heading,
+// with its share of the total and a bar scaled to the largest
+func printDistribution(heading string, labels []string, times []time.Duration) {
+	var total, largest time.Duration
+	for _, d := range times {
+		total += d
+		largest = max(largest, d)
+	}
+	if total == 0 {
+		fmt.Println(tr("No tracked time to report"))
+		return
+	}
+
+	const barWidth = 40
+	rows := make([][]string, len(times))
+	for i, d := range times {
+		bar := int(float64(d) / float64(largest) * barWidth)
+		rows[i] = []string{
+			labels[i],
+			fmt.Sprintf("%.2f hs", d.Hours()),
+			fmt.Sprintf("%.0f%%", float64(d)/float64(total)*100),
+			strings.Repeat("█", bar),
+		}
+	}
+	printTable([]string{heading, tr("Time"), tr("Share"), ""}, rows, 1, 2)
+}
+
+// pomodoroStats are the pomodoros completed each day of the log
+type pomodoroStats struct {
+	Days                   []dayPomodoros `json:"days"`
+	Pomodoros              int            `json:"pomodoros"`
+	AveragePomodorosPerDay float64        `json:"average_pomodoros_per_day"`
+}
+
+// dayPomodoros are the pomodoros completed on a day, by task
+type dayPomodoros struct {
+	Date      string          `json:"date"`
+	Pomodoros int             `json:"pomodoros"`
+	Tasks     []taskPomodoros `json:"tasks"`
+}
+
+// taskPomodoros are the pomodoros completed on a task
+type taskPomodoros struct {
+	Task      string `json:"task"`
+	Pomodoros int    `json:"pomodoros"`
+}
+
+// computePomodoroStats returns the pomodoros completed each day, by task.
+// A pomodoro split in several records, by pauses or midnight, counts once,
+// for the task of its last record.
+func computePomodoroStats(records []Record, dayStart time.Duration) pomodoroStats {
+	dates, days := recordsByDay(records, dayStart)
+	stats := pomodoroStats{Days: []dayPomodoros{}}
+
+	for _, date := range dates {
+		tasks := make(map[int]string)
+		for _, record := range days[date] {
+			if number := pomodoroNumber(record.Tags); number > 0 {
+				tasks[number] = strings.Join(record.Titles, "/")
+			}
+		}
+		if len(tasks) == 0 {
+			continue
+		}
+
+		counts := make(map[string]int)
+		for _, task := range tasks {
+			counts[task]++
+		}
+		day := dayPomodoros{Date: date, Pomodoros: len(tasks)}
+		for task, count := range counts {
+			day.Tasks = append(day.Tasks, taskPomodoros{Task: task, Pomodoros: count})
+		}
+		sort.Slice(day.Tasks, func(i, j int) bool {
+			if day.Tasks[i].Pomodoros != day.Tasks[j].Pomodoros {
+				return day.Tasks[i].Pomodoros > day.Tasks[j].Pomodoros
+			}
+			return day.Tasks[i].Task < day.Tasks[j].Task
+		})
+		stats.Days = append(stats.Days, day)
+		stats.Pomodoros += day.Pomodoros
+	}
+	if len(stats.Days) > 0 {
+		stats.AveragePomodorosPerDay = float64(stats.Pomodoros) / float64(len(stats.Days))
+	}
+	return stats
+}
+
+// printPomodoroStats prints the pomodoros completed each day, by task
+func printPomodoroStats(records []Record, dayStart time.Duration) error {
+	stats := computePomodoroStats(records, dayStart)
+	if jsonOutput() {
+		return printJSON(stats)
+	}
+	if len(stats.Days) == 0 {
+		fmt.Println(tr("No pomodoros logged"))
+		return nil
+	}
+
+	var rows [][]string
+	for _, day := range stats.Days {
+		rows = append(rows, []string{day.Date, "", fmt.Sprint(day.Pomodoros), strings.Repeat("●", day.Pomodoros)})
+		for _, task := range day.Tasks {
+			rows = append(rows, []string{"", talogo.SanitizeTitle(task.Task), fmt.Sprint(task.Pomodoros), ""})
+		}
+	}
+	printTable([]string{tr("Date"), tr("Task"), tr("Pomodoros"), ""}, rows, 2)
+	fmt.Printf(tr("\nPomodoros: %d, %.1f per day with pomodoros\n"), stats.Pomodoros, stats.AveragePomodorosPerDay)
+	return nil
+}
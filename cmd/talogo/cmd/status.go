@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var statusCmdFormat string
+
+// statusCmd defines the status subcommand
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the running session, also formatted for status bars",
+	Long: `Show the running sessions, read from the state kept by each 'talogo log'
+and 'talogo serve' process. Sessions running side by side, e.g. with
+'log --running both' or 'log --allow-overlap', are all shown, oldest first.
+
+Formats:
+  text    human readable description (default)
+  plain   a single line for polybar, i3blocks or tmux
+  waybar  the JSON expected by a waybar custom module with return-type json
+
+With --quiet, text prints only the running tasks, e.g. work/mail, one per
+line. The text and JSON outputs exit with code 2 when no session is
+running, so scripts can check with: if talogo status -q; then ...`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		states := readSessionStates()
+		format := statusCmdFormat
+		if jsonOutput() {
+			format = "json"
+		}
+		if err := printStatus(states, format); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(exitError)
+		}
+		// Status bars run the command on a timer, and may hide modules
+		// that fail
+		if len(states) == 0 && (format == "text" || format == "json") {
+			os.Exit(exitNotRunning)
+		}
+	},
+}
+
+func init() {
+	statusCmd.Flags().StringVar(&statusCmdFormat, "format", "text", "Output format (text, plain, waybar)")
+	rootCmd.AddCommand(statusCmd)
+}
+
+// printStatus prints the running sessions in the given format
+func printStatus(states []sessionState, format string) error {
+	switch format {
+	case "text":
+		if quiet {
+			for _, state := range states {
+				fmt.Println(strings.Join(state.Titles, "/"))
+			}
+			return nil
+		}
+		if len(states) == 0 {
+			fmt.Println(tr("Not tracking"))
+			return nil
+		}
+		for i, state := range states {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf(tr("Tracking %s for %s (since %s)\n"), strings.Join(state.Titles, " / "),
+				state.elapsed().Round(time.Second), state.Start.Format(clockLayout))
+			if state.Paused {
+				fmt.Printf(tr("Paused since %s\n"), state.PausedAt.Format(clockLayout))
+			}
+			if len(state.Tags) > 0 {
+				fmt.Printf(tr("Tags: %s\n"), strings.Join(state.Tags, ", "))
+			}
+			fmt.Printf(tr("Log: %s\n"), state.LogFile)
+		}
+	case "plain":
+		if len(states) == 0 {
+			fmt.Println("idle")
+			return nil
+		}
+		fmt.Println(strings.Join(statusSegments(states), "  "))
+	case "waybar":
+		module := map[string]string{"text": "idle", "tooltip": tr("Not tracking"), "class": "idle", "alt": "idle"}
+		if len(states) > 0 {
+			var tooltips []string
+			class := "paused"
+			for _, state := range states {
+				tooltip := fmt.Sprintf(tr("%s\nStarted at %s"), strings.Join(state.Titles, " / "), state.Start.Format(clockLayout))
+				if len(state.Tags) > 0 {
+					tooltip += "\n" + tr("Tags: ") + strings.Join(state.Tags, ", ")
+				}
+				if state.Paused {
+					tooltip += fmt.Sprintf(tr("\nPaused since %s"), state.PausedAt.Format(clockLayout))
+				} else {
+					class = "running"
+				}
+				tooltips = append(tooltips, tooltip)
+			}
+			module = map[string]string{
+				"text":    strings.Join(statusSegments(states), "  "),
+				"tooltip": strings.Join(tooltips, "\n\n"),
+				"class":   class,
+				"alt":     class,
+			}
+		}
+		return json.NewEncoder(os.Stdout).Encode(module)
+	case "json":
+		status := apiStatus{}
+		if len(states) > 0 {
+			// The top level fields describe the latest session, as
+			// before several could run
+			status = newAPIStatus(states[len(states)-1])
+			for _, state := range states {
+				status.Sessions = append(status.Sessions, newAPIStatus(state))
+			}
+		}
+		return printJSON(status)
+	default:
+		return fmt.Errorf("unknown format %q (expected text, plain or waybar)", format)
+	}
+	return nil
+}
+
+// newAPIStatus returns the status of the running session state
+func newAPIStatus(state sessionState) apiStatus {
+	return apiStatus{
+		Running:        true,
+		Session:        &apiSession{Titles: state.Titles, Tags: state.Tags, Notes: state.Notes, Start: state.Start},
+		ElapsedSeconds: state.elapsed().Seconds(),
+		Paused:         state.Paused,
+		LogFile:        state.LogFile,
+	}
+}
+
+// statusSegments returns a short segment for each running session, e.g.
+// ▶ work/mail 1:05
+func statusSegments(states []sessionState) []string {
+	segments := make([]string, 0, len(states))
+	for _, state := range states {
+		segments = append(segments, fmt.Sprintf("%s %s %s", statusIcon(state),
+			strings.Join(state.Titles, "/"), formatClock(state.elapsed())))
+	}
+	return segments
+}
+
+// statusIcon returns the symbol shown before the running session
+func statusIcon(state sessionState) string {
+	if state.Paused {
+		return "⏸"
+	}
+	return "▶"
+}
+
+// formatClock formats d as hours and minutes, e.g. 1:05
+func formatClock(d time.Duration) string {
+	minutes := int(d.Minutes())
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+// stopwatchCmd defines the stopwatch subcommand
+var stopwatchCmd = &cobra.Command{
+	Use:   "stopwatch [LABEL...]",
+	Short: "Measure time with the timer, without logging it",
+	Long: `Measure time with the timer of 'talogo log', for quick measurements that
+should not appear in reports: nothing is written to the log, and the
+stopwatch is not a running session for status, hooks or webhooks. The
+labels, if any, are shown above the timer.
+
+Keys:
+  p, space     pause or resume
+  l, enter     take a lap
+  q, ctrl+c    stop`,
+	Run: func(cmd *cobra.Command, args []string) {
+		now := time.Now()
+		s := stopwatchModel{model: model{
+			titles:    args,
+			startTime: now,
+			spanStart: now,
+			running:   true,
+		}}
+		if _, err := tea.NewProgram(s).Run(); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(stopwatchCmd)
+}
+
+// stopwatchModel is the bubbletea model of the stopwatch subcommand: the
+// timer of the log subcommand, with laps and without logging anything
+type stopwatchModel struct {
+	model
+	laps []time.Duration // Time elapsed at each lap
+}
+
+func (s stopwatchModel) Init() tea.Cmd {
+	return tickCmd()
+}
+
+func (s stopwatchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		s.width = msg.Width
+		s.height = msg.Height
+	case tea.KeyMsg:
+		now := time.Now()
+		switch msg.String() {
+		case "q", "ctrl+c":
+			if !s.paused {
+				s.model = s.pause(now)
+			}
+			s.running = false
+			s.quitting = true
+			return s, tea.Quit
+		case "p", " ":
+			if s.paused {
+				s.model = s.resume(now)
+			} else {
+				s.model = s.pause(now)
+			}
+		case "l", "enter":
+			if !s.paused {
+				s.elapsed = s.activeTime(now)
+				s.laps = append(s.laps, s.elapsed)
+			}
+		}
+	case tickMsg:
+		if s.running && !s.paused {
+			s.elapsed = s.activeTime(time.Now())
+		}
+		return s, tickCmd()
+	}
+	return s, nil
+}
+
+func (s stopwatchModel) View() string {
+	if s.quitting {
+		return strings.TrimPrefix(s.lapsView(len(s.laps)), "\n") + fmt.Sprintf(tr("Stopwatch stopped at %s, nothing was logged\n"), stopwatchTime(s.elapsed))
+	}
+	view := s.layoutTimer(false, 0)
+	help := "\n" + cutLine(tr("p pause/resume  l lap  q quit"), s.width) + "\n"
+
+	// The last laps that fit the terminal, below the timer
+	count := len(s.laps)
+	if s.height > 0 {
+		count = min(count, s.height-strings.Count(view+help, "\n")-2)
+	}
+	return view + s.lapsView(count) + help
+}
+
+// lapsView returns the last count laps, one per line with the time
+// elapsed and the time since the previous lap
+func (s stopwatchModel) lapsView(count int) string {
+	if count <= 0 {
+		return ""
+	}
+	view := "\n"
+	for i := len(s.laps) - count; i < len(s.laps); i++ {
+		split := s.laps[i]
+		if i > 0 {
+			split -= s.laps[i-1]
+		}
+		line := fmt.Sprintf(tr("Lap %d: %s (+%s)"), i+1, stopwatchTime(s.laps[i]), stopwatchTime(split))
+		view += cutLine(line, s.width) + "\n"
+	}
+	return view
+}
+
+// stopwatchTime returns d as hours, minutes and seconds, e.g. 01:05:09
+func stopwatchTime(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+)
+
+// Store holds the records of a log. Commands reach it through readRecords,
+// appendRecords, rewriteRecords and friends, which open the store of the
+// log location, so a new backend only needs a Store implementation and an
+// entry in storeBackends.
+type Store interface {
+	// Append adds records at the end of the log, creating it if needed
+	Append(records []Record) error
+	// List calls fn for each valid record, in log order, and onBad for
+	// each malformed one
+	List(onBad talogo.BadRecordFunc, fn func(Record) error) error
+	// Query calls fn for each valid record starting in [from, to), in log
+	// order. A zero from or to leaves that side unbounded. Malformed
+	// records are reported to stderr and skipped.
+	Query(from, to time.Time, fn func(Record) error) error
+	// Rewrite replaces the content of the log with records, keeping the
+	// previous version in a .bak file
+	Rewrite(records []Record) error
+}
+
+// storeBackends maps the backend names, used as schemes of log locations
+// like "sqlite:~/talogo.db", to the function opening a store at a path
+var storeBackends = map[string]func(path string) Store{
+	"csv":    func(path string) Store { return csvStore{path} },
+	"jsonl":  func(path string) Store { return jsonlStore{path} },
+	"sqlite": func(path string) Store { return sqliteStore{path} },
+}
+
+// storeExtensions maps log file extensions to the backend of locations
+// without a scheme. Any other file is CSV.
+var storeExtensions = map[string]string{
+	".jsonl":   "jsonl",
+	".db":      "sqlite",
+	".sqlite":  "sqlite",
+	".sqlite3": "sqlite",
+}
+
+// parseLogLocation splits a log location, a path optionally prefixed by a
+// backend scheme such as "sqlite:" or "sqlite://", in its backend and
+// path. Locations without a scheme pick the backend from their extension.
+func parseLogLocation(location string) (backend, path string) {
+	// Single letter prefixes are Windows drives, not schemes
+	if i := strings.Index(location, ":"); i > 1 {
+		if _, ok := storeBackends[location[:i]]; ok {
+			return location[:i], expandHome(strings.TrimPrefix(location[i+1:], "//"))
+		}
+	}
+	if backend, ok := storeExtensions[strings.ToLower(filepath.Ext(location))]; ok {
+		return backend, location
+	}
+	return "csv", location
+}
+
+// logPath returns the file holding the log location, next to which its
+// side files (index, backups, sync state) are kept
+func logPath(location string) string {
+	_, path := parseLogLocation(location)
+	return path
+}
+
+// absLogLocation returns the log location with its path made absolute,
+// keeping its backend scheme if any, so it names the same log from any
+// working directory
+func absLogLocation(location string) (string, error) {
+	backend, path := parseLogLocation(location)
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve log path: %v", err)
+	}
+	if strings.HasPrefix(location, backend+":") {
+		return backend + ":" + abs, nil
+	}
+	return abs, nil
+}
+
+// openStore returns the store of the log location
+func openStore(location string) Store {
+	backend, path := parseLogLocation(location)
+	return storeBackends[backend](path)
+}
+
+// queryByScanning implements Query for stores that can only be read in
+// full, filtering the records listed by store
+func queryByScanning(store Store, from, to time.Time, fn func(Record) error) error {
+	return store.List(skipBadRecord, func(record Record) error {
+		if (!from.IsZero() && record.Start.Before(from)) || (!to.IsZero() && !record.Start.Before(to)) {
+			return nil
+		}
+		return fn(record)
+	})
+}
+
+// queryDays returns the records of the log file that may fall in the days
+// from to to (YYYY-MM-DD, inclusive) for days starting dayStart after
+// midnight. Records starting the day before are included, since they may
+// cross into the period; callers still filter the records by day.
+func queryDays(logFile, from, to string, dayStart time.Duration) ([]Record, error) {
+	first, err := time.ParseInLocation("2006-01-02", from, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", from)
+	}
+	last, err := time.ParseInLocation("2006-01-02", to, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", to)
+	}
+	return queryRecords(logFile, first.AddDate(0, 0, -1).Add(dayStart), last.AddDate(0, 0, 1).Add(dayStart))
+}
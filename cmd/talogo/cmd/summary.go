@@ -1,26 +1,50 @@
 package cmd
 
 import (
-	"encoding/csv"
 	"fmt"
 	"os"
-	"sort"
 	"strings"
 	"time"
 
+	"github.com/artilugio0/talogo/pkg/talogo"
 	"github.com/spf13/cobra"
 )
 
 var (
-	summaryCmdLogFile string
+	summaryCmdLogFile  string
+	summaryCmdEarnings bool
+	summaryCmdTZ       string
+	summaryCmdSources  []string
+	summaryCmdNoIndex  bool
+	summaryCmdExclude  []string
+	summaryCmdBy       string
+	summaryCmdHosts    []string
+	summaryCmdOutput   string
+	summaryCmdOverlaps bool
+	summaryCmdUnbilled bool
 )
 
 // TaskNode represents a node in the task hierarchy
-type TaskNode struct {
-	Name      string
-	Duration  time.Duration
-	Children  map[string]*TaskNode
-	TotalTime time.Duration // Includes children
+type TaskNode = talogo.TaskNode
+
+// summaryOptions controls how the summary is computed and printed
+type summaryOptions struct {
+	Earnings bool
+	Config   *Config
+	Location *time.Location // Zone used to bucket records by day
+	Sources  []string       // Only include records with these sources
+	NoIndex  bool           // Always parse the whole log
+	Exclude  []string       // Task paths left out of the report
+	ByTag    bool           // Aggregate by tag instead of task hierarchy
+	Hosts    []string       // Only include records tracked on these hosts
+	DayStart time.Duration  // Time after midnight at which days begin
+	Unbilled bool           // Leave out records marked as billed
+	// CountOverlaps counts the time parallel records share with others
+	// twice in day totals
+	CountOverlaps bool
+	// DailyGoal is the time meant to be tracked each day, compared with
+	// the day totals of the text output if not 0
+	DailyGoal time.Duration
 }
 
 // summaryCmd defines the summary subcommand
@@ -28,158 +52,191 @@ var summaryCmd = &cobra.Command{
 	Use:   "summary",
 	Short: "Generate a report of total hours spent per task and subtasks per day",
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := generateSummary(summaryCmdLogFile); err != nil {
-			fmt.Fprintf(os.Stderr, "Error generating summary: %v\n", err)
+		loc, err := reportLocation(summaryCmdTZ)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error generating summary: %v\n"), err)
 			os.Exit(1)
 		}
-	},
-}
-
-func init() {
-	summaryCmd.Flags().StringVarP(&summaryCmdLogFile, "file", "f", "./talogo.csv", "Log file to read")
-	rootCmd.AddCommand(summaryCmd)
-}
 
-// generateSummary reads the CSV and prints the daily task summary
-func generateSummary(logFile string) error {
-	file, err := os.Open(logFile)
-	if err != nil {
-		return fmt.Errorf("failed to open CSV file: %v", err)
-	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-	reader.LazyQuotes = true       // Allow relaxed quoting
-	reader.FieldsPerRecord = -1    // Allow variable number of fields
-	reader.TrimLeadingSpace = true // Trim leading spaces
-
-	records, err := reader.ReadAll()
-	if err != nil {
-		return fmt.Errorf("failed to read CSV: %v", err)
-	}
-
-	if len(records) <= 1 {
-		fmt.Println("No data in CSV file (only header or empty)")
-		return nil
-	}
-
-	// Group records by day
-	dailyTasks := make(map[string]map[string]*TaskNode) // date -> root task -> hierarchy
-	for i, record := range records {
-		if i == 0 {
-			continue // Skip header row
+		if summaryCmdBy != "task" && summaryCmdBy != "tag" {
+			fmt.Fprintf(os.Stderr, tr("Error generating summary: invalid --by value %q (expected task or tag)\n"), summaryCmdBy)
+			os.Exit(1)
 		}
 
-		// Ensure record has at least start_time, end_time
-		if len(record) < 2 {
-			fmt.Fprintf(os.Stderr, "Skipping malformed record on line %d: too few fields (%d)\n", i+1, len(record))
-			continue
+		reporter, err := reporterFor(summaryCmdOutput)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error generating summary: %v\n"), err)
+			os.Exit(1)
 		}
 
-		// Parse start time
-		startTime, err := time.Parse(time.RFC3339, record[0])
+		dayStart, err := configuredDayStart()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Skipping record on line %d: invalid start time (%s)\n", i+1, record[0])
-			continue
+			fmt.Fprintf(os.Stderr, tr("Error generating summary: %v\n"), err)
+			os.Exit(1)
 		}
 
-		// Parse end time
-		endTime, err := time.Parse(time.RFC3339, record[1])
+		opts := summaryOptions{
+			Earnings: summaryCmdEarnings,
+			Location: loc,
+			Sources:  summaryCmdSources,
+			NoIndex:  summaryCmdNoIndex,
+			Exclude:  summaryCmdExclude,
+			ByTag:    summaryCmdBy == "tag",
+			Hosts:    summaryCmdHosts,
+			DayStart: dayStart,
+			Unbilled: summaryCmdUnbilled,
+
+			CountOverlaps: summaryCmdOverlaps,
+		}
+		config, err := loadConfig()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Skipping record on line %d: invalid end time (%s)\n", i+1, record[1])
-			continue
+			fmt.Fprintf(os.Stderr, tr("Error generating summary: %v\n"), err)
+			os.Exit(1)
+		}
+		if opts.Earnings {
+			opts.Config = config
+		}
+		if opts.DailyGoal, _, err = config.goals(); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error generating summary: %v\n"), err)
+			os.Exit(1)
 		}
 
-		// Calculate duration
-		duration := endTime.Sub(startTime)
-		if duration < 0 {
-			fmt.Fprintf(os.Stderr, "Skipping record on line %d: negative duration\n", i+1)
-			continue
+		if err := generateSummary(summaryCmdLogFile, reporter, opts); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error generating summary: %v\n"), err)
+			os.Exit(1)
 		}
+	},
+}
 
-		// Get date in YYYY-MM-DD format
-		dateStr := startTime.Format("2006-01-02")
+func init() {
+	summaryCmd.Flags().StringVarP(&summaryCmdLogFile, "file", "f", defaultLogFile(), "Log file to read")
+	summaryCmd.Flags().BoolVar(&summaryCmdEarnings, "earnings", false, "Show money earned per task using the rates in the config file")
+	summaryCmd.Flags().StringSliceVar(&summaryCmdSources, "source", nil, "Only include records created by these sources (interactive, add, import, auto, recovered, api, unknown)")
+	summaryCmd.Flags().StringArrayVar(&summaryCmdExclude, "exclude", nil, "Leave a task and its subtasks out of the report (e.g. \"breaks\" or \"work/lunch\")")
+	summaryCmd.Flags().StringSliceVar(&summaryCmdHosts, "host", nil, "Only include records tracked on these hosts")
+	summaryCmd.Flags().StringVar(&summaryCmdBy, "by", "task", "Aggregate time by task or by tag")
+	summaryCmd.Flags().BoolVar(&summaryCmdNoIndex, "no-index", false, "Ignore the aggregate index and parse the whole log")
+	summaryCmd.Flags().StringVar(&summaryCmdTZ, "tz", "", "Time zone used to group records by day (e.g. Europe/Madrid)")
+	summaryCmd.Flags().StringVar(&summaryCmdOutput, "output", "text", "Output format ("+strings.Join(reporterNames(), ", ")+")")
+	summaryCmd.Flags().BoolVar(&summaryCmdOverlaps, "count-overlaps", false, "Count the time parallel sessions share with others twice in day totals")
+	summaryCmd.Flags().BoolVar(&summaryCmdUnbilled, "unbilled", false, "Leave out the sessions marked as billed")
+	rootCmd.AddCommand(summaryCmd)
+}
 
-		// Initialize daily task map
-		if _, exists := dailyTasks[dateStr]; !exists {
-			dailyTasks[dateStr] = make(map[string]*TaskNode)
-		}
+// generateSummary reads the log and prints the daily task summary with
+// reporter
+func generateSummary(logFile string, reporter Reporter, opts summaryOptions) error {
+	days, total, matched, err := buildSummary(logFile, opts)
+	if err != nil {
+		return err
+	}
 
-		// Build task hierarchy
-		current := dailyTasks[dateStr]
-		var leaf *TaskNode
-		for j := 2; j < len(record); j++ {
-			if record[j] == "" {
-				break // No more titles
-			}
-			taskName := record[j]
-			if taskName == "" {
-				continue // Skip empty task names
-			}
-			if _, exists := current[taskName]; !exists {
-				current[taskName] = &TaskNode{
-					Name:     taskName,
-					Children: make(map[string]*TaskNode),
-				}
-			}
-			current[taskName].TotalTime += duration
-			leaf = current[taskName]
-			current = current[taskName].Children
+	// Machine readable formats get an empty summary instead
+	if _, isText := reporter.(textReporter); isText {
+		if total == 0 {
+			fmt.Println(tr("No data in CSV file (only header or empty)"))
+			return nil
 		}
-		if leaf != nil {
-			leaf.Duration += duration
+		if matched == 0 {
+			fmt.Println(tr("No records match the given filters"))
+			return nil
 		}
 	}
 
-	// Sort dates
-	var dates []string
-	for date := range dailyTasks {
-		dates = append(dates, date)
+	return reporter.Report(os.Stdout, days, opts)
+}
+
+// buildSummary aggregates the records of the log file into one task tree
+// per day, keyed by date. It also returns the number of records in the log
+// and how many of them matched the filters of opts.
+func buildSummary(logFile string, opts summaryOptions) (map[string]*TaskNode, int, int, error) {
+	days := make(map[string]*TaskNode) // date -> day node whose children are the root tasks
+	total, matched := 0, 0
+
+	// The index stores totals by the logged date and task, so it can only
+	// be used when records are neither filtered nor moved to another zone,
+	// nor overlapped by parallel records counted once, nor earning the
+	// rates of their tags, which it does not keep
+	unfiltered := len(opts.Sources) == 0 && len(opts.Hosts) == 0 && opts.Location == nil && !opts.ByTag && !opts.Unbilled
+	if opts.Earnings && len(opts.Config.TagRates) > 0 {
+		unfiltered = false
 	}
-	sort.Strings(dates)
-
-	// Print report
-	for _, date := range dates {
-		fmt.Printf("Date: %s\n", date)
-		tasks := dailyTasks[date]
-		var taskNames []string
-		for name := range tasks {
-			taskNames = append(taskNames, name)
-		}
-		sort.Strings(taskNames)
+	index, fresh := loadIndex(logFile)
+	if fresh && index.Parallel && !opts.CountOverlaps {
+		unfiltered = false
+	}
+	if unfiltered && fresh && index.DayStart == opts.DayStart && !opts.NoIndex {
+		index.forEach(func(record Record) {
+			if record.MatchesTask(opts.Exclude) {
+				return
+			}
+			matched++
+			addToSummary(days, record, opts)
+		})
+		total = index.Records
+	} else {
+		// Group records by day while streaming the log, so only the
+		// aggregated totals are kept in memory
+		newIndex := newLogIndex(opts.DayStart)
+		var parallel []Record
+		err := scanRecords(logFile, func(record Record) error {
+			total++
+			newIndex.add(record)
+			if !opts.matches(record) {
+				return nil
+			}
+			matched++
 
-		// Calculate total hours for the day
-		var totalDayHours float64
-		for _, taskName := range taskNames {
-			totalDayHours += tasks[taskName].TotalTime.Hours()
+			if opts.Location != nil {
+				record.Start = record.Start.In(opts.Location)
+				record.End = record.End.In(opts.Location)
+			}
+			if isParallel(record) {
+				parallel = append(parallel, record)
+			}
+			addToSummary(days, record, opts)
+			return nil
+		})
+		if err != nil {
+			return nil, 0, 0, err
 		}
-		fmt.Printf("Total: %.2f hs\n", totalDayHours)
-
-		for _, taskName := range taskNames {
-			task := tasks[taskName]
-			fmt.Printf("  %s: %.2f hs\n", taskName, task.TotalTime.Hours())
-			printSubtasks(task.Children, 4)
+		saveIndex(logFile, newIndex)
+
+		// Parallel records are few, the records they overlap are read
+		// again rather than kept while streaming
+		if !opts.CountOverlaps {
+			overlaps, err := loggedParallelOverlaps(logFile, parallel, opts.matches, opts.DayStart)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+			discountOverlaps(days, nil, overlaps)
 		}
-		fmt.Println()
 	}
 
-	return nil
+	return days, total, matched, nil
 }
 
-// printSubtasks recursively prints subtasks with indentation
-func printSubtasks(tasks map[string]*TaskNode, indent int) {
-	if len(tasks) == 0 {
-		return
+// matches reports whether record passes the source, host, task and
+// billing filters of opts
+func (opts summaryOptions) matches(record Record) bool {
+	if len(opts.Sources) > 0 && !record.MatchesSource(opts.Sources) {
+		return false
 	}
-	var taskNames []string
-	for name := range tasks {
-		taskNames = append(taskNames, name)
+	if opts.Unbilled && isBilled(record) {
+		return false
 	}
-	sort.Strings(taskNames)
+	return record.MatchesHost(opts.Hosts) && !record.MatchesTask(opts.Exclude)
+}
 
-	for _, taskName := range taskNames {
-		task := tasks[taskName]
-		fmt.Printf("%s%s: %.2f hs\n", strings.Repeat(" ", indent), taskName, task.TotalTime.Hours())
-		printSubtasks(task.Children, indent+2)
+// addToSummary adds the duration of record to the task hierarchy of its
+// day, or to each of its tags when aggregating by tag
+func addToSummary(days map[string]*TaskNode, record Record, opts summaryOptions) {
+	aggregation := talogo.SummaryOptions{DayStart: opts.DayStart, ByTag: opts.ByTag}
+	if opts.Earnings {
+		aggregation.Rate = func(record Record) float64 {
+			rate, _ := opts.Config.rateFor(record.Titles, record.Tags)
+			return rate
+		}
 	}
+	talogo.AddToSummary(days, record, aggregation)
 }
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// useConfig makes config the loaded config for the rest of the test
+func useConfig(t *testing.T, config *Config) {
+	t.Helper()
+	previous := loadedConfig
+	loadedConfig = config
+	t.Cleanup(func() { loadedConfig = previous })
+}
+
+// writeTestLog writes a CSV log with content to a temporary directory and
+// returns its path
+func writeTestLog(t *testing.T, content string) string {
+	t.Helper()
+	logFile := filepath.Join(t.TempDir(), "talogo.csv")
+	if err := os.WriteFile(logFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return logFile
+}
+
+func TestBuildSummaryEarningsWithIndex(t *testing.T) {
+	config := &Config{
+		Rates:    map[string]float64{"work/billable": 60},
+		TagRates: map[string]float64{"client": 50},
+	}
+	useConfig(t, config)
+	logFile := writeTestLog(t, `# talogo schema v6
+id,start_time,end_time,duration_seconds,source,tags,notes,host,user,title1,title2
+a1,2026-09-01T09:00:00Z,2026-09-01T11:00:00Z,7200,add,client,,,,work,support
+a2,2026-09-01T11:00:00Z,2026-09-01T12:00:00Z,3600,add,,,,,work,billable
+`)
+
+	earnings := func(noIndex bool) float64 {
+		t.Helper()
+		days, _, _, err := buildSummary(logFile, summaryOptions{Earnings: true, Config: config, NoIndex: noIndex})
+		if err != nil {
+			t.Fatal(err)
+		}
+		total := 0.0
+		for _, day := range days {
+			total += day.Earnings
+		}
+		return total
+	}
+
+	unindexed := earnings(true)
+	if unindexed != 160 {
+		t.Errorf("earnings without the index = %v, want 160", unindexed)
+	}
+	// The first summary writes the index, the second could read it
+	earnings(false)
+	if _, fresh := loadIndex(logFile); !fresh {
+		t.Fatal("the index was not written")
+	}
+	if indexed := earnings(false); indexed != unindexed {
+		t.Errorf("earnings with a fresh index = %v, want %v as without it", indexed, unindexed)
+	}
+
+	// Without tag rates the index holds everything earnings need
+	config.TagRates = nil
+	if indexed, unindexed := earnings(false), earnings(true); indexed != unindexed || indexed != 60 {
+		t.Errorf("earnings without tag rates = %v with the index and %v without, want 60", indexed, unindexed)
+	}
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncRemoteCmdLogFile  string
+	syncRemoteCmdStrategy string
+	syncRemoteCmdPrefer   string
+)
+
+// syncCmd groups the commands synchronizing the log with other systems
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Synchronize the log with remote storage and other services",
+}
+
+var syncRemoteCmd = &cobra.Command{
+	Use:   "remote",
+	Short: "Synchronize the log with the S3 bucket or WebDAV server in the config file",
+	Long: `Synchronize the log with the S3 bucket or WebDAV server configured in the
+[remote] section of the config file.
+
+Strategies:
+  merge  records missing on either side are appended to the other one
+  lww    last write wins: the most recently modified copy replaces the other`,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := loadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		store, err := newRemoteStore(config.Remote)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+
+		switch syncRemoteCmdStrategy {
+		case "merge":
+			err = syncRemoteMerge(syncRemoteCmdLogFile, store, syncRemoteCmdPrefer)
+		case "lww":
+			err = syncRemoteLastWriteWins(syncRemoteCmdLogFile, store)
+		default:
+			err = fmt.Errorf("unknown strategy %q (expected merge or lww)", syncRemoteCmdStrategy)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error syncing: %v\n"), err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	syncRemoteCmd.Flags().StringVarP(&syncRemoteCmdLogFile, "file", "f", defaultLogFile(), "Log file to synchronize")
+	syncRemoteCmd.Flags().StringVar(&syncRemoteCmdStrategy, "strategy", "merge", "Synchronization strategy (merge, lww)")
+	syncRemoteCmd.Flags().StringVar(&syncRemoteCmdPrefer, "prefer", "", "Resolve conflicting edits without asking, keeping the local or other (remote) version")
+	syncCmd.AddCommand(syncRemoteCmd)
+	rootCmd.AddCommand(syncCmd)
+}
+
+// syncRemoteMerge appends the records only found remotely to the local
+// log, but for those deleted locally, then uploads the local log if the
+// remote lacks any of its records or has deleted ones.
+// Records edited differently on each side are resolved as prefer says, or
+// interactively if prefer is empty.
+func syncRemoteMerge(logFile string, store remoteStore, prefer string) error {
+	data, _, exists, err := store.Get()
+	if err != nil {
+		return err
+	}
+
+	var remote []Record
+	if exists {
+		if remote, err = parseLogData(data, logFile); err != nil {
+			return fmt.Errorf("failed to parse remote log: %v", err)
+		}
+	}
+
+	var local []Record
+	localExists := fileExists(logPath(logFile))
+	if localExists {
+		if local, err = readRecordsStrict(logFile); err != nil {
+			return err
+		}
+	}
+
+	deleted, err := deletedKeys(logFile, local)
+	if err != nil {
+		return err
+	}
+	result := mergeRecords(local, remote, deleted)
+	pushed := mergeRecords(remote, local, nil).Added
+
+	merged, tookOther, err := resolveConflicts(result.Merged, result.Conflicts, prefer)
+	if err != nil {
+		return err
+	}
+	pulled := result.Added + tookOther
+	// Conflicts resolved with the local version must reach the remote
+	pushed += len(result.Conflicts) - tookOther
+
+	if pulled > 0 {
+		if localExists {
+			err = rewriteRecords(logFile, merged)
+		} else {
+			err = appendRecords(logFile, merged)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// The remote copy still has the records deleted locally until it is
+	// replaced by the local log
+	if (pushed > 0 || result.Deleted > 0 || !exists) && fileExists(logPath(logFile)) {
+		if err := uploadLogFile(logFile, store); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf(tr("Pulled %d records, pushed %d records\n"), pulled, pushed)
+	return nil
+}
+
+// syncRemoteLastWriteWins replaces the oldest of the local and remote logs
+// with the newest one
+func syncRemoteLastWriteWins(logFile string, store remoteStore) error {
+	data, remoteModTime, exists, err := store.Get()
+	if err != nil {
+		return err
+	}
+
+	// The log is replaced as a whole, whatever its backend
+	path := logPath(logFile)
+	info, statErr := os.Stat(path)
+	switch {
+	case !exists && statErr != nil:
+		fmt.Println(tr("Nothing to synchronize, neither the local nor the remote log exist"))
+		return nil
+	case !exists || (statErr == nil && !info.ModTime().Before(remoteModTime)):
+		if err := uploadLogFile(logFile, store); err != nil {
+			return err
+		}
+		fmt.Println(tr("Local log is newer, uploaded it"))
+	case statErr != nil:
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create log directory: %v", err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write log file: %v", err)
+		}
+		fmt.Println(tr("Downloaded remote log"))
+	default:
+		// Rewritten as records, so the replaced records go to the trash
+		// and the checksums follow the new content
+		remote, err := parseLogData(data, logFile)
+		if err != nil {
+			return fmt.Errorf("failed to parse remote log: %v", err)
+		}
+		if err := rewriteRecords(logFile, remote); err != nil {
+			return err
+		}
+		fmt.Printf(tr("Remote log is newer, replaced the local one (previous version kept in %s.bak)\n"), path)
+	}
+	return nil
+}
+
+// uploadLogFile uploads the content of the local log to store
+func uploadLogFile(logFile string, store remoteStore) error {
+	data, err := os.ReadFile(logPath(logFile))
+	if err != nil {
+		return fmt.Errorf("failed to read log file: %v", err)
+	}
+	return store.Put(data)
+}
+
+// fileExists reports whether path exists
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
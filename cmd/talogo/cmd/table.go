@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/charmbracelet/x/term"
+)
+
+// tableBorders is the value of the global --borders flag, drawing the
+// borders of the tables of list and stats
+var tableBorders bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&tableBorders, "borders", false, "Draw borders around the tables of list, stats and estimate report")
+}
+
+// printTable prints rows as a table with aligned columns under headers,
+// left out with --quiet. The columns whose indexes are in right are
+// aligned to the right, for numbers. On a terminal too narrow for it, the
+// widest columns are truncated.
+func printTable(headers []string, rows [][]string, right ...int) {
+	alignRight := make(map[int]bool, len(right))
+	for _, column := range right {
+		alignRight[column] = true
+	}
+	columns := len(headers)
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+
+	build := func() *table.Table {
+		t := table.New().Rows(rows...).Wrap(false).
+			StyleFunc(func(row, column int) lipgloss.Style {
+				style := lipgloss.NewStyle()
+				if tableBorders {
+					style = style.Padding(0, 1)
+				} else if column < columns-1 {
+					style = style.PaddingRight(2)
+				}
+				if row == table.HeaderRow {
+					style = style.Bold(true)
+				}
+				if alignRight[column] {
+					style = style.Align(lipgloss.Right)
+				}
+				return style
+			})
+		withHeaders := !quiet && len(headers) > 0
+		if withHeaders {
+			t.Headers(headers...)
+		}
+		if !tableBorders {
+			// Tables without headers draw no header border, but lipgloss
+			// counts it in their height, which loses their last row if
+			// it is disabled
+			t.BorderTop(false).BorderBottom(false).BorderLeft(false).BorderRight(false).
+				BorderColumn(false).BorderHeader(!withHeaders)
+		}
+		return t
+	}
+
+	t := build()
+	out := t.String()
+	// A set width also stretches narrower tables, so it is only set to
+	// shrink them
+	if width, _, err := term.GetSize(os.Stdout.Fd()); err == nil && width > 0 && lipgloss.Width(out) > width {
+		out = build().Width(width).String()
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// templateCmd defines the template subcommand
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage task templates, logged with 'talogo log @NAME'",
+}
+
+var templateAddCmd = &cobra.Command{
+	Use:   "add NAME TITLE...",
+	Short: "Save the titles of a task as a template",
+	Long: `Save the titles of a task as a template, so 'talogo log @NAME' logs them.
+Further arguments to log are added as subtasks.
+
+Arguments are split at slashes into titles. A single argument without
+slashes is split at spaces instead, so these are the same:
+
+  talogo template add standup "work meetings standup"
+  talogo template add standup work/meetings/standup
+  talogo template add standup work meetings standup`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := strings.TrimPrefix(args[0], "@")
+		if name == "" || strings.ContainsAny(name, ". ") {
+			fmt.Fprintf(os.Stderr, tr("Invalid template name %q: names cannot be empty or contain dots or spaces\n"), args[0])
+			os.Exit(1)
+		}
+
+		var titles []string
+		if len(args) == 2 && !strings.Contains(args[1], "/") {
+			titles = strings.Fields(args[1])
+		} else {
+			for _, arg := range args[1:] {
+				titles = append(titles, strings.Split(strings.Trim(arg, "/"), "/")...)
+			}
+		}
+		titles, err := checkTitles(titles)
+		if err == nil && len(titles) == 0 {
+			err = fmt.Errorf("no titles given")
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error adding template: %v\n"), err)
+			os.Exit(1)
+		}
+
+		if err := updateConfigTable([]string{"templates", name}, titles); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error adding template: %v\n"), err)
+			os.Exit(1)
+		}
+		printInfo("Added template @%s for %s\n", name, strings.Join(titles, " / "))
+	},
+}
+
+var templateRemoveCmd = &cobra.Command{
+	Use:   "remove NAME",
+	Short: "Remove a template",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := updateConfigTable([]string{"templates", strings.TrimPrefix(args[0], "@")}, nil); err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error removing template: %v\n"), err)
+			os.Exit(1)
+		}
+	},
+}
+
+var templateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the templates and their titles",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := loadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error listing templates: %v\n"), err)
+			os.Exit(1)
+		}
+
+		var names []string
+		for name := range config.Templates {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			fmt.Printf("@%s\t%s\n", name, strings.Join(config.Templates[name], " / "))
+		}
+	},
+}
+
+func init() {
+	templateCmd.AddCommand(templateAddCmd)
+	templateCmd.AddCommand(templateRemoveCmd)
+	templateCmd.AddCommand(templateListCmd)
+	rootCmd.AddCommand(templateCmd)
+}
@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	timelineCmdLogFile string
+	timelineCmdDate    string
+	timelineCmdDays    int
+	timelineCmdWidth   int
+	timelineCmdNoColor bool
+)
+
+// themes are the palettes of colored output by the theme of the config,
+// ANSI colors given to the top-level tasks in turn. Light terminals get
+// no bright colors, hard to read on a light background.
+var themes = map[string][]string{
+	"default": {"34", "32", "33", "35", "36", "31", "94", "92", "93", "95", "96", "91"},
+	"light":   {"34", "32", "35", "31", "36", "33", "90"},
+	"none":    nil,
+}
+
+// timelineCmd defines the timeline subcommand
+var timelineCmd = &cobra.Command{
+	Use:   "timeline",
+	Short: "Draw the sessions of a day as bars along a 24h axis",
+	Long: `Draw the sessions of each day as horizontal bars along a 24 hour axis, one
+lane per top-level task, so gaps and overlaps stand out. The first lane
+shows the whole day: · untracked, █ tracked, ▓ tracked more than once.
+
+Sessions of the same task that overlap are drawn on lanes of their own.
+Colors follow the theme of the config, and are left out when the output
+is not a terminal or NO_COLOR is set.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if timelineCmdDays < 1 {
+			fmt.Fprintln(os.Stderr, tr("Error: --days must be at least 1"))
+			os.Exit(1)
+		}
+		if timelineCmdWidth < 24 {
+			fmt.Fprintln(os.Stderr, tr("Error: --width must be at least 24"))
+			os.Exit(1)
+		}
+		to, err := time.ParseInLocation("2006-01-02", timelineCmdDate, time.Local)
+		if timelineCmdDate == "" {
+			to, err = time.ParseInLocation("2006-01-02", time.Now().Format("2006-01-02"), time.Local)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: invalid date %q (expected YYYY-MM-DD)\n"), timelineCmdDate)
+			os.Exit(1)
+		}
+		from := to.AddDate(0, 0, -(timelineCmdDays - 1))
+
+		dayStart, err := configuredDayStart()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		records, err := queryDays(timelineCmdLogFile, from.Format("2006-01-02"), to.Format("2006-01-02"), dayStart)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error reading log: %v\n"), err)
+			os.Exit(1)
+		}
+
+		config, err := loadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		palette, err := config.palette()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error: %v\n"), err)
+			os.Exit(1)
+		}
+		if timelineCmdNoColor || os.Getenv("NO_COLOR") != "" {
+			palette = nil
+		}
+		if info, err := os.Stdout.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			palette = nil
+		}
+		printTimeline(os.Stdout, records, from, to, dayStart, timelineCmdWidth, palette)
+	},
+}
+
+func init() {
+	timelineCmd.Flags().StringVarP(&timelineCmdLogFile, "file", "f", defaultLogFile(), "Log file to read")
+	timelineCmd.Flags().StringVar(&timelineCmdDate, "date", "", "Day to draw, or the last of --days (YYYY-MM-DD, default today)")
+	timelineCmd.Flags().IntVar(&timelineCmdDays, "days", 1, "Number of days to draw, ending on --date")
+	timelineCmd.Flags().IntVar(&timelineCmdWidth, "width", 96, "Width of the 24h axis in columns")
+	timelineCmd.Flags().BoolVar(&timelineCmdNoColor, "no-color", false, "Draw without colors")
+	rootCmd.AddCommand(timelineCmd)
+}
+
+// timelineLane is a row of the timeline, holding sessions of a top-level
+// task that do not overlap
+type timelineLane struct {
+	task  string
+	cells []int // Sessions covering each column
+}
+
+// printTimeline writes the timeline of each day from the first to the
+// last, with days beginning dayStart after midnight, coloring the tasks
+// with the palette if not empty
+func printTimeline(w io.Writer, records []Record, first, last time.Time, dayStart time.Duration, width int, palette []string) {
+	days := make(map[string][]Record)
+	tasks := make(map[string]bool)
+	for _, record := range records {
+		for _, part := range talogo.SplitByDay(record, dayStart) {
+			date := talogo.DayOf(part.Start, dayStart)
+			if date < first.Format("2006-01-02") || date > last.Format("2006-01-02") {
+				continue
+			}
+			days[date] = append(days[date], part)
+			tasks[timelineTask(part)] = true
+		}
+	}
+
+	// Colors follow the order of the task names, so a task keeps its
+	// color across days
+	var names []string
+	for task := range tasks {
+		names = append(names, task)
+	}
+	sort.Strings(names)
+	colors := make(map[string]string)
+	for i, task := range names {
+		if len(palette) > 0 {
+			colors[task] = palette[i%len(palette)]
+		}
+	}
+
+	labelWidth := len("tracked")
+	for task := range tasks {
+		labelWidth = max(labelWidth, min(len([]rune(task)), 20))
+	}
+
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		dayRecords := days[date]
+		sort.SliceStable(dayRecords, func(i, j int) bool {
+			return dayRecords[i].Start.Before(dayRecords[j].Start)
+		})
+
+		var total time.Duration
+		for _, record := range dayRecords {
+			total += record.Duration()
+		}
+		fmt.Fprintf(w, "%s  %.2f hs\n", date, total.Hours())
+		fmt.Fprintf(w, "%s  %s\n", padLabel("", labelWidth), timelineAxis(width, dayStart))
+
+		begin := day.Add(dayStart)
+		tracked := make([]int, width)
+		var lanes []*timelineLane
+		for _, record := range dayRecords {
+			from, to := timelineColumns(record, begin, width)
+			task := timelineTask(record)
+
+			var lane *timelineLane
+			for _, l := range lanes {
+				if l.task == task && !l.covers(from, to) {
+					lane = l
+					break
+				}
+			}
+			if lane == nil {
+				lane = &timelineLane{task: task, cells: make([]int, width)}
+				lanes = append(lanes, lane)
+			}
+			for i := from; i < to; i++ {
+				lane.cells[i]++
+				tracked[i]++
+			}
+		}
+
+		fmt.Fprintf(w, "%s  %s\n", padLabel("tracked", labelWidth), timelineBar(tracked, ""))
+		sort.SliceStable(lanes, func(i, j int) bool {
+			return lanes[i].task < lanes[j].task
+		})
+		for i, lane := range lanes {
+			label := ""
+			if i == 0 || lanes[i-1].task != lane.task {
+				label = lane.task
+			}
+			fmt.Fprintf(w, "%s  %s\n", padLabel(label, labelWidth), timelineBar(lane.cells, colors[lane.task]))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// covers reports whether any column in [from, to) of the lane is taken
+func (l *timelineLane) covers(from, to int) bool {
+	for i := from; i < to; i++ {
+		if l.cells[i] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// timelineTask returns the top-level task of record
+func timelineTask(record Record) string {
+	if len(record.Titles) == 0 {
+		return "(untitled)"
+	}
+	return talogo.SanitizeTitle(record.Titles[0])
+}
+
+// timelineColumns returns the columns [from, to) covered by record on a
+// day beginning at begin. Sessions shorter than a column take one.
+func timelineColumns(record Record, begin time.Time, width int) (int, int) {
+	column := 24 * time.Hour / time.Duration(width)
+	from := int(record.Start.Sub(begin) / column)
+	to := int((record.End.Sub(begin) + column - 1) / column)
+	from = min(max(from, 0), width-1)
+	to = min(max(to, from+1), width)
+	return from, to
+}
+
+// timelineAxis returns the hour labels of a day beginning dayStart after
+// midnight, every 3 hours, or every 6 if the axis is narrow
+func timelineAxis(width int, dayStart time.Duration) string {
+	step := 3
+	if width < 48 {
+		step = 6
+	}
+	axis := []rune(strings.Repeat(" ", width))
+	for h := 0; h < 24; h += step {
+		label := fmt.Sprint((int(dayStart/time.Hour) + h) % 24)
+		copy(axis[h*width/24:], []rune(label))
+	}
+	return strings.TrimRight(string(axis), " ")
+}
+
+// timelineBar draws cells, one column per cell, in the ANSI color code
+// if not empty
+func timelineBar(cells []int, code string) string {
+	var b strings.Builder
+	colored := false
+	for _, n := range cells {
+		if n > 0 && code != "" && !colored {
+			b.WriteString("\033[" + code + "m")
+			colored = true
+		} else if n == 0 && colored {
+			b.WriteString("\033[0m")
+			colored = false
+		}
+		switch {
+		case n == 0:
+			b.WriteRune('·')
+		case n == 1:
+			b.WriteRune('█')
+		default:
+			b.WriteRune('▓')
+		}
+	}
+	if colored {
+		b.WriteString("\033[0m")
+	}
+	return b.String()
+}
+
+// padLabel returns label cut or padded with spaces to width runes, ending
+// in … when cut
+func padLabel(label string, width int) string {
+	runes := []rune(label)
+	if len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	return label + strings.Repeat(" ", width-len(runes))
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// clockLayouts are the accepted layouts of a time of day
+var clockLayouts = []string{"15:04", "15:04:05", "3:04pm", "3pm"}
+
+// parseTime parses a point in time given on the command line, relative to
+// now, in any of these forms:
+//
+//	2026-03-01T09:30:00+01:00   RFC3339
+//	2026-03-01 09:30            date and time of day
+//	2026-03-01                  midnight of a date
+//	9:30, 14:00:05, 9:30am, 3pm today at a time of day
+//	yesterday 14:00             yesterday (or today) at a time of day
+//	2h ago, 1h30m ago, 30m      a duration before now
+//
+// Times without an offset are in the zone of now.
+func parseTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, strings.TrimSpace(value)); err == nil {
+		return t, nil
+	}
+	s := strings.ToLower(strings.TrimSpace(value))
+
+	if d, ok := strings.CutSuffix(s, " ago"); ok {
+		s = strings.TrimSpace(d)
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("invalid time %q: negative duration", value)
+		}
+		return now.Add(-d), nil
+	}
+
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	clock := s
+	first, rest, _ := strings.Cut(s, " ")
+	if first == "today" || first == "yesterday" {
+		if first == "yesterday" {
+			day = day.AddDate(0, 0, -1)
+		}
+		clock = strings.TrimSpace(rest)
+		if clock == "" {
+			return time.Time{}, fmt.Errorf("invalid time %q: missing time of day, e.g. %q", value, first+" 9:30")
+		}
+	} else if d, err := time.ParseInLocation("2006-01-02", first, now.Location()); err == nil {
+		if rest == "" {
+			return d, nil
+		}
+		day, clock = d, strings.TrimSpace(rest)
+	}
+
+	// Allow a space before am or pm
+	clock = strings.ReplaceAll(clock, " ", "")
+	for _, layout := range clockLayouts {
+		c, err := time.Parse(layout, clock)
+		if err == nil {
+			return time.Date(day.Year(), day.Month(), day.Day(), c.Hour(), c.Minute(), c.Second(), 0, now.Location()), nil
+		}
+	}
+	return time.Time{}, invalidTimeError(value)
+}
+
+// invalidTimeError describes a time parseTime does not understand
+func invalidTimeError(value string) error {
+	return fmt.Errorf("invalid time %q (expected e.g. 9:30, yesterday 14:00, 2h ago or RFC3339)", value)
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	zone := time.FixedZone("UTC-3", -3*60*60)
+	now := time.Date(2026, 3, 10, 15, 20, 0, 0, zone)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		// Absolute
+		{"2026-03-01T09:30:00+01:00", time.Date(2026, 3, 1, 9, 30, 0, 0, time.FixedZone("", 60*60))},
+		{"2026-03-01T09:30:00Z", time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)},
+		{"2026-03-01 09:30", time.Date(2026, 3, 1, 9, 30, 0, 0, zone)},
+		{"2026-03-01 3pm", time.Date(2026, 3, 1, 15, 0, 0, 0, zone)},
+		{"2026-03-01", time.Date(2026, 3, 1, 0, 0, 0, 0, zone)},
+		// Time of day
+		{"9:30", time.Date(2026, 3, 10, 9, 30, 0, 0, zone)},
+		{"14:00:05", time.Date(2026, 3, 10, 14, 0, 5, 0, zone)},
+		{"9:30am", time.Date(2026, 3, 10, 9, 30, 0, 0, zone)},
+		{"9:30 PM", time.Date(2026, 3, 10, 21, 30, 0, 0, zone)},
+		{"3pm", time.Date(2026, 3, 10, 15, 0, 0, 0, zone)},
+		{" 9:30 ", time.Date(2026, 3, 10, 9, 30, 0, 0, zone)},
+		{"today 8:15", time.Date(2026, 3, 10, 8, 15, 0, 0, zone)},
+		{"yesterday 14:00", time.Date(2026, 3, 9, 14, 0, 0, 0, zone)},
+		{"Yesterday 11pm", time.Date(2026, 3, 9, 23, 0, 0, 0, zone)},
+		// Relative
+		{"2h ago", now.Add(-2 * time.Hour)},
+		{"1h30m ago", now.Add(-90 * time.Minute)},
+		{"30m", now.Add(-30 * time.Minute)},
+		{"0s", now},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			got, err := parseTime(test.value, now)
+			if err != nil {
+				t.Fatalf("parseTime(%q) failed: %v", test.value, err)
+			}
+			if !got.Equal(test.want) {
+				t.Errorf("parseTime(%q) = %v, want %v", test.value, got, test.want)
+			}
+			_, gotOffset := got.Zone()
+			_, wantOffset := test.want.Zone()
+			if gotOffset != wantOffset {
+				t.Errorf("parseTime(%q) has offset %d, want %d", test.value, gotOffset, wantOffset)
+			}
+		})
+	}
+}
+
+func TestParseTimeInvalid(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 20, 0, 0, time.UTC)
+	for _, value := range []string{
+		"",
+		"soon",
+		"-2h ago",
+		"-30m",
+		"yesterday",
+		"today ",
+		"tomorrow 9:30",
+		"25:00",
+		"9:61",
+		"13pm",
+		"2026-02-30",
+		"2026-03-01 noon",
+		"2026-03-01T09:30:00",
+	} {
+		t.Run(value, func(t *testing.T) {
+			if got, err := parseTime(value, now); err == nil {
+				t.Errorf("parseTime(%q) = %v, want an error", value, got)
+			}
+		})
+	}
+}
+
+func TestParseTimeAcrossDaylightSaving(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	// The day after the spring forward change of 2026
+	now := time.Date(2026, 3, 9, 10, 0, 0, 0, ny)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		// Wall clock times of the previous day keep their offset then
+		{"yesterday 1:30", time.Date(2026, 3, 8, 6, 30, 0, 0, time.UTC)},
+		{"yesterday 14:00", time.Date(2026, 3, 8, 18, 0, 0, 0, time.UTC)},
+		// Durations are elapsed time, not wall clock time
+		{"32h ago", time.Date(2026, 3, 8, 6, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			got, err := parseTime(test.value, now)
+			if err != nil {
+				t.Fatalf("parseTime(%q) failed: %v", test.value, err)
+			}
+			if !got.Equal(test.want) {
+				t.Errorf("parseTime(%q) = %v, want %v", test.value, got, test.want.In(ny))
+			}
+			if got.Location() != ny {
+				t.Errorf("parseTime(%q) is in %v, want %v", test.value, got.Location(), ny)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+)
+
+// expandTitles returns titles with those that are aliases in the config
+// replaced by the titles of their task path, and those starting with @ by
+// the titles of the template they name
+func expandTitles(titles []string) ([]string, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	var expanded []string
+	for _, title := range titles {
+		if name, ok := strings.CutPrefix(title, "@"); ok {
+			template, ok := config.Templates[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown template %q (add it with 'talogo template add')", name)
+			}
+			expanded = append(expanded, template...)
+			continue
+		}
+		if path, ok := config.Aliases[title]; ok {
+			expanded = append(expanded, strings.Split(strings.Trim(path, "/"), "/")...)
+			continue
+		}
+		expanded = append(expanded, title)
+	}
+	return expanded, nil
+}
+
+// normalizeTitles applies the [titles] settings of the config to the
+// titles of records about to be written
+func normalizeTitles(records []Record) error {
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	for i := range records {
+		if records[i].Titles, err = normalizedTitles(config, records[i].Titles); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizedTitles returns titles as written to the log with the [titles]
+// settings of config. Titles left empty are dropped.
+func normalizedTitles(config *Config, titles []string) ([]string, error) {
+	var convert func(string) string
+	switch config.Titles.Case {
+	case "":
+	case "lower":
+		convert = strings.ToLower
+	case "upper":
+		convert = strings.ToUpper
+	default:
+		return nil, fmt.Errorf("invalid case %q in config (expected lower or upper)", config.Titles.Case)
+	}
+
+	// Records split by day share their titles, build a new slice
+	var normalized []string
+	for _, title := range titles {
+		if !config.Titles.KeepControlCharacters {
+			title = talogo.SanitizeTitle(title)
+		}
+		if convert != nil {
+			title = convert(title)
+		}
+		if title != "" {
+			normalized = append(normalized, title)
+		}
+	}
+	return normalized, nil
+}
+
+// validateTitles checks titles against the rules of the [titles] settings
+// of config
+func validateTitles(config *Config, titles []string) error {
+	rules := config.Titles
+	if rules.MaxDepth > 0 && len(titles) > rules.MaxDepth {
+		return fmt.Errorf("%s has %d titles, max_depth in config allows %d", strings.Join(titles, "/"), len(titles), rules.MaxDepth)
+	}
+	for _, title := range titles {
+		if length := utf8.RuneCountInString(title); rules.MaxLength > 0 && length > rules.MaxLength {
+			return fmt.Errorf("title %q has %d characters, max_length in config allows %d", title, length, rules.MaxLength)
+		}
+		if i := strings.IndexAny(title, rules.ForbiddenCharacters); i >= 0 {
+			r, _ := utf8.DecodeRuneInString(title[i:])
+			return fmt.Errorf("title %q contains %q, forbidden by forbidden_characters in config", title, r)
+		}
+	}
+	return nil
+}
+
+// checkTitles returns titles normalized with the [titles] settings of the
+// config, or an error if they break its rules
+func checkTitles(titles []string) ([]string, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	titles, err = normalizedTitles(config, titles)
+	if err != nil {
+		return nil, err
+	}
+	return titles, validateTitles(config, titles)
+}
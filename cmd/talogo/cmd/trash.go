@@ -0,0 +1,348 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	trashCmdLogFile   string
+	trashCmdOlderThan time.Duration
+)
+
+// runningCommand is the subcommand being run, e.g. "delete" or "project
+// add", recorded with the sessions it moves to the trash
+var runningCommand string
+
+// Reasons sessions are moved to the trash
+const (
+	trashRemoved = "removed" // No longer in the log
+	trashEdited  = "edited"  // Replaced by another version
+)
+
+// trashEntry is a session moved to the trash, with the tombstone data
+// telling when and why
+type trashEntry struct {
+	ID        string            `json:"id"` // Identifies the entry, not the session
+	TrashedAt time.Time         `json:"trashed_at"`
+	Command   string            `json:"command"`
+	Reason    string            `json:"reason"`
+	Record    talogo.JSONRecord `json:"record"`
+}
+
+// trashCmd defines the trash subcommand
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "List the sessions removed from the log or edited away, to restore them",
+	Long: `List the sessions commands rewriting the log removed from it, such as
+delete, coalesce or doctor --prune-short, and the previous versions of
+the sessions they edited, such as with rename or extend. They are kept in
+a .trash.jsonl file next to the log until restored with 'talogo trash
+restore' or dropped with 'talogo trash empty'.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		entries, err := readTrash(trashCmdLogFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error reading the trash: %v\n"), err)
+			os.Exit(1)
+		}
+		if len(entries) == 0 {
+			fmt.Println(tr("The trash is empty"))
+			return
+		}
+		for _, entry := range entries {
+			record, err := entry.Record.Record()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, tr("Skipping entry %s: %v\n"), entry.ID, err)
+				continue
+			}
+			fmt.Printf(tr("%s  %s %s by %s\n    %s\n"), entry.ID, entry.TrashedAt.Local().Format("2006-01-02 15:04"),
+				entry.Reason, entry.Command, formatRecord(record))
+		}
+	},
+}
+
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore ID...",
+	Short: "Put sessions of the trash back into the log (unique id prefixes are accepted)",
+	Long: `Put sessions of the trash back into the log, by the id of their trash
+entry. A restored session replaces the version of the log with the same
+id, which goes to the trash in turn, and is appended to the log
+otherwise.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		count, err := restoreFromTrash(trashCmdLogFile, args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error restoring sessions: %v\n"), err)
+			os.Exit(1)
+		}
+		printInfo("Restored %d sessions\n", count)
+	},
+}
+
+var trashEmptyCmd = &cobra.Command{
+	Use:   "empty",
+	Short: "Drop the sessions of the trash for good",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		count, err := emptyTrash(trashCmdLogFile, trashCmdOlderThan, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error emptying the trash: %v\n"), err)
+			os.Exit(1)
+		}
+		printInfo("Dropped %d sessions from the trash\n", count)
+	},
+}
+
+func init() {
+	trashCmd.PersistentFlags().StringVarP(&trashCmdLogFile, "file", "f", defaultLogFile(), "Log file whose trash to use")
+	trashEmptyCmd.Flags().DurationVar(&trashCmdOlderThan, "older-than", 0, "Only drop the sessions trashed longer ago than this, e.g. 720h")
+	trashCmd.AddCommand(trashRestoreCmd, trashEmptyCmd)
+	rootCmd.AddCommand(trashCmd)
+}
+
+// trashFile returns the path of the trash of logFile
+func trashFile(logFile string) string {
+	return logPath(logFile) + ".trash.jsonl"
+}
+
+// readTrash returns the entries of the trash of logFile, oldest first. A
+// missing trash is empty.
+func readTrash(logFile string) ([]trashEntry, error) {
+	file, err := os.Open(trashFile(logFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []trashEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry trashEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("invalid entry on line %d of %s: %v", line, trashFile(logFile), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// writeTrash replaces the trash of logFile with entries, removing it when
+// there are none
+func writeTrash(logFile string, entries []trashEntry) error {
+	path := trashFile(logFile)
+	if len(entries) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return replaceFile(path, data.Bytes())
+}
+
+// replaceFile atomically replaces the content of the file at path with
+// data, through a temporary file renamed over it. The file keeps its
+// permissions, and is created readable by everyone if missing.
+func replaceFile(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+	defer tmp.Close()
+
+	if err := tmp.Chmod(mode); err != nil {
+		return fmt.Errorf("failed to set permissions: %v", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %v", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// trashRecords appends records to the trash of logFile for reason
+func trashRecords(logFile string, records []Record, reason string) error {
+	if len(records) == 0 {
+		return nil
+	}
+	file, err := os.OpenFile(trashFile(logFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	now := time.Now()
+	for _, record := range records {
+		entry := trashEntry{
+			ID:        talogo.NewID(),
+			TrashedAt: now,
+			Command:   runningCommand,
+			Reason:    reason,
+			// Exact times, so restored sessions are the ones removed
+			Record: talogo.NewJSONRecord(record, talogo.NanosecondPrecision),
+		}
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return file.Close()
+}
+
+// trashReplaced moves the records of previous, the content of logFile,
+// that records, its new content, lost or hold another version of to the
+// trash. Records are matched by id, or by times and titles when previous
+// has none, as when migrating the log to ids.
+func trashReplaced(logFile string, previous, records []Record) error {
+	byID := make(map[string]Record, len(records))
+	byTimes := make(map[string]Record, len(records))
+	for _, record := range records {
+		if record.ID != "" {
+			byID[record.ID] = record
+		}
+		byTimes[recordKey(Record{Start: record.Start, End: record.End, Titles: record.Titles})] = record
+	}
+
+	var removed, edited []Record
+	for _, record := range previous {
+		current, ok := byID[record.ID]
+		if record.ID == "" {
+			current, ok = byTimes[recordKey(record)]
+		}
+		switch {
+		case !ok:
+			removed = append(removed, record)
+		case !sameContent(record, current) || record.Duration() != current.Duration():
+			edited = append(edited, record)
+		}
+	}
+	if err := trashRecords(logFile, removed, trashRemoved); err != nil {
+		return err
+	}
+	return trashRecords(logFile, edited, trashEdited)
+}
+
+// restoreFromTrash puts the sessions of the trash entries with the given
+// ids back into the log file and returns how many were restored
+func restoreFromTrash(logFile string, ids []string) (int, error) {
+	entries, err := readTrash(logFile)
+	if err != nil {
+		return 0, err
+	}
+	restored := make(map[int]bool)
+	for _, id := range ids {
+		i, err := findTrashEntry(entries, id)
+		if err != nil {
+			return 0, err
+		}
+		restored[i] = true
+	}
+
+	records, err := readRecordsStrict(logFile)
+	if err != nil {
+		return 0, err
+	}
+	var kept []trashEntry
+	for i, entry := range entries {
+		if !restored[i] {
+			kept = append(kept, entry)
+			continue
+		}
+		record, err := entry.Record.Record()
+		if err != nil {
+			return 0, fmt.Errorf("invalid session in trash entry %s: %v", entry.ID, err)
+		}
+		replaced := false
+		for j := range records {
+			if recordKey(records[j]) == recordKey(record) {
+				records[j] = record
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			records = append(records, record)
+		}
+	}
+
+	if err := rewriteRecords(logFile, records); err != nil {
+		return 0, err
+	}
+	// Rewriting the log trashed the versions the restored sessions
+	// replaced, after the entries read before
+	after, err := readTrash(logFile)
+	if err != nil {
+		return 0, err
+	}
+	kept = append(kept, after[len(entries):]...)
+	return len(restored), writeTrash(logFile, kept)
+}
+
+// findTrashEntry returns the index of the trash entry with the given id,
+// or of the only one whose id starts with it
+func findTrashEntry(entries []trashEntry, id string) (int, error) {
+	found := -1
+	for i, entry := range entries {
+		if entry.ID == id {
+			return i, nil
+		}
+		if strings.HasPrefix(entry.ID, id) {
+			if found >= 0 {
+				return -1, fmt.Errorf("id prefix %q matches several trash entries", id)
+			}
+			found = i
+		}
+	}
+	if found < 0 {
+		return -1, fmt.Errorf("no trash entry with id %q", id)
+	}
+	return found, nil
+}
+
+// emptyTrash drops the entries of the trash of logFile trashed more than
+// olderThan before now, all of them if olderThan is 0, and returns how
+// many were dropped
+func emptyTrash(logFile string, olderThan time.Duration, now time.Time) (int, error) {
+	entries, err := readTrash(logFile)
+	if err != nil {
+		return 0, err
+	}
+	var kept []trashEntry
+	for _, entry := range entries {
+		if olderThan > 0 && now.Sub(entry.TrashedAt) <= olderThan {
+			kept = append(kept, entry)
+		}
+	}
+	return len(entries) - len(kept), writeTrash(logFile, kept)
+}
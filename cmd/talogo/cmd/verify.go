@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifyCmdLogFile string
+	verifyCmdReset   bool
+)
+
+// verifyCmd defines the verify subcommand
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check the log against its checksums, to detect edits made outside talogo",
+	Long: `Check the log against its checksums. With checksums = true in the config,
+talogo keeps a hash chain of the records of the log in a .checksums file
+next to it, updated by every command writing the log. verify computes it
+again from the log and reports the first record that does not match, as
+left by an edit made outside talogo or a corrupted disk. Commands
+rewriting the log refuse to while it does not match.
+
+--reset accepts the current content of the log, writing its checksums
+anew, e.g. after enabling checksums or reviewing a manual edit.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if verifyCmdReset {
+			count, err := resetChecksums(verifyCmdLogFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, tr("Error writing checksums: %v\n"), err)
+				os.Exit(exitError)
+			}
+			printInfo("Wrote the checksums of %d records\n", count)
+			return
+		}
+
+		problem, err := verifyChecksums(verifyCmdLogFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Error verifying the log: %v\n"), err)
+			os.Exit(exitError)
+		}
+		if problem != "" {
+			fmt.Printf(tr("The log does not match its checksums: %s\n"), problem)
+			os.Exit(exitCheckFailed)
+		}
+		printInfo("The log matches its checksums\n")
+	},
+}
+
+func init() {
+	verifyCmd.Flags().StringVarP(&verifyCmdLogFile, "file", "f", defaultLogFile(), "Log file to verify")
+	verifyCmd.Flags().BoolVar(&verifyCmdReset, "reset", false, "Write the checksums of the current content of the log instead of checking it")
+	rootCmd.AddCommand(verifyCmd)
+}
+
+// checksumFile returns the path of the checksums of logFile
+func checksumFile(logFile string) string {
+	return logPath(logFile) + ".checksums"
+}
+
+// checksumsEnabled reports whether the config asks to keep checksums
+func checksumsEnabled() bool {
+	config, err := loadConfig()
+	return err == nil && config.Checksums
+}
+
+// recordChecksum returns the checksum of record following the one of the
+// record before it, previous. It covers what every backend and precision
+// stores of the record, the same before and after writing it.
+func recordChecksum(previous string, record Record) string {
+	start := record.Start.UTC().Truncate(time.Second)
+	end := record.End.UTC().Truncate(time.Second)
+	seconds := int64(record.Duration().Seconds())
+	if seconds == 0 {
+		// Logs without a duration for sub-second sessions read it from
+		// the times
+		seconds = int64(end.Sub(start).Seconds())
+	}
+	fields, _ := json.Marshal([]interface{}{
+		previous,
+		record.ID,
+		start.Format(time.RFC3339),
+		end.Format(time.RFC3339),
+		seconds,
+		strings.Join(sanitizedTitles(record.Titles), "\x00"),
+		record.Source,
+		strings.Join(record.Tags, talogo.TagSeparator),
+		record.Notes,
+		record.Host,
+		record.User,
+	})
+	sum := sha256.Sum256(fields)
+	return hex.EncodeToString(sum[:])
+}
+
+// chainChecksums returns the checksums of records, following previous
+func chainChecksums(previous string, records []Record) []string {
+	sums := make([]string, len(records))
+	for i, record := range records {
+		previous = recordChecksum(previous, record)
+		sums[i] = previous
+	}
+	return sums
+}
+
+// readChecksums returns the checksums of logFile, one per record, and
+// whether it has any
+func readChecksums(logFile string) ([]string, bool, error) {
+	data, err := os.ReadFile(checksumFile(logFile))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return strings.Fields(string(data)), true, nil
+}
+
+// writeChecksums replaces the checksums of logFile with those of records
+func writeChecksums(logFile string, records []Record) error {
+	sums := chainChecksums("", records)
+	data := strings.Join(sums, "\n")
+	if len(sums) > 0 {
+		data += "\n"
+	}
+	return replaceFile(checksumFile(logFile), []byte(data))
+}
+
+// appendChecksums adds the checksums of records, just appended to
+// logFile, to those of the log. A log without checksums gets them for
+// all of its records.
+func appendChecksums(logFile string, records []Record) error {
+	sums, exists, err := readChecksums(logFile)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		_, err := resetChecksums(logFile)
+		return err
+	}
+
+	previous := ""
+	if len(sums) > 0 {
+		previous = sums[len(sums)-1]
+	}
+	file, err := os.OpenFile(checksumFile(logFile), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	for _, sum := range chainChecksums(previous, records) {
+		if _, err := fmt.Fprintln(file, sum); err != nil {
+			return err
+		}
+	}
+	return file.Close()
+}
+
+// resetChecksums writes the checksums of the current content of logFile
+// and returns how many records they cover
+func resetChecksums(logFile string) (int, error) {
+	records, err := readRecordsStrict(logFile)
+	if err != nil {
+		return 0, err
+	}
+	return len(records), writeChecksums(logFile, records)
+}
+
+// verifyChecksums compares the records of logFile with its checksums and
+// describes the first difference, empty if there is none
+func verifyChecksums(logFile string) (string, error) {
+	sums, exists, err := readChecksums(logFile)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", fmt.Errorf("the log has no checksums, set checksums = true in the config and run 'talogo verify --reset'")
+	}
+
+	problem := ""
+	previous := ""
+	count := 0
+	onBad := func(line int, reason string) error {
+		if problem == "" {
+			problem = fmt.Sprintf("line %d is not a valid record (%s)", line, reason)
+		}
+		return nil
+	}
+	err = scanLog(logFile, onBad, func(record Record) error {
+		count++
+		previous = recordChecksum(previous, record)
+		if problem == "" && (count > len(sums) || sums[count-1] != previous) {
+			problem = fmt.Sprintf("record %d, on line %d, was changed, added or removed: %s", count, record.Line, formatRecord(record))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if problem == "" && count < len(sums) {
+		problem = fmt.Sprintf("the log has %d records but the checksums cover %d, some were removed from its end", count, len(sums))
+	}
+	return problem, nil
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Session events sent to webhooks
+const (
+	EventStart  = "start"
+	EventStop   = "stop"
+	EventCancel = "cancel"
+	EventPause  = "pause"
+	// EventExpire is only sent to hooks, when the countdown of a session
+	// is over
+	EventExpire = "expire"
+)
+
+// webhookTimeout bounds each webhook request, so an unreachable endpoint
+// doesn't hold up the command
+const webhookTimeout = 5 * time.Second
+
+// webhookPayload is the JSON body posted to webhooks, and the data of
+// webhook templates. Besides the titles and tags lists it has flat fields
+// (task, tags_text, duration_minutes) that automation platforms such as
+// Zapier or IFTTT can map without transformation steps. Times are ISO 8601.
+type webhookPayload struct {
+	Event           string   `json:"event"`              // start, stop or cancel
+	Task            string   `json:"task"`               // Titles joined by "/", e.g. work/project-x
+	Titles          []string `json:"titles"`             // Task path as a list
+	Tags            []string `json:"tags,omitempty"`     // Tags as a list
+	TagsText        string   `json:"tags_text"`          // Tags joined by ", "
+	Notes           string   `json:"notes,omitempty"`    // Session notes
+	StartTime       string   `json:"start_time"`         // When the session started
+	EndTime         string   `json:"end_time,omitempty"` // When it stopped, only on stop
+	DurationSeconds float64  `json:"duration_seconds,omitempty"`
+	DurationMinutes float64  `json:"duration_minutes,omitempty"` // Rounded to two decimals
+	Source          string   `json:"source,omitempty"`
+	Host            string   `json:"host,omitempty"`
+	User            string   `json:"user,omitempty"`
+}
+
+// webhookTemplateFuncs are the functions available to webhook templates
+var webhookTemplateFuncs = template.FuncMap{
+	// json encodes a value as JSON, e.g. {{json .Task}} for a quoted string
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join": strings.Join,
+}
+
+// webhookCmd sends the webhooks of event from the log TUI without
+// blocking it
+func webhookCmd(event string, record Record) tea.Cmd {
+	return func() tea.Msg {
+		sendWebhooks(event, record)
+		return nil
+	}
+}
+
+// sendWebhooks posts event for the session in record to the configured
+// webhooks that subscribe to it. The end time and duration are only sent
+// on stop. Failures are reported as warnings and never fail the command.
+func sendWebhooks(event string, record Record) {
+	config, err := loadConfig()
+	if err != nil || len(config.Webhooks) == 0 {
+		return
+	}
+
+	payload := webhookPayload{
+		Event:     event,
+		Task:      strings.Join(record.Titles, "/"),
+		Titles:    record.Titles,
+		Tags:      record.Tags,
+		TagsText:  strings.Join(record.Tags, ", "),
+		Notes:     record.Notes,
+		StartTime: formatTimestamp(record.Start),
+		Source:    record.Source,
+		Host:      record.Host,
+		User:      record.User,
+	}
+	if event == EventStop {
+		payload.EndTime = formatTimestamp(record.End)
+		payload.DurationSeconds = durationSeconds(record.Duration())
+		payload.DurationMinutes = math.Round(record.Duration().Minutes()*100) / 100
+	}
+
+	client := &http.Client{Timeout: webhookTimeout}
+	var wg sync.WaitGroup
+	for _, hook := range config.Webhooks {
+		if len(hook.Events) > 0 && !slices.Contains(hook.Events, event) {
+			continue
+		}
+		body, err := webhookBody(hook, payload)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("Warning: webhook %s failed: %v\n"), hook.URL, err)
+			continue
+		}
+		contentType := hook.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			if err := postWebhook(client, url, contentType, body); err != nil {
+				fmt.Fprintf(os.Stderr, tr("Warning: webhook %s failed: %v\n"), url, err)
+			}
+		}(hook.URL)
+	}
+	wg.Wait()
+}
+
+// webhookBody returns the body posted to hook: its template executed with
+// payload, or payload as JSON if it has no template
+func webhookBody(hook WebhookConfig, payload webhookPayload) ([]byte, error) {
+	if hook.Template == "" {
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode payload: %v", err)
+		}
+		return body, nil
+	}
+
+	tmpl, err := template.New("webhook").Funcs(webhookTemplateFuncs).Parse(hook.Template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %v", err)
+	}
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, payload); err != nil {
+		return nil, fmt.Errorf("failed to execute template: %v", err)
+	}
+	return body.Bytes(), nil
+}
+
+// postWebhook posts body to url
+func postWebhook(client *http.Client, url, contentType string, body []byte) error {
+	resp, err := client.Post(url, contentType, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
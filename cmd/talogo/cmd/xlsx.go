@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/artilugio0/talogo/pkg/talogo"
+)
+
+// Styles of the cells of exported workbooks, indexes into the cellXfs of
+// xlsxStyles
+const (
+	xlsxDefault  = iota
+	xlsxHeader   // Bold
+	xlsxDateTime // 2026-05-06 09:30
+	xlsxDuration // 7:30, hours beyond 24 included
+	xlsxDate     // 2026-05-06
+	xlsxTotal    // Bold duration
+)
+
+// xlsxStyles defines the formats of the styles above. Durations are
+// stored as fractions of a day, as spreadsheets do, so they can be
+// summed with formulas.
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="3"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm"/><numFmt numFmtId="165" formatCode="[h]:mm"/><numFmt numFmtId="166" formatCode="yyyy-mm-dd"/></numFmts>
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="6">
+<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>
+<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="166" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="165" fontId="1" fillId="0" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1"/>
+</cellXfs>
+</styleSheet>
+`
+
+// xlsxCell is a cell of an exported sheet, a text or a number
+type xlsxCell struct {
+	Text     string
+	Number   float64
+	IsNumber bool
+	Style    int
+}
+
+// xlsxText returns a cell holding s
+func xlsxText(s string, style int) xlsxCell {
+	return xlsxCell{Text: s, Style: style}
+}
+
+// xlsxNumber returns a cell holding n, shown as style formats it
+func xlsxNumber(n float64, style int) xlsxCell {
+	return xlsxCell{Number: n, IsNumber: true, Style: style}
+}
+
+// xlsxSheet is a sheet of an exported workbook, its first row a header
+// kept in view while scrolling
+type xlsxSheet struct {
+	Name   string
+	Widths []float64 // Of the columns, in characters
+	Rows   [][]xlsxCell
+}
+
+// writeRecordsXLSX writes records as an Excel workbook with two sheets:
+// the sessions, one per row, and the time of each task per day, with
+// totals for both
+func writeRecordsXLSX(w io.Writer, records []Record) error {
+	dayStart, err := configuredDayStart()
+	if err != nil {
+		return err
+	}
+	sorted := append([]Record{}, records...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	sheets := []xlsxSheet{xlsxSessionsSheet(sorted, dayStart), xlsxDaysSheet(sorted, dayStart)}
+	if err := writeXLSX(w, sheets); err != nil {
+		return fmt.Errorf("failed to write XLSX: %v", err)
+	}
+	return nil
+}
+
+// xlsxSessionsSheet returns the sheet listing records
+func xlsxSessionsSheet(records []Record, dayStart time.Duration) xlsxSheet {
+	sheet := xlsxSheet{
+		Name:   "Sessions",
+		Widths: []float64{12, 17, 17, 10, 40, 20, 50},
+		Rows: [][]xlsxCell{{
+			xlsxText("Date", xlsxHeader), xlsxText("Start", xlsxHeader), xlsxText("End", xlsxHeader),
+			xlsxText("Duration", xlsxHeader), xlsxText("Task", xlsxHeader), xlsxText("Tags", xlsxHeader),
+			xlsxText("Notes", xlsxHeader),
+		}},
+	}
+	var total time.Duration
+	for _, record := range records {
+		date, _ := time.Parse("2006-01-02", talogo.DayOf(record.Start, dayStart))
+		total += record.Duration()
+		sheet.Rows = append(sheet.Rows, []xlsxCell{
+			xlsxNumber(xlsxSerial(date), xlsxDate),
+			xlsxNumber(xlsxSerial(record.Start), xlsxDateTime),
+			xlsxNumber(xlsxSerial(record.End), xlsxDateTime),
+			xlsxNumber(xlsxDays(record.Duration()), xlsxDuration),
+			xlsxText(strings.Join(sanitizedTitles(record.Titles), "/"), xlsxDefault),
+			xlsxText(strings.Join(record.Tags, ", "), xlsxDefault),
+			xlsxText(record.Notes, xlsxDefault),
+		})
+	}
+	sheet.Rows = append(sheet.Rows, []xlsxCell{
+		xlsxText("Total", xlsxHeader), {}, {}, xlsxNumber(xlsxDays(total), xlsxTotal),
+	})
+	return sheet
+}
+
+// xlsxDaysSheet returns the sheet with a row per day and a column per
+// task path, holding the time of the task that day
+func xlsxDaysSheet(records []Record, dayStart time.Duration) xlsxSheet {
+	times := make(map[string]map[string]time.Duration) // Date -> task -> time
+	taskTotals := make(map[string]time.Duration)
+	var dates, tasks []string
+	for _, record := range records {
+		date := talogo.DayOf(record.Start, dayStart)
+		task := strings.Join(sanitizedTitles(record.Titles), "/")
+		if times[date] == nil {
+			times[date] = make(map[string]time.Duration)
+			dates = append(dates, date)
+		}
+		if _, seen := taskTotals[task]; !seen {
+			tasks = append(tasks, task)
+		}
+		times[date][task] += record.Duration()
+		taskTotals[task] += record.Duration()
+	}
+	sort.Strings(dates)
+	sort.Strings(tasks)
+
+	header := []xlsxCell{xlsxText("Date", xlsxHeader)}
+	widths := []float64{12}
+	for _, task := range tasks {
+		header = append(header, xlsxText(task, xlsxHeader))
+		widths = append(widths, float64(max(10, min(40, len(task)+2))))
+	}
+	header = append(header, xlsxText("Total", xlsxHeader))
+	widths = append(widths, 10)
+	sheet := xlsxSheet{Name: "By day", Widths: widths, Rows: [][]xlsxCell{header}}
+
+	var total time.Duration
+	for _, date := range dates {
+		day, _ := time.Parse("2006-01-02", date)
+		row := []xlsxCell{xlsxNumber(xlsxSerial(day), xlsxDate)}
+		var dayTotal time.Duration
+		for _, task := range tasks {
+			d, ok := times[date][task]
+			if !ok {
+				row = append(row, xlsxCell{})
+				continue
+			}
+			dayTotal += d
+			row = append(row, xlsxNumber(xlsxDays(d), xlsxDuration))
+		}
+		total += dayTotal
+		sheet.Rows = append(sheet.Rows, append(row, xlsxNumber(xlsxDays(dayTotal), xlsxTotal)))
+	}
+
+	totals := []xlsxCell{xlsxText("Total", xlsxHeader)}
+	for _, task := range tasks {
+		totals = append(totals, xlsxNumber(xlsxDays(taskTotals[task]), xlsxTotal))
+	}
+	sheet.Rows = append(sheet.Rows, append(totals, xlsxNumber(xlsxDays(total), xlsxTotal)))
+	return sheet
+}
+
+// xlsxSerial returns the spreadsheet serial number of the wall clock time
+// of t: days since 1899-12-30, the time of day as the fraction
+func xlsxSerial(t time.Time) float64 {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	return xlsxDays(wall.Sub(time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)))
+}
+
+// xlsxDays returns d in days, the unit of spreadsheet times
+func xlsxDays(d time.Duration) float64 {
+	return d.Seconds() / (24 * 60 * 60)
+}
+
+// writeXLSX writes a workbook with sheets to w
+func writeXLSX(w io.Writer, sheets []xlsxSheet) error {
+	var contentTypes, workbook, workbookRels strings.Builder
+	contentTypes.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+`)
+	workbook.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	workbookRels.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rStyles" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+`)
+	for i, sheet := range sheets {
+		fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`+"\n", i+1)
+		fmt.Fprintf(&workbook, `<sheet name="%s" sheetId="%d" r:id="rSheet%d"/>`, xmlEscape(sheet.Name), i+1, i+1)
+		fmt.Fprintf(&workbookRels, `<Relationship Id="rSheet%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`+"\n", i+1, i+1)
+	}
+	contentTypes.WriteString("</Types>\n")
+	workbook.WriteString("</sheets></workbook>\n")
+	workbookRels.WriteString("</Relationships>\n")
+
+	files := []struct{ name, content string }{
+		{"[Content_Types].xml", contentTypes.String()},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rWorkbook" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>
+`},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", workbookRels.String()},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for i, sheet := range sheets {
+		files = append(files, struct{ name, content string }{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), sheet.xml()})
+	}
+
+	archive := zip.NewWriter(w)
+	now := time.Now()
+	for _, file := range files {
+		f, err := archive.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, file.content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// xml returns the worksheet part of the sheet
+func (s xlsxSheet) xml() string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>
+`)
+	if len(s.Widths) > 0 {
+		b.WriteString("<cols>")
+		for i, width := range s.Widths {
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%g" customWidth="1"/>`, i+1, i+1, width)
+		}
+		b.WriteString("</cols>\n")
+	}
+	b.WriteString("<sheetData>\n")
+	for i, row := range s.Rows {
+		fmt.Fprintf(&b, `<row r="%d">`, i+1)
+		for j, cell := range row {
+			ref := letterName(j+1) + strconv.Itoa(i+1)
+			switch {
+			case cell.IsNumber:
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, cell.Style, strconv.FormatFloat(cell.Number, 'f', -1, 64))
+			case cell.Text != "":
+				fmt.Fprintf(&b, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, cell.Style, xmlEscape(cell.Text))
+			}
+		}
+		b.WriteString("</row>\n")
+	}
+	b.WriteString("</sheetData>\n</worksheet>\n")
+	return b.String()
+}
+
+// xmlEscape escapes s for XML text and attribute values
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
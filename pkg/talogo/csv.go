@@ -0,0 +1,425 @@
+package talogo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVSchemaVersion is the version of the log layout written by talogo.
+// Version 1 files have no version line and only start_time, end_time and
+// title columns. Version 2 added duration_seconds and source, version 3
+// added tags, version 4 added notes, version 5 added id and version 6
+// added host and user.
+const CSVSchemaVersion = 6
+
+// CSVVersionPrefix starts the comment line holding the schema version,
+// written before the header
+const CSVVersionPrefix = "# talogo schema v"
+
+// LogColumns lists the columns written before the title columns, in order
+var LogColumns = []string{"id", "start_time", "end_time", "duration_seconds", "source", "tags", "notes", "host", "user"}
+
+// TagSeparator separates the tags stored in the tags column
+const TagSeparator = ";"
+
+// utf8BOM is the byte order mark spreadsheet programs put at the start of
+// the files they save
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// BadRecordFunc is called by the log scanners for each record that can't
+// be parsed. Returning an error stops the scan.
+type BadRecordFunc func(line int, reason string) error
+
+// CSVSchema maps the columns of a log file header to their positions.
+// Title columns (title1, title2, ...) always come last, so records with
+// more titles than the header just have extra trailing fields.
+type CSVSchema struct {
+	columns    map[string]int
+	titleStart int
+	titleCount int
+	// Comma is the field delimiter of the file
+	Comma rune
+	// Precision of the times and durations written in rows
+	Precision Precision
+}
+
+// NewCSVSchema builds the schema described by a comma-delimited log file
+// header
+func NewCSVSchema(header []string) CSVSchema {
+	schema := CSVSchema{
+		columns:    make(map[string]int),
+		titleStart: len(header),
+		Comma:      ',',
+	}
+	for i, name := range header {
+		if strings.HasPrefix(name, "title") {
+			if schema.titleStart == len(header) {
+				schema.titleStart = i
+			}
+			schema.titleCount++
+			continue
+		}
+		schema.columns[name] = i
+	}
+	return schema
+}
+
+// CanonicalSchema returns the current schema with enough title columns for
+// all records
+func CanonicalSchema(records []Record) CSVSchema {
+	maxTitles := 0
+	for _, record := range records {
+		if len(record.Titles) > maxTitles {
+			maxTitles = len(record.Titles)
+		}
+	}
+	header := append([]string{}, LogColumns...)
+	for i := 1; i <= maxTitles; i++ {
+		header = append(header, fmt.Sprintf("title%d", i))
+	}
+	return NewCSVSchema(header)
+}
+
+// Field returns the value of the named column of row, or an empty string
+// if the column does not exist
+func (s CSVSchema) Field(row []string, name string) string {
+	i, ok := s.columns[name]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return row[i]
+}
+
+// Header returns the header row of the schema
+func (s CSVSchema) Header() []string {
+	header := make([]string, s.titleStart+s.titleCount)
+	for name, i := range s.columns {
+		header[i] = name
+	}
+	for i := 0; i < s.titleCount; i++ {
+		header[s.titleStart+i] = fmt.Sprintf("title%d", i+1)
+	}
+	return header
+}
+
+// columnValue returns the value record stores in the named column
+func (s CSVSchema) columnValue(record Record, name string) string {
+	switch name {
+	case "id":
+		return record.ID
+	case "start_time":
+		return s.Precision.FormatTime(record.Start)
+	case "end_time":
+		return s.Precision.FormatTime(record.End)
+	case "duration_seconds":
+		return strconv.FormatFloat(s.Precision.Seconds(record.Duration()), 'f', -1, 64)
+	case "source":
+		return record.Source
+	case "tags":
+		return strings.Join(record.Tags, TagSeparator)
+	case "notes":
+		return record.Notes
+	case "host":
+		return record.Host
+	case "user":
+		return record.User
+	}
+	return ""
+}
+
+// MissingColumns returns the columns record has a value for that the
+// schema lacks
+func (s CSVSchema) MissingColumns(record Record) []string {
+	var missing []string
+	for _, name := range LogColumns {
+		if _, ok := s.columns[name]; !ok && s.columnValue(record, name) != "" {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// MissingColumnNames returns the current log columns the schema lacks
+func (s CSVSchema) MissingColumnNames() []string {
+	var missing []string
+	for _, name := range LogColumns {
+		if _, ok := s.columns[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// Row converts record to a row following the schema
+func (s CSVSchema) Row(record Record) []string {
+	row := make([]string, s.titleStart)
+	for name, i := range s.columns {
+		row[i] = s.columnValue(record, name)
+	}
+
+	// Add titles, padding with empty strings if fewer than the header has
+	row = append(row, record.Titles...)
+	for len(row) < s.titleStart+s.titleCount {
+		row = append(row, "")
+	}
+	return row
+}
+
+// ReadCSVLayout returns the schema version and the header of a CSV log.
+// Logs without a version line are version 1.
+func ReadCSVLayout(r io.ReadSeeker) (int, CSVSchema, error) {
+	version := 1
+	firstLine, _ := bufio.NewReader(r).ReadString('\n')
+	firstLine = strings.TrimPrefix(firstLine, string(utf8BOM))
+	if v, ok := strings.CutPrefix(strings.TrimSpace(firstLine), CSVVersionPrefix); ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			version = n
+		}
+	}
+
+	comma, err := DetectCSVDelimiter(r)
+	if err != nil {
+		return 0, CSVSchema{}, err
+	}
+	header, err := NewCSVReader(r, comma).Read()
+	if err == io.EOF {
+		return CSVSchemaVersion, CanonicalSchema(nil), nil
+	}
+	if err != nil {
+		return 0, CSVSchema{}, fmt.Errorf("failed to read CSV headers: %v", err)
+	}
+	schema := NewCSVSchema(header)
+	schema.Comma = comma
+	return version, schema, nil
+}
+
+// DetectCSVDelimiter returns the field delimiter of a CSV log: the most
+// frequent of comma, semicolon and tab in its header line. It leaves r
+// positioned at its start.
+func DetectCSVDelimiter(r io.ReadSeeker) (rune, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("failed to read CSV file: %v", err)
+	}
+
+	comma := ','
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimPrefix(scanner.Text(), string(utf8BOM))
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		best := strings.Count(line, ",")
+		for _, candidate := range []rune{';', '\t'} {
+			if n := strings.Count(line, string(candidate)); n > best {
+				comma, best = candidate, n
+			}
+		}
+		break
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("failed to read CSV file: %v", err)
+	}
+	return comma, nil
+}
+
+// ParseCSVDelimiter parses a delimiter given by the user: a single
+// character, or "tab"
+func ParseCSVDelimiter(s string) (rune, error) {
+	if s == "tab" || s == `\t` {
+		return '\t', nil
+	}
+	runes := []rune(s)
+	if len(runes) != 1 || runes[0] == '"' || runes[0] == '\r' || runes[0] == '\n' {
+		return 0, fmt.Errorf("invalid CSV delimiter %q (expected a single character or \"tab\")", s)
+	}
+	return runes[0], nil
+}
+
+// WriteCSVLog writes a whole CSV log: the version line (for versions above
+// 1), the header of schema and records
+func WriteCSVLog(w io.Writer, version int, schema CSVSchema, records []Record) error {
+	// Make room for records with more titles than the header has
+	for _, record := range records {
+		if len(record.Titles) > schema.titleCount {
+			schema.titleCount = len(record.Titles)
+		}
+	}
+
+	if version > 1 {
+		if _, err := fmt.Fprintf(w, "%s%d\n", CSVVersionPrefix, version); err != nil {
+			return fmt.Errorf("failed to write CSV header: %v", err)
+		}
+	}
+	writer := csv.NewWriter(w)
+	writer.Comma = schema.Comma
+	if err := writer.Write(schema.Header()); err != nil {
+		return fmt.Errorf("failed to write CSV header: %v", err)
+	}
+	for _, record := range records {
+		if err := writer.Write(schema.Row(record)); err != nil {
+			return fmt.Errorf("failed to write CSV record: %v", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV record: %v", err)
+	}
+	return nil
+}
+
+// NewCSVReader returns a CSV reader configured for talogo logs delimited
+// by comma. A leading byte order mark is skipped, and CRLF line endings
+// are handled by encoding/csv.
+func NewCSVReader(r io.Reader, comma rune) *csv.Reader {
+	buffered := bufio.NewReader(r)
+	if prefix, err := buffered.Peek(len(utf8BOM)); err == nil && bytes.Equal(prefix, utf8BOM) {
+		buffered.Discard(len(utf8BOM))
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.Comma = comma
+	reader.Comment = '#'           // Skip the schema version line
+	reader.LazyQuotes = true       // Allow relaxed quoting
+	reader.FieldsPerRecord = -1    // Allow variable number of fields
+	reader.TrimLeadingSpace = true // Trim leading spaces
+	return reader
+}
+
+// ParseTags splits the value of a tags column
+func ParseTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, TagSeparator) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// ScanCSV calls fn for each valid record of a CSV log, in order, without
+// loading the whole log in memory, and onBad for each invalid one.
+// Scanning stops at the first error returned by either.
+func ScanCSV(r io.ReadSeeker, onBad BadRecordFunc, fn func(Record) error) error {
+	comma, err := DetectCSVDelimiter(r)
+	if err != nil {
+		return err
+	}
+	reader := NewCSVReader(r, comma)
+	reader.ReuseRecord = true // Rows are converted to records right away
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read CSV: %v", err)
+	}
+
+	schema := NewCSVSchema(append([]string{}, header...))
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read CSV: %v", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		// Ensure record has at least start_time, end_time
+		if len(row) < 2 {
+			if err := onBad(line, fmt.Sprintf("too few fields (%d)", len(row))); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Parse start time
+		startTime, err := time.Parse(time.RFC3339, schema.Field(row, "start_time"))
+		if err != nil {
+			if err := onBad(line, fmt.Sprintf("invalid start time (%s)", schema.Field(row, "start_time"))); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Parse end time
+		endTime, err := time.Parse(time.RFC3339, schema.Field(row, "end_time"))
+		if err != nil {
+			if err := onBad(line, fmt.Sprintf("invalid end time (%s)", schema.Field(row, "end_time"))); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if endTime.Before(startTime) {
+			if err := onBad(line, "negative duration"); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Logs without a duration column fall back to end - start
+		var elapsed time.Duration
+		if seconds, err := strconv.ParseFloat(schema.Field(row, "duration_seconds"), 64); err == nil {
+			elapsed = time.Duration(seconds * float64(time.Second))
+		}
+
+		var titles []string
+		if schema.titleStart < len(row) {
+			for _, title := range row[schema.titleStart:] {
+				if title == "" {
+					break // No more titles
+				}
+				titles = append(titles, title)
+			}
+		}
+
+		record := Record{
+			ID:      schema.Field(row, "id"),
+			Line:    line,
+			Start:   startTime,
+			End:     endTime,
+			Titles:  titles,
+			Source:  schema.Field(row, "source"),
+			Tags:    ParseTags(schema.Field(row, "tags")),
+			Notes:   schema.Field(row, "notes"),
+			Host:    schema.Field(row, "host"),
+			User:    schema.Field(row, "user"),
+			Elapsed: elapsed,
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteCSV writes records as CSV delimited by comma, using the log file
+// columns but no version line
+func WriteCSV(w io.Writer, records []Record, comma rune, precision Precision) error {
+	schema := CanonicalSchema(records)
+	schema.Precision = precision
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+	if err := writer.Write(schema.Header()); err != nil {
+		return fmt.Errorf("failed to write CSV header: %v", err)
+	}
+
+	for _, record := range records {
+		if err := writer.Write(schema.Row(record)); err != nil {
+			return fmt.Errorf("failed to write CSV record: %v", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
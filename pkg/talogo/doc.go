@@ -0,0 +1,30 @@
+// Package talogo holds the core of talogo: the sessions stored in time
+// logs, reading and writing the CSV and JSON-Lines log formats, and the
+// aggregation of sessions into task trees and reports.
+//
+// It doesn't read the talogo config file, settings such as the day start
+// or the timestamp precision are given explicitly. The talogo command is
+// a frontend to this package.
+//
+// For example, to print the hours of each day of a CSV log:
+//
+//	file, err := os.Open("talogo.csv")
+//	if err != nil {
+//		return err
+//	}
+//	defer file.Close()
+//
+//	days := make(map[string]*talogo.TaskNode)
+//	err = talogo.ScanCSV(file, func(line int, reason string) error {
+//		return nil // Skip malformed records
+//	}, func(record talogo.Record) error {
+//		talogo.AddToSummary(days, record, talogo.SummaryOptions{})
+//		return nil
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	for _, date := range talogo.SortedDates(days) {
+//		fmt.Printf("%s: %.2f hs\n", date, days[date].TotalTime.Hours())
+//	}
+package talogo
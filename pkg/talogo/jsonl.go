@@ -0,0 +1,121 @@
+package talogo
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// JSONRecord is the JSON representation of a record, used by the JSONL
+// log format and the JSON exports
+type JSONRecord struct {
+	ID              string   `json:"id,omitempty"`
+	StartTime       string   `json:"start_time"`
+	EndTime         string   `json:"end_time"`
+	DurationSeconds float64  `json:"duration_seconds"`
+	Titles          []string `json:"titles"`
+	Source          string   `json:"source,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	Notes           string   `json:"notes,omitempty"`
+	Host            string   `json:"host,omitempty"`
+	User            string   `json:"user,omitempty"`
+}
+
+// NewJSONRecord converts record to its JSON representation
+func NewJSONRecord(record Record, precision Precision) JSONRecord {
+	return JSONRecord{
+		ID:              record.ID,
+		StartTime:       precision.FormatTime(record.Start),
+		EndTime:         precision.FormatTime(record.End),
+		DurationSeconds: precision.Seconds(record.Duration()),
+		Titles:          record.Titles,
+		Source:          record.Source,
+		Tags:            record.Tags,
+		Notes:           record.Notes,
+		Host:            record.Host,
+		User:            record.User,
+	}
+}
+
+// Record converts the JSON representation back to a record
+func (j JSONRecord) Record() (Record, error) {
+	startTime, err := time.Parse(time.RFC3339, j.StartTime)
+	if err != nil {
+		return Record{}, fmt.Errorf("invalid start time (%s)", j.StartTime)
+	}
+	endTime, err := time.Parse(time.RFC3339, j.EndTime)
+	if err != nil {
+		return Record{}, fmt.Errorf("invalid end time (%s)", j.EndTime)
+	}
+	if endTime.Before(startTime) {
+		return Record{}, fmt.Errorf("negative duration")
+	}
+
+	return Record{
+		ID:      j.ID,
+		Start:   startTime,
+		End:     endTime,
+		Titles:  j.Titles,
+		Source:  j.Source,
+		Tags:    j.Tags,
+		Notes:   j.Notes,
+		Host:    j.Host,
+		User:    j.User,
+		Elapsed: time.Duration(j.DurationSeconds * float64(time.Second)),
+	}, nil
+}
+
+// ScanJSONL calls fn for each valid record of a JSONL log, in order, and
+// onBad for each invalid one. Scanning stops at the first error returned
+// by either.
+func ScanJSONL(r io.Reader, onBad BadRecordFunc, fn func(Record) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var j JSONRecord
+		if err := json.Unmarshal([]byte(text), &j); err != nil {
+			if err := onBad(line, err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+		record, err := j.Record()
+		if err != nil {
+			if err := onBad(line, err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+		record.Line = line
+
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read JSONL: %v", err)
+	}
+
+	return nil
+}
+
+// WriteJSONL writes records as JSON Lines, one JSON object per line
+func WriteJSONL(w io.Writer, records []Record, precision Precision) error {
+	encoder := json.NewEncoder(w)
+	for _, record := range records {
+		if err := encoder.Encode(NewJSONRecord(record, precision)); err != nil {
+			return fmt.Errorf("failed to write JSONL record: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package talogo
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Locale holds the names and separators reports format dates and numbers
+// with. A nil Locale formats dates as YYYY-MM-DD and numbers with a
+// decimal point.
+type Locale struct {
+	Name     string
+	Weekdays [7]string  // Sunday first
+	Months   [12]string // January first
+	// DateLayout is the layout of long dates, with {weekday}, {day},
+	// {month} and {year} replaced by their values
+	DateLayout string
+	Decimal    string // Decimal separator
+}
+
+// locales are the bundled locales, by language
+var locales = map[string]*Locale{
+	"en": {
+		Name:       "en",
+		Weekdays:   [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		Months:     [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		DateLayout: "{weekday}, {month} {day}",
+		Decimal:    ".",
+	},
+	"es": {
+		Name:       "es",
+		Weekdays:   [7]string{"Domingo", "Lunes", "Martes", "Miércoles", "Jueves", "Viernes", "Sábado"},
+		Months:     [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		DateLayout: "{weekday} {day} de {month}",
+		Decimal:    ",",
+	},
+	"pt": {
+		Name:       "pt",
+		Weekdays:   [7]string{"Domingo", "Segunda-feira", "Terça-feira", "Quarta-feira", "Quinta-feira", "Sexta-feira", "Sábado"},
+		Months:     [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		DateLayout: "{weekday}, {day} de {month}",
+		Decimal:    ",",
+	},
+	"fr": {
+		Name:       "fr",
+		Weekdays:   [7]string{"Dimanche", "Lundi", "Mardi", "Mercredi", "Jeudi", "Vendredi", "Samedi"},
+		Months:     [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		DateLayout: "{weekday} {day} {month}",
+		Decimal:    ",",
+	},
+	"de": {
+		Name:       "de",
+		Weekdays:   [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		Months:     [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		DateLayout: "{weekday}, {day}. {month}",
+		Decimal:    ",",
+	},
+	"it": {
+		Name:       "it",
+		Weekdays:   [7]string{"Domenica", "Lunedì", "Martedì", "Mercoledì", "Giovedì", "Venerdì", "Sabato"},
+		Months:     [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		DateLayout: "{weekday} {day} {month}",
+		Decimal:    ",",
+	},
+}
+
+// LookupLocale returns the bundled locale of name, a language code
+// optionally followed by a region and encoding, e.g. "es", "es_AR" or
+// "pt-BR.UTF-8"
+func LookupLocale(name string) (*Locale, error) {
+	language := strings.ToLower(name)
+	if i := strings.IndexAny(language, "_-."); i >= 0 {
+		language = language[:i]
+	}
+	locale, ok := locales[language]
+	if !ok {
+		return nil, fmt.Errorf("unknown locale %q (expected one of %s)", name, strings.Join(LocaleNames(), ", "))
+	}
+	return locale, nil
+}
+
+// LocaleNames returns the names of the bundled locales, sorted
+func LocaleNames() []string {
+	var names []string
+	for name := range locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Date formats date (in YYYY-MM-DD format) as a long date, e.g.
+// "Lunes 6 de mayo"
+func (l *Locale) Date(date string) string {
+	day, err := time.Parse("2006-01-02", date)
+	if l == nil || err != nil {
+		return date
+	}
+	return strings.NewReplacer(
+		"{weekday}", l.Weekdays[day.Weekday()],
+		"{day}", strconv.Itoa(day.Day()),
+		"{month}", l.Months[day.Month()-1],
+		"{year}", strconv.Itoa(day.Year()),
+	).Replace(l.DateLayout)
+}
+
+// ShortDate formats day as the abbreviated month and the day, e.g.
+// "may 6", or "Jan 2" with a nil Locale
+func (l *Locale) ShortDate(day time.Time) string {
+	if l == nil {
+		return day.Format("Jan 2")
+	}
+	return l.shortMonth(day.Month()) + " " + strconv.Itoa(day.Day())
+}
+
+// shortMonth returns the first three letters of the name of month
+func (l *Locale) shortMonth(month time.Month) string {
+	name := []rune(l.Months[month-1])
+	return string(name[:min(3, len(name))])
+}
+
+// Hours formats d as hours with two decimals, e.g. "7,50"
+func (l *Locale) Hours(d time.Duration) string {
+	return l.Number(d.Hours(), 2)
+}
+
+// Number formats n with the given decimals and the decimal separator of
+// the locale
+func (l *Locale) Number(n float64, decimals int) string {
+	s := strconv.FormatFloat(n, 'f', decimals, 64)
+	if l == nil {
+		return s
+	}
+	return strings.Replace(s, ".", l.Decimal, 1)
+}
@@ -0,0 +1,231 @@
+package talogo
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Matrix holds the time of each task, as rows, in each week of a report,
+// as columns, the layout of many timesheet forms
+type Matrix struct {
+	Weeks      []Week            // Columns
+	Tasks      []string          // Rows, task paths joined by "/"
+	Times      [][]time.Duration // Time of each task in each week
+	TaskTotals []time.Duration   // Time of each task in the period
+	WeekTotals []time.Duration   // Time of each week
+	Total      time.Duration
+	Locale     *Locale // Formats week labels and hours
+}
+
+// Week is the part of a week inside a period
+type Week struct {
+	From   string // First day, in YYYY-MM-DD format
+	To     string // Last day, in YYYY-MM-DD format
+	Number int    // ISO week number of the Monday of the week
+}
+
+// Label returns a short name of the week with month names of locale,
+// e.g. "W41 Oct 5-11" or "W40 Sep 28-Oct 4"
+func (w Week) Label(locale *Locale) string {
+	from, _ := time.Parse("2006-01-02", w.From)
+	to, _ := time.Parse("2006-01-02", w.To)
+	label := fmt.Sprintf("W%02d ", w.Number)
+	if from.Equal(to) {
+		return label + locale.ShortDate(from)
+	}
+	if from.Month() == to.Month() {
+		return label + locale.ShortDate(from) + "-" + to.Format("2")
+	}
+	return label + locale.ShortDate(from) + "-" + locale.ShortDate(to)
+}
+
+// WeekStartOf returns the first day of the week of day, with weeks
+// beginning on weekStart
+func WeekStartOf(day time.Time, weekStart time.Weekday) time.Time {
+	return day.AddDate(0, 0, -(int(day.Weekday())-int(weekStart)+7)%7)
+}
+
+// Weeks returns the weeks of the period from and to (in YYYY-MM-DD format),
+// beginning on weekStart, the first and last cut to the period. Weeks are
+// numbered as the ISO week of their Monday, so with Sunday weeks the
+// number is that of the following Monday to Saturday.
+func Weeks(from, to string, weekStart time.Weekday) []Week {
+	first, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return nil
+	}
+	last, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return nil
+	}
+	var weeks []Week
+	for day := first; !day.After(last); {
+		start := WeekStartOf(day, weekStart)
+		end := start.AddDate(0, 0, 6)
+		if end.After(last) {
+			end = last
+		}
+		monday := start.AddDate(0, 0, (8-int(start.Weekday()))%7)
+		_, number := monday.ISOWeek()
+		weeks = append(weeks, Week{From: day.Format("2006-01-02"), To: end.Format("2006-01-02"), Number: number})
+		day = end.AddDate(0, 0, 1)
+	}
+	return weeks
+}
+
+// Matrix returns the time of the tasks of the report in each of its
+// weeks, beginning on weekStart. Tasks are cut to their first depth
+// titles, so 1 lists the root tasks only.
+func (r *Report) Matrix(depth int, weekStart time.Weekday) *Matrix {
+	m := &Matrix{Weeks: Weeks(r.From, r.To, weekStart), Locale: r.Locale}
+	m.WeekTotals = make([]time.Duration, len(m.Weeks))
+
+	rows := make(map[string]int)
+	for _, record := range r.Records {
+		titles := make([]string, 0, len(record.Titles))
+		for _, title := range record.Titles {
+			titles = append(titles, SanitizeTitle(title))
+		}
+		if depth > 0 && len(titles) > depth {
+			titles = titles[:depth]
+		}
+		task := strings.Join(titles, "/")
+
+		day := DayOf(record.Start, r.DayStart)
+		week := -1
+		for i, w := range m.Weeks {
+			if day >= w.From && day <= w.To {
+				week = i
+			}
+		}
+		if week < 0 {
+			continue
+		}
+
+		row, ok := rows[task]
+		if !ok {
+			row = len(m.Tasks)
+			rows[task] = row
+			m.Tasks = append(m.Tasks, task)
+			m.Times = append(m.Times, make([]time.Duration, len(m.Weeks)))
+			m.TaskTotals = append(m.TaskTotals, 0)
+		}
+		duration := record.Duration()
+		m.Times[row][week] += duration
+		m.TaskTotals[row] += duration
+		m.WeekTotals[week] += duration
+		m.Total += duration
+	}
+
+	// Rows in task order
+	sorted := append([]string{}, m.Tasks...)
+	sort.Strings(sorted)
+	times := make([][]time.Duration, len(sorted))
+	totals := make([]time.Duration, len(sorted))
+	for i, task := range sorted {
+		times[i] = m.Times[rows[task]]
+		totals[i] = m.TaskTotals[rows[task]]
+	}
+	m.Tasks, m.Times, m.TaskTotals = sorted, times, totals
+	return m
+}
+
+// cells returns the rows of the matrix as text, header and totals
+// included, with hours formatted to two decimals
+func (m *Matrix) cells() [][]string {
+	header := []string{"Task"}
+	for _, week := range m.Weeks {
+		header = append(header, week.Label(m.Locale))
+	}
+	header = append(header, "Total")
+
+	rows := [][]string{header}
+	for i, task := range m.Tasks {
+		row := []string{task}
+		for _, d := range m.Times[i] {
+			row = append(row, m.Locale.Hours(d))
+		}
+		rows = append(rows, append(row, m.Locale.Hours(m.TaskTotals[i])))
+	}
+	totals := []string{"Total"}
+	for _, d := range m.WeekTotals {
+		totals = append(totals, m.Locale.Hours(d))
+	}
+	return append(rows, append(totals, m.Locale.Hours(m.Total)))
+}
+
+// Markdown formats the matrix as a Markdown table under title, with the
+// columns aligned so it also reads well as plain text
+func (m *Matrix) Markdown(title string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	if len(m.Tasks) == 0 {
+		b.WriteString("No time tracked.\n")
+		return b.String()
+	}
+
+	rows := m.cells()
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len([]rune(cell)))
+		}
+	}
+	writeRow := func(row []string) {
+		b.WriteString("|")
+		for i, cell := range row {
+			pad := strings.Repeat(" ", widths[i]-len([]rune(cell)))
+			if i == 0 {
+				fmt.Fprintf(&b, " %s%s |", cell, pad)
+			} else {
+				fmt.Fprintf(&b, " %s%s |", pad, cell)
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(rows[0])
+	b.WriteString("|")
+	for i, width := range widths {
+		if i == 0 {
+			fmt.Fprintf(&b, " %s |", strings.Repeat("-", width))
+		} else {
+			fmt.Fprintf(&b, " %s: |", strings.Repeat("-", width-1))
+		}
+	}
+	b.WriteString("\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return b.String()
+}
+
+// HTML formats the matrix as an HTML document with a table under title
+func (m *Matrix) HTML(title string) string {
+	var b strings.Builder
+	title = html.EscapeString(title)
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"><title>%s</title></head>\n<body>\n<h1>%s</h1>\n", title, title)
+	if len(m.Tasks) == 0 {
+		b.WriteString("<p>No time tracked.</p>\n")
+	} else {
+		rows := m.cells()
+		b.WriteString("<table>\n")
+		for i, row := range rows {
+			tag := "td"
+			if i == 0 || i == len(rows)-1 {
+				tag = "th"
+			}
+			b.WriteString("<tr>")
+			for _, cell := range row {
+				fmt.Fprintf(&b, "<%s>%s</%s>", tag, html.EscapeString(cell), tag)
+			}
+			b.WriteString("</tr>\n")
+		}
+		b.WriteString("</table>\n")
+	}
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
@@ -0,0 +1,271 @@
+package talogo
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Sources describing how a record was created
+const (
+	SourceInteractive = "interactive" // Tracked with the log TUI
+	SourceAdd         = "add"         // Entered manually
+	SourceImport      = "import"      // Imported, usually as "import:<format>"
+	SourceAuto        = "auto"        // Created by an automatic rule
+	SourceRecovered   = "recovered"   // Recovered from an interrupted session
+	SourceAPI         = "api"         // Tracked through the serve HTTP API
+	SourceUnknown     = "unknown"     // Logged before sources were recorded
+)
+
+// Record represents a single logged session
+type Record struct {
+	ID     string
+	Line   int // Line number in the log file, 0 if unknown
+	Start  time.Time
+	End    time.Time
+	Titles []string
+	Source string // How the record was created, see the Source constants
+	Tags   []string
+	Notes  string
+	Host   string // Machine the record was tracked on, if configured
+	User   string // User who tracked the record, if configured
+	// Elapsed is the duration stored in the log, 0 if the log has none
+	Elapsed time.Duration
+}
+
+// Duration returns the time spent in the session
+func (r Record) Duration() time.Duration {
+	if r.Elapsed > 0 {
+		return r.Elapsed
+	}
+	return r.End.Sub(r.Start)
+}
+
+// MatchesHost reports whether the record was tracked on any of hosts. An
+// empty hosts list matches every record.
+func (r Record) MatchesHost(hosts []string) bool {
+	if len(hosts) == 0 {
+		return true
+	}
+	for _, host := range hosts {
+		if strings.EqualFold(host, r.Host) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesSource reports whether the record was created by any of sources.
+// A source without a qualifier (e.g. "import") matches all its qualified
+// variants (e.g. "import:ics").
+func (r Record) MatchesSource(sources []string) bool {
+	source := r.Source
+	if source == "" {
+		source = SourceUnknown
+	}
+	kind, _, _ := strings.Cut(source, ":")
+	for _, s := range sources {
+		if s == source || s == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesTask reports whether the record was logged under any of tasks,
+// given as task paths with titles joined by "/" (e.g. "work/lunch"). A
+// task matches all its subtasks.
+func (r Record) MatchesTask(tasks []string) bool {
+	for i := 1; i <= len(r.Titles); i++ {
+		path := strings.Join(r.Titles[:i], "/")
+		for _, task := range tasks {
+			if task == path {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FilterBySource returns the records created by any of sources. An empty
+// sources list returns records unchanged.
+func FilterBySource(records []Record, sources []string) []Record {
+	if len(sources) == 0 {
+		return records
+	}
+	var filtered []Record
+	for _, record := range records {
+		if record.MatchesSource(sources) {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
+
+// DayOf returns the date, in YYYY-MM-DD format, of the day t belongs to
+// when days begin dayStart after midnight
+func DayOf(t time.Time, dayStart time.Duration) string {
+	year, month, day := t.Date()
+	// Compare wall clock times, so the boundary holds on days with a
+	// daylight saving change
+	sinceMidnight := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if sinceMidnight < dayStart {
+		day--
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+}
+
+// SplitByDay splits a record spanning multiple days into one record per
+// day, with days beginning dayStart after midnight. Day boundaries are
+// wall clock times in the zone of the record, so days with a daylight
+// saving change are 23 or 25 hours long. A record that is not split keeps
+// its stored duration.
+func SplitByDay(record Record, dayStart time.Duration) []Record {
+	var records []Record
+	currentStart := record.Start
+	for {
+		date, _ := time.Parse("2006-01-02", DayOf(currentStart, dayStart))
+		year, month, day := date.Date()
+		nextDay := time.Date(year, month, day+1, int(dayStart/time.Hour), int(dayStart%time.Hour/time.Minute), 0, 0, currentStart.Location())
+		endOfDay := nextDay.Add(-time.Nanosecond)
+
+		currentEnd := endOfDay
+		if endOfDay.After(record.End) {
+			currentEnd = record.End
+		}
+
+		dayRecord := record
+		dayRecord.Start = currentStart
+		dayRecord.End = currentEnd
+		dayRecord.Elapsed = 0
+		records = append(records, dayRecord)
+
+		if currentEnd.Equal(record.End) {
+			if len(records) == 1 {
+				records[0].Elapsed = record.Elapsed
+			}
+			break
+		}
+
+		// Move to next day
+		currentStart = endOfDay.Add(time.Nanosecond)
+	}
+	return records
+}
+
+// Coalesce returns records in order of start time, with back-to-back
+// sessions of the same task merged: those starting less than gap after
+// the previous session ended, on the same day for days beginning
+// dayStart after midnight, with the same titles, host and user. Merged
+// records keep the id and source of the first session, the tags of all
+// of them and their distinct notes, and their duration leaves out the
+// gaps.
+func Coalesce(records []Record, gap, dayStart time.Duration) []Record {
+	sorted := make([]Record, len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	var merged []Record
+	for _, record := range sorted {
+		if n := len(merged); n > 0 {
+			prev := merged[n-1]
+			since := record.Start.Sub(prev.End)
+			if since >= 0 && since < gap && DayOf(prev.Start, dayStart) == DayOf(record.Start, dayStart) &&
+				strings.Join(prev.Titles, "/") == strings.Join(record.Titles, "/") &&
+				prev.Host == record.Host && prev.User == record.User {
+				merged[n-1] = mergeRecords(prev, record)
+				continue
+			}
+		}
+		merged = append(merged, record)
+	}
+	return merged
+}
+
+// mergeRecords returns the record of the session a followed by b
+func mergeRecords(a, b Record) Record {
+	merged := a
+	merged.End = b.End
+	merged.Elapsed = a.Duration() + b.Duration()
+	merged.Tags = append([]string{}, a.Tags...)
+	for _, tag := range b.Tags {
+		if !slices.Contains(merged.Tags, tag) {
+			merged.Tags = append(merged.Tags, tag)
+		}
+	}
+	if b.Notes != "" && !slices.Contains(strings.Split(a.Notes, "; "), b.Notes) {
+		if merged.Notes != "" {
+			merged.Notes += "; "
+		}
+		merged.Notes += b.Notes
+	}
+	return merged
+}
+
+// SanitizeTitle returns title on a single line: line breaks, tabs and
+// other control characters become spaces, runs of spaces collapse into
+// one and leading and trailing spaces are removed
+func SanitizeTitle(title string) string {
+	clean := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, title)
+	return strings.Join(strings.Fields(clean), " ")
+}
+
+// NewID returns a new UUIDv7: a random UUID whose first 48 bits are the
+// current Unix time in milliseconds, so IDs sort by creation time
+func NewID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("failed to generate id: %v", err))
+	}
+
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(time.Now().UnixMilli()))
+	copy(b[0:6], ms[2:8])
+
+	b[6] = (b[6] & 0x0f) | 0x70 // Version 7
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// Precision is the resolution of the times and durations written to logs.
+// Logs with either precision can always be read.
+type Precision int
+
+const (
+	// SecondPrecision writes RFC3339 times and whole second durations
+	SecondPrecision Precision = iota
+	// NanosecondPrecision writes RFC3339Nano times and fractional
+	// durations, for very short measurements
+	NanosecondPrecision
+)
+
+// FormatTime formats t as stored in logs. Parsing with time.RFC3339
+// accepts both precisions.
+func (p Precision) FormatTime(t time.Time) string {
+	if p == NanosecondPrecision {
+		return t.Format(time.RFC3339Nano)
+	}
+	return t.Format(time.RFC3339)
+}
+
+// Seconds returns d in seconds as stored in logs, truncated to whole
+// seconds with SecondPrecision
+func (p Precision) Seconds(d time.Duration) float64 {
+	if p == NanosecondPrecision {
+		return d.Seconds()
+	}
+	return float64(int64(d.Seconds()))
+}
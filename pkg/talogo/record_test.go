@@ -0,0 +1,140 @@
+package talogo
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+// newYork returns the America/New_York zone, where 2026 days with a
+// daylight saving change are March 8 (23 hours) and November 1 (25 hours)
+func newYork(t *testing.T) *time.Location {
+	t.Helper()
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return location
+}
+
+func TestDayOfDaylightSaving(t *testing.T) {
+	ny := newYork(t)
+	tests := []struct {
+		name     string
+		t        time.Time
+		dayStart time.Duration
+		want     string
+	}{
+		{"before spring forward", time.Date(2026, 3, 8, 1, 30, 0, 0, ny), 0, "2026-03-08"},
+		{"after spring forward", time.Date(2026, 3, 8, 3, 30, 0, 0, ny), 0, "2026-03-08"},
+		{"spring forward before day start", time.Date(2026, 3, 8, 3, 30, 0, 0, ny), 4 * time.Hour, "2026-03-07"},
+		{"spring forward at day start", time.Date(2026, 3, 8, 4, 0, 0, 0, ny), 4 * time.Hour, "2026-03-08"},
+		{"first 1:30 of fall back", time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC).In(ny), 0, "2026-11-01"},
+		{"first 1:30 of fall back before day start", time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC).In(ny), 4 * time.Hour, "2026-10-31"},
+		{"second 1:30 of fall back before day start", time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC).In(ny), 4 * time.Hour, "2026-10-31"},
+		{"fall back at day start", time.Date(2026, 11, 1, 4, 0, 0, 0, ny), 4 * time.Hour, "2026-11-01"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := DayOf(test.t, test.dayStart); got != test.want {
+				t.Errorf("DayOf(%v, %v) = %s, want %s", test.t, test.dayStart, got, test.want)
+			}
+		})
+	}
+}
+
+func TestSplitByDayDaylightSaving(t *testing.T) {
+	ny := newYork(t)
+	type day struct {
+		date   string
+		length time.Duration
+	}
+	tests := []struct {
+		name       string
+		start, end time.Time
+		dayStart   time.Duration
+		want       []day
+	}{
+		{
+			name:  "spring forward",
+			start: time.Date(2026, 3, 7, 12, 0, 0, 0, ny),
+			end:   time.Date(2026, 3, 9, 12, 0, 0, 0, ny),
+			want:  []day{{"2026-03-07", 12 * time.Hour}, {"2026-03-08", 23 * time.Hour}, {"2026-03-09", 12 * time.Hour}},
+		},
+		{
+			name:     "spring forward with day start",
+			start:    time.Date(2026, 3, 6, 12, 0, 0, 0, ny),
+			end:      time.Date(2026, 3, 8, 12, 0, 0, 0, ny),
+			dayStart: 4 * time.Hour,
+			want:     []day{{"2026-03-06", 16 * time.Hour}, {"2026-03-07", 23 * time.Hour}, {"2026-03-08", 8 * time.Hour}},
+		},
+		{
+			name:  "fall back",
+			start: time.Date(2026, 10, 31, 12, 0, 0, 0, ny),
+			end:   time.Date(2026, 11, 2, 12, 0, 0, 0, ny),
+			want:  []day{{"2026-10-31", 12 * time.Hour}, {"2026-11-01", 25 * time.Hour}, {"2026-11-02", 12 * time.Hour}},
+		},
+		{
+			name:     "fall back with day start",
+			start:    time.Date(2026, 10, 30, 12, 0, 0, 0, ny),
+			end:      time.Date(2026, 11, 1, 12, 0, 0, 0, ny),
+			dayStart: 4 * time.Hour,
+			want:     []day{{"2026-10-30", 16 * time.Hour}, {"2026-10-31", 25 * time.Hour}, {"2026-11-01", 8 * time.Hour}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			record := Record{Start: test.start, End: test.end, Titles: []string{"work"}, Elapsed: test.end.Sub(test.start)}
+			records := SplitByDay(record, test.dayStart)
+			if len(records) != len(test.want) {
+				t.Fatalf("SplitByDay returned %d records, want %d", len(records), len(test.want))
+			}
+			var total time.Duration
+			for i, got := range records {
+				date := DayOf(got.Start, test.dayStart)
+				// Days but the last end a nanosecond before the next begins
+				length := got.Duration().Round(time.Second)
+				if date != test.want[i].date || length != test.want[i].length {
+					t.Errorf("record %d is %v on %s, want %v on %s", i, length, date, test.want[i].length, test.want[i].date)
+				}
+				if got.Elapsed != 0 {
+					t.Errorf("record %d keeps the stored duration %v of the split record", i, got.Elapsed)
+				}
+				total += length
+			}
+			if total != record.Duration() {
+				t.Errorf("split records last %v, want %v", total, record.Duration())
+			}
+		})
+	}
+}
+
+func TestSplitByDayUnsplitKeepsElapsed(t *testing.T) {
+	ny := newYork(t)
+	tests := []struct {
+		name       string
+		start, end time.Time
+		dayStart   time.Duration
+	}{
+		{"across spring forward", time.Date(2026, 3, 8, 1, 0, 0, 0, ny), time.Date(2026, 3, 8, 3, 30, 0, 0, ny), 0},
+		{"across spring forward before day start", time.Date(2026, 3, 8, 1, 0, 0, 0, ny), time.Date(2026, 3, 8, 3, 30, 0, 0, ny), 4 * time.Hour},
+		{"across fall back", time.Date(2026, 11, 1, 0, 30, 0, 0, ny), time.Date(2026, 11, 1, 2, 30, 0, 0, ny), 0},
+		{"across fall back before day start", time.Date(2026, 11, 1, 0, 30, 0, 0, ny), time.Date(2026, 11, 1, 2, 30, 0, 0, ny), 4 * time.Hour},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// A stored duration shorter than the span, as of a paused session
+			record := Record{Start: test.start, End: test.end, Titles: []string{"work"}, Elapsed: 80 * time.Minute}
+			records := SplitByDay(record, test.dayStart)
+			if len(records) != 1 {
+				t.Fatalf("SplitByDay returned %d records, want 1", len(records))
+			}
+			if records[0].Elapsed != record.Elapsed {
+				t.Errorf("Elapsed = %v, want the stored %v", records[0].Elapsed, record.Elapsed)
+			}
+			if !records[0].Start.Equal(record.Start) || !records[0].End.Equal(record.End) {
+				t.Errorf("record moved to %v - %v", records[0].Start, records[0].End)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package talogo
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+)
+
+// Report holds the time tracked in a period, per day and in total
+type Report struct {
+	From     string               // First day, in YYYY-MM-DD format
+	To       string               // Last day, in YYYY-MM-DD format
+	Days     map[string]*TaskNode // date -> day node whose children are the root tasks
+	Total    *TaskNode            // Whole period, children are the root tasks
+	Records  []Record             // Records of the period
+	DayStart time.Duration        // Time after midnight at which days begin
+	Locale   *Locale              // Formats dates and hours, ISO dates if nil
+}
+
+// NewReport aggregates the records whose day is between the from and to
+// dates (inclusive, in YYYY-MM-DD format)
+func NewReport(records []Record, from, to string, opts SummaryOptions) *Report {
+	report := &Report{
+		From:     from,
+		To:       to,
+		Days:     make(map[string]*TaskNode),
+		Total:    NewTaskNode("total"),
+		DayStart: opts.DayStart,
+	}
+	for _, record := range records {
+		if day := DayOf(record.Start, opts.DayStart); day < from || day > to {
+			continue
+		}
+		report.Records = append(report.Records, record)
+		AddToSummary(report.Days, record, opts)
+	}
+	for _, day := range report.Days {
+		report.Total.Merge(day)
+	}
+	return report
+}
+
+// Title returns the heading of the report
+func (r *Report) Title() string {
+	if r.From == r.To {
+		return "Time report " + r.From
+	}
+	return fmt.Sprintf("Time report %s to %s", r.From, r.To)
+}
+
+// Markdown formats the report as Markdown
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", r.Title())
+	if len(r.Records) == 0 {
+		b.WriteString("No time tracked.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "**Total: %s hs**\n\n## Tasks\n\n", r.Locale.Hours(r.Total.TotalTime))
+	r.writeMarkdownTasks(&b, r.Total.Children, 0)
+	b.WriteString("\n## Days\n")
+	for _, date := range SortedDates(r.Days) {
+		fmt.Fprintf(&b, "\n### %s: %s hs\n\n", r.Locale.Date(date), r.Locale.Hours(r.Days[date].TotalTime))
+		r.writeMarkdownTasks(&b, r.Days[date].Children, 0)
+	}
+	return b.String()
+}
+
+// writeMarkdownTasks writes tasks and their subtasks as a nested list
+func (r *Report) writeMarkdownTasks(b *strings.Builder, tasks map[string]*TaskNode, depth int) {
+	for _, name := range SortedTaskNames(tasks) {
+		fmt.Fprintf(b, "%s- %s: %s hs\n", strings.Repeat("  ", depth), name, r.Locale.Hours(tasks[name].TotalTime))
+		r.writeMarkdownTasks(b, tasks[name].Children, depth+1)
+	}
+}
+
+// HTML formats the report as an HTML document
+func (r *Report) HTML() string {
+	var b strings.Builder
+	title := html.EscapeString(r.Title())
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"><title>%s</title></head>\n<body>\n<h1>%s</h1>\n", title, title)
+	if len(r.Records) == 0 {
+		b.WriteString("<p>No time tracked.</p>\n")
+	} else {
+		fmt.Fprintf(&b, "<p><strong>Total: %s hs</strong></p>\n<h2>Tasks</h2>\n", r.Locale.Hours(r.Total.TotalTime))
+		r.writeHTMLTasks(&b, r.Total.Children)
+		b.WriteString("<h2>Days</h2>\n")
+		for _, date := range SortedDates(r.Days) {
+			fmt.Fprintf(&b, "<h3>%s: %s hs</h3>\n", html.EscapeString(r.Locale.Date(date)), r.Locale.Hours(r.Days[date].TotalTime))
+			r.writeHTMLTasks(&b, r.Days[date].Children)
+		}
+	}
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+// writeHTMLTasks writes tasks and their subtasks as nested lists
+func (r *Report) writeHTMLTasks(b *strings.Builder, tasks map[string]*TaskNode) {
+	if len(tasks) == 0 {
+		return
+	}
+	b.WriteString("<ul>\n")
+	for _, name := range SortedTaskNames(tasks) {
+		fmt.Fprintf(b, "<li>%s: %s hs", html.EscapeString(name), r.Locale.Hours(tasks[name].TotalTime))
+		if len(tasks[name].Children) > 0 {
+			b.WriteString("\n")
+			r.writeHTMLTasks(b, tasks[name].Children)
+		}
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</ul>\n")
+}
@@ -0,0 +1,122 @@
+package talogo
+
+import (
+	"sort"
+	"time"
+)
+
+// TaskNode represents a node in the task hierarchy
+type TaskNode struct {
+	Name      string
+	Duration  time.Duration
+	Children  map[string]*TaskNode
+	TotalTime time.Duration // Includes children
+	Earnings  float64       // Includes children
+}
+
+// NewTaskNode returns an empty task node
+func NewTaskNode(name string) *TaskNode {
+	return &TaskNode{
+		Name:     name,
+		Children: make(map[string]*TaskNode),
+	}
+}
+
+// Merge adds the times and earnings of src and its children to n
+func (n *TaskNode) Merge(src *TaskNode) {
+	n.Duration += src.Duration
+	n.TotalTime += src.TotalTime
+	n.Earnings += src.Earnings
+	for name, child := range src.Children {
+		if _, exists := n.Children[name]; !exists {
+			n.Children[name] = NewTaskNode(name)
+		}
+		n.Children[name].Merge(child)
+	}
+}
+
+// SummaryOptions controls how records are aggregated
+type SummaryOptions struct {
+	DayStart time.Duration // Time after midnight at which days begin
+	ByTag    bool          // Aggregate by tag instead of task hierarchy
+	// Rate returns the hourly rate earned by a record. Earnings are not
+	// computed if nil.
+	Rate func(Record) float64
+}
+
+// AddToSummary adds the duration of record to the task hierarchy of its
+// day in days, keyed by date, or to each of its tags when aggregating by
+// tag. The children of each day node are the root tasks.
+func AddToSummary(days map[string]*TaskNode, record Record, opts SummaryOptions) {
+	duration := record.Duration()
+
+	var earnings float64
+	if opts.Rate != nil {
+		earnings = duration.Hours() * opts.Rate(record)
+	}
+
+	// Get date in YYYY-MM-DD format
+	dateStr := DayOf(record.Start, opts.DayStart)
+
+	// Initialize day node
+	if _, exists := days[dateStr]; !exists {
+		days[dateStr] = NewTaskNode(dateStr)
+	}
+	day := days[dateStr]
+	day.TotalTime += duration
+	day.Earnings += earnings
+
+	if opts.ByTag {
+		tags := record.Tags
+		if len(tags) == 0 {
+			tags = []string{"(untagged)"}
+		}
+		for _, tag := range tags {
+			if _, exists := day.Children[tag]; !exists {
+				day.Children[tag] = NewTaskNode(tag)
+			}
+			day.Children[tag].TotalTime += duration
+			day.Children[tag].Duration += duration
+			day.Children[tag].Earnings += earnings
+		}
+		return
+	}
+
+	// Build task hierarchy. Titles logged with line breaks, before they
+	// were sanitized on write, count as their single line version.
+	current := day.Children
+	var leaf *TaskNode
+	for _, taskName := range record.Titles {
+		taskName = SanitizeTitle(taskName)
+		if _, exists := current[taskName]; !exists {
+			current[taskName] = NewTaskNode(taskName)
+		}
+		current[taskName].TotalTime += duration
+		current[taskName].Earnings += earnings
+		leaf = current[taskName]
+		current = current[taskName].Children
+	}
+	if leaf != nil {
+		leaf.Duration += duration
+	}
+}
+
+// SortedDates returns the dates of the summary days in order
+func SortedDates(days map[string]*TaskNode) []string {
+	var dates []string
+	for date := range days {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	return dates
+}
+
+// SortedTaskNames returns the names of tasks in order
+func SortedTaskNames(tasks map[string]*TaskNode) []string {
+	var names []string
+	for name := range tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}